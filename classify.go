@@ -0,0 +1,197 @@
+package erk
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+)
+
+// Classifier converts an error that is not an erk error into an erk error with a meaningful kind.
+// It should return nil if it does not recognize the error.
+//
+// The returned error should wrap the original error (for example, using erk.WrapAs), so errors.Is continues to work.
+type Classifier func(err error) error
+
+type (
+	// ErkNotExist is the kind of errors caused by a file or directory that does not exist.
+	ErkNotExist struct{ DefaultKind }
+
+	// ErkEOF is the kind of errors caused by reaching the end of the input.
+	ErkEOF struct{ DefaultKind }
+
+	// ErkTimeout is the kind of errors caused by a network timeout.
+	ErkTimeout struct{ DefaultKind }
+
+	// ErkNoRows is the kind of errors caused by a database query returning no rows.
+	ErkNoRows struct{ DefaultKind }
+
+	// ErkJSONSyntax is the kind of errors caused by invalid JSON.
+	ErkJSONSyntax struct{ DefaultKind }
+)
+
+// KindStringFor the provided kind.
+func (ErkNotExist) KindStringFor(Kind) string { return "erk:not_exist" }
+
+// KindStringFor the provided kind.
+func (ErkEOF) KindStringFor(Kind) string { return "erk:eof" }
+
+// KindStringFor the provided kind.
+func (ErkTimeout) KindStringFor(Kind) string { return "erk:timeout" }
+
+// KindStringFor the provided kind.
+func (ErkNoRows) KindStringFor(Kind) string { return "erk:no_rows" }
+
+// KindStringFor the provided kind.
+func (ErkJSONSyntax) KindStringFor(Kind) string { return "erk:json_syntax" }
+
+// Errors wrapping well known standard library errors.
+// The message is the message of the wrapped error.
+var (
+	ErrNotExist   = New(ErkNotExist{}, "{{.err}}")
+	ErrEOF        = New(ErkEOF{}, "{{.err}}")
+	ErrTimeout    = New(ErkTimeout{}, "{{.err}}")
+	ErrNoRows     = New(ErkNoRows{}, "{{.err}}")
+	ErrJSONSyntax = New(ErkJSONSyntax{}, "{{.err}}")
+)
+
+//nolint:gochecknoglobals // Registered once, and read when errors are converted
+var classifiers struct {
+	sync.RWMutex
+	custom []Classifier
+}
+
+// Built in classifiers, which run after custom classifiers.
+//
+//nolint:gochecknoglobals // Only read internally
+var builtInClassifiers = []Classifier{
+	classifyContextError,
+	classifyNotExist,
+	classifyEOF,
+	classifyTimeout,
+	classifyNoRows,
+	classifyJSONSyntax,
+}
+
+// RegisterClassifier used by Classify, ToErk, and Export.
+//
+// Custom classifiers run in the order they were registered, before the built in classifiers.
+// Thus, they can override how standard library errors are classified.
+func RegisterClassifier(classifier Classifier) {
+	classifiers.Lock()
+	defer classifiers.Unlock()
+
+	classifiers.custom = append(classifiers.custom, classifier)
+}
+
+// ClearClassifiers removes all registered custom classifiers.
+// The built in classifiers are not removed.
+// This is mostly useful in tests.
+func ClearClassifiers() {
+	classifiers.Lock()
+	defer classifiers.Unlock()
+
+	classifiers.custom = nil
+}
+
+// Classify converts an error into an erk error using the first classifier that recognizes it.
+//
+// The built in classifiers recognize:
+//   - context.Canceled and context.DeadlineExceeded (see FromContextError)
+//   - os.ErrNotExist, with the op and path params for *os.PathError
+//   - io.EOF
+//   - net.Error timeouts, with the op and addr params for *net.OpError
+//   - sql.ErrNoRows
+//   - *json.SyntaxError, with the offset param
+//
+// If err is already an erk.Erkable, or no classifier recognizes it, err is returned unchanged.
+func Classify(err error) error {
+	var e Erkable
+	if errors.As(err, &e) {
+		return err
+	}
+
+	if classified := classify(err); classified != nil {
+		return classified
+	}
+
+	return err
+}
+
+func classify(err error) Erkable {
+	// Classifiers are called without holding the lock, so they can register other classifiers.
+	// Registering only appends, so the classifiers in the copied slice are never modified.
+	classifiers.RLock()
+	custom := classifiers.custom
+	classifiers.RUnlock()
+
+	for _, chain := range [][]Classifier{custom, builtInClassifiers} {
+		for _, classifier := range chain {
+			if classified, ok := classifier(err).(Erkable); ok {
+				return classified
+			}
+		}
+	}
+
+	return nil
+}
+
+func classifyNotExist(err error) error {
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return WrapWith(ErrNotExist, err, Params{"op": pathErr.Op, "path": pathErr.Path})
+	}
+
+	return WrapAs(ErrNotExist, err)
+}
+
+func classifyEOF(err error) error {
+	if !errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return WrapAs(ErrEOF, err)
+}
+
+func classifyTimeout(err error) error {
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		return nil
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		params := Params{"op": opErr.Op}
+		if opErr.Addr != nil {
+			params["addr"] = opErr.Addr.String()
+		}
+
+		return WrapWith(ErrTimeout, err, params)
+	}
+
+	return WrapAs(ErrTimeout, err)
+}
+
+func classifyNoRows(err error) error {
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	return WrapAs(ErrNoRows, err)
+}
+
+func classifyJSONSyntax(err error) error {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return nil
+	}
+
+	return WrapWith(ErrJSONSyntax, err, Params{"offset": syntaxErr.Offset})
+}
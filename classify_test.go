@@ -0,0 +1,198 @@
+package erk_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+)
+
+type testTimeoutError struct{}
+
+func (testTimeoutError) Error() string   { return "i/o timeout" }
+func (testTimeoutError) Timeout() bool   { return true }
+func (testTimeoutError) Temporary() bool { return true }
+
+func TestClassify(t *testing.T) {
+	ensure := ensure.New(t)
+
+	_, openErr := os.Open("/does/not/exist")
+	jsonErr := json.Unmarshal([]byte(`{"a":}`), &struct{}{})
+
+	table := []struct {
+		Name           string
+		Err            error
+		ExpectedKind   erk.Kind
+		ExpectedParams erk.Params
+	}{
+		{
+			Name:           "with os.ErrNotExist",
+			Err:            os.ErrNotExist,
+			ExpectedKind:   erk.ErkNotExist{},
+			ExpectedParams: erk.Params{"err": os.ErrNotExist},
+		},
+		{
+			Name:           "with *os.PathError",
+			Err:            openErr,
+			ExpectedKind:   erk.ErkNotExist{},
+			ExpectedParams: erk.Params{"err": openErr, "op": "open", "path": "/does/not/exist"},
+		},
+		{
+			Name:           "with io.EOF",
+			Err:            io.EOF,
+			ExpectedKind:   erk.ErkEOF{},
+			ExpectedParams: erk.Params{"err": io.EOF},
+		},
+		{
+			Name:           "with net.Error timeout",
+			Err:            testTimeoutError{},
+			ExpectedKind:   erk.ErkTimeout{},
+			ExpectedParams: erk.Params{"err": testTimeoutError{}},
+		},
+		{
+			Name:         "with *net.OpError timeout",
+			Err:          &net.OpError{Op: "dial", Net: "tcp", Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 80}, Err: testTimeoutError{}},
+			ExpectedKind: erk.ErkTimeout{},
+			ExpectedParams: erk.Params{
+				"err":  &net.OpError{Op: "dial", Net: "tcp", Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 80}, Err: testTimeoutError{}},
+				"op":   "dial",
+				"addr": "127.0.0.1:80",
+			},
+		},
+		{
+			Name:           "with sql.ErrNoRows",
+			Err:            sql.ErrNoRows,
+			ExpectedKind:   erk.ErkNoRows{},
+			ExpectedParams: erk.Params{"err": sql.ErrNoRows},
+		},
+		{
+			Name:           "with *json.SyntaxError",
+			Err:            jsonErr,
+			ExpectedKind:   erk.ErkJSONSyntax{},
+			ExpectedParams: erk.Params{"err": jsonErr, "offset": int64(6)},
+		},
+		{
+			Name:           "with unknown error",
+			Err:            errors.New("unknown"),
+			ExpectedKind:   nil,
+			ExpectedParams: nil,
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		err := erk.Classify(entry.Err)
+		ensure(erk.GetKind(err)).Equals(entry.ExpectedKind)
+		ensure(erk.GetParams(err)).Equals(entry.ExpectedParams)
+		ensure(err.Error()).Equals(entry.Err.Error())
+		ensure(errors.Is(err, entry.Err)).IsTrue()
+
+		ensure(erk.GetKind(erk.ToErk(entry.Err))).Equals(entry.ExpectedKind)
+	})
+}
+
+func TestClassifyWrappedError(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with wrapped standard library error", func(ensure ensurepkg.Ensure) {
+		err := erk.Classify(fmt.Errorf("reading: %w", io.EOF))
+		ensure(erk.GetKind(err)).Equals(erk.ErkEOF{})
+		ensure(err.Error()).Equals("reading: EOF")
+		ensure(errors.Is(err, io.EOF)).IsTrue()
+	})
+
+	ensure.Run("with erk error", func(ensure ensurepkg.Ensure) {
+		err := erk.WrapAs(erk.New(ErkExample{}, "my message: {{.err}}"), io.EOF)
+		ensure(erk.Classify(err)).Equals(err)
+	})
+}
+
+func TestRegisterClassifier(t *testing.T) {
+	ensure := ensure.New(t)
+	defer erk.ClearClassifiers()
+
+	errMyNotExist := erk.New(ErkExample{}, "custom: {{.err}}")
+	errUnrelated := errors.New("unrelated")
+
+	erk.RegisterClassifier(func(err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			return erk.WrapAs(errMyNotExist, err)
+		}
+
+		return nil
+	})
+
+	erk.RegisterClassifier(func(err error) error {
+		if err == errUnrelated { //nolint:errorlint // Testing direct comparison
+			return errMyNotExist // Shared error that should not be modified by ToErk
+		}
+
+		return nil
+	})
+
+	ensure.Run("overrides built in classifier", func(ensure ensurepkg.Ensure) {
+		err := erk.Classify(os.ErrNotExist)
+		ensure(erk.GetKind(err)).Equals(ErkExample{})
+		ensure(err.Error()).Equals("custom: file does not exist")
+	})
+
+	ensure.Run("does not modify shared errors", func(ensure ensurepkg.Ensure) {
+		exported := erk.Export(errUnrelated).(*erk.ExportedError)
+		ensure(*exported.Type).Equals("errors:errorString")
+		ensure(erk.Export(errMyNotExist).(*erk.ExportedError).Type).IsNil()
+	})
+
+	ensure.Run("falls back to built in classifiers", func(ensure ensurepkg.Ensure) {
+		ensure(erk.GetKind(erk.Classify(io.EOF))).Equals(erk.ErkEOF{})
+	})
+
+	ensure.Run("with classifier registering another classifier", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearClassifiers()
+
+		errRegister := errors.New("register")
+		erk.RegisterClassifier(func(err error) error {
+			if err == errRegister { //nolint:errorlint // Testing direct comparison
+				erk.RegisterClassifier(func(error) error { return nil })
+			}
+
+			return nil
+		})
+
+		done := make(chan error)
+		go func() { done <- erk.Classify(errRegister) }()
+
+		select {
+		case err := <-done:
+			ensure(err).Equals(errRegister)
+		case <-time.After(time.Second):
+			ensure.Failf("Expected Classify to return, but it deadlocked")
+		}
+	})
+
+	ensure.Run("after clearing", func(ensure ensurepkg.Ensure) {
+		erk.ClearClassifiers()
+		ensure(erk.GetKind(erk.Classify(os.ErrNotExist))).Equals(erk.ErkNotExist{})
+	})
+}
+
+func TestExportClassifiedError(t *testing.T) {
+	ensure := ensure.New(t)
+
+	jsonErr := json.Unmarshal([]byte(`{"a":}`), &struct{}{})
+
+	bytes, err := json.Marshal(erk.Export(jsonErr))
+	ensure(err).IsNotError()
+	ensure(string(bytes)).Equals(
+		`{"kind":"erk:json_syntax","type":"encoding/json:SyntaxError","message":"invalid character '}' looking for beginning of value","params":{"offset":6}}`,
+	)
+}
@@ -0,0 +1,549 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/JosiahWitt/erk"
+)
+
+const (
+	maxLineSize    = 64 << 20
+	maxValueLength = 80
+	noKind         = "(none)"
+)
+
+// ErkAnalyze is the kind of errors returned by the analyze command.
+type ErkAnalyze struct{ erk.DefaultKind }
+
+// Errors returned by the analyze command.
+var (
+	ErrAnalyzeOpen   = erk.New(ErkAnalyze{}, "failed to open {{.path}}: {{.err}}")
+	ErrAnalyzeRead   = erk.New(ErkAnalyze{}, "failed to read {{.path}}: {{.err}}")
+	ErrAnalyzeFormat = erk.New(ErkUsage{}, "unknown format {{.format}}, expected table or json")
+)
+
+// analyzeOptions configure how records are summarized.
+type analyzeOptions struct {
+	format string
+	top    int
+	bucket time.Duration
+}
+
+// record is a line of JSON containing an exported error or exported group.
+//
+// The exported error can also be nested under the error key, as written by erkreport.
+// In that case, the timestamp and fingerprint are read from the outer object.
+type record struct {
+	Kind        *string                `json:"kind"`
+	Message     string                 `json:"message"`
+	Params      map[string]interface{} `json:"params"`
+	Errors      []*record              `json:"errors"`
+	Counts      []int                  `json:"counts"`
+	Timestamp   *time.Time             `json:"timestamp"`
+	Fingerprint string                 `json:"fingerprint"`
+	Error       *record                `json:"error"`
+}
+
+// Summary of the analyzed records.
+type Summary struct {
+	Records   int            `json:"records"`
+	Invalid   int            `json:"invalid"`
+	Kinds     []Count        `json:"kinds"`
+	Messages  []Count        `json:"messages"`
+	Params    []ParamSummary `json:"params"`
+	Groups    []GroupSummary `json:"groups"`
+	Histogram []Bucket       `json:"histogram"`
+}
+
+// Count of occurrences of a key.
+type Count struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// ParamSummary is the distribution of values for a param.
+type ParamSummary struct {
+	Name     string  `json:"name"`
+	Count    int     `json:"count"`
+	Distinct int     `json:"distinct"`
+	Values   []Count `json:"values"`
+}
+
+// GroupSummary breaks down the children of groups with the same kind and raw message.
+type GroupSummary struct {
+	Kind     string  `json:"kind"`
+	Message  string  `json:"message"`
+	Count    int     `json:"count"`
+	Children []Count `json:"children"`
+}
+
+// Bucket of the time histogram.
+type Bucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+func runAnalyze(args []string, s streams) error {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: erk analyze [flags] [files...]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Summarizes JSON lines of exported errors and groups, read from the files or stdin.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	options := analyzeOptions{}
+	fs.StringVar(&options.format, "format", "table", "output format: table or json")
+	fs.IntVar(&options.top, "top", 10, "number of messages and param values to show, or 0 for all")
+	fs.DurationVar(&options.bucket, "bucket", time.Hour, "width of the time histogram buckets")
+
+	if err := parseFlags(fs, args, s); err != nil {
+		return err
+	}
+
+	if options.format != "table" && options.format != "json" {
+		return erk.WithParams(ErrAnalyzeFormat, erk.Params{"format": options.format})
+	}
+
+	a := newAnalyzer()
+
+	if fs.NArg() == 0 {
+		if err := a.read(s.stdin, "stdin"); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range fs.Args() {
+		if err := a.readFile(path); err != nil {
+			return err
+		}
+	}
+
+	summary := a.summarize(options)
+	if options.format == "json" {
+		encoder := json.NewEncoder(s.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summary)
+	}
+
+	return writeSummaryTable(s.stdout, summary)
+}
+
+type groupStats struct {
+	kind     string
+	message  string
+	count    int
+	children map[string]int
+}
+
+type analyzer struct {
+	records   int
+	invalid   int
+	kinds     map[string]int
+	messages  map[string]int    // Keyed by fingerprint, or by raw message. See fingerprintKey.
+	texts     map[string]string // Raw message of the first record with each key in messages
+	params    map[string]map[string]int
+	groups    map[string]*groupStats
+	times     []time.Time
+	groupKeys []string
+}
+
+func newAnalyzer() *analyzer {
+	return &analyzer{
+		kinds:    map[string]int{},
+		messages: map[string]int{},
+		texts:    map[string]string{},
+		params:   map[string]map[string]int{},
+		groups:   map[string]*groupStats{},
+	}
+}
+
+func (a *analyzer) readFile(path string) error {
+	file, err := os.Open(path) //nolint:gosec // Reading user provided files is intended
+	if err != nil {
+		return erk.WrapWith(ErrAnalyzeOpen, err, erk.Params{"path": path})
+	}
+	defer file.Close()
+
+	return a.read(file, path)
+}
+
+func (a *analyzer) read(r io.Reader, path string) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		rec, ok := parseRecord(line)
+		if !ok {
+			a.invalid++
+			continue
+		}
+
+		a.add(rec)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return erk.WrapWith(ErrAnalyzeRead, err, erk.Params{"path": path})
+	}
+
+	return nil
+}
+
+func parseRecord(line []byte) (*record, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	rec := &record{}
+	if err := decoder.Decode(rec); err != nil {
+		return nil, false
+	}
+
+	// Unwrap reports, keeping the outer timestamp and fingerprint
+	if rec.Error != nil {
+		inner := rec.Error
+		if inner.Timestamp == nil {
+			inner.Timestamp = rec.Timestamp
+		}
+
+		if inner.Fingerprint == "" {
+			inner.Fingerprint = rec.Fingerprint
+		}
+
+		rec = inner
+	}
+
+	if rec.Kind == nil && rec.Message == "" {
+		return nil, false
+	}
+
+	return rec, true
+}
+
+func (a *analyzer) add(rec *record) {
+	a.records++
+
+	kind := recordKind(rec)
+	message := rawMessage(rec.Message, rec.Params)
+	key := fingerprintKey(rec, message)
+
+	a.kinds[kind]++
+	a.messages[key]++
+
+	if _, ok := a.texts[key]; !ok {
+		a.texts[key] = message
+	}
+
+	for name, value := range rec.Params {
+		if a.params[name] == nil {
+			a.params[name] = map[string]int{}
+		}
+
+		a.params[name][formatValue(value)]++
+	}
+
+	if rec.Errors != nil {
+		a.addGroup(rec, kind, message)
+	}
+
+	if rec.Timestamp != nil {
+		a.times = append(a.times, *rec.Timestamp)
+	}
+}
+
+func (a *analyzer) addGroup(rec *record, kind, message string) {
+	key := fingerprintKey(rec, kind+"\n"+message)
+
+	stats, ok := a.groups[key]
+	if !ok {
+		stats = &groupStats{kind: kind, message: message, children: map[string]int{}}
+		a.groups[key] = stats
+		a.groupKeys = append(a.groupKeys, key)
+	}
+
+	stats.count++
+
+	for i, child := range rec.Errors {
+		count := 1
+		if i < len(rec.Counts) {
+			count = rec.Counts[i]
+		}
+
+		stats.children[recordKind(child)] += count
+	}
+}
+
+func (a *analyzer) summarize(options analyzeOptions) *Summary {
+	summary := &Summary{
+		Records:   a.records,
+		Invalid:   a.invalid,
+		Kinds:     sortCounts(a.kinds, 0),
+		Messages:  a.sortMessages(options.top),
+		Params:    []ParamSummary{},
+		Groups:    []GroupSummary{},
+		Histogram: buildHistogram(a.times, options.bucket),
+	}
+
+	names := make([]string, 0, len(a.params))
+	for name := range a.params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		values := a.params[name]
+
+		total := 0
+		for _, count := range values {
+			total += count
+		}
+
+		summary.Params = append(summary.Params, ParamSummary{
+			Name:     name,
+			Count:    total,
+			Distinct: len(values),
+			Values:   sortCounts(values, options.top),
+		})
+	}
+
+	for _, key := range a.groupKeys {
+		stats := a.groups[key]
+		summary.Groups = append(summary.Groups, GroupSummary{
+			Kind:     stats.kind,
+			Message:  stats.message,
+			Count:    stats.count,
+			Children: sortCounts(stats.children, 0),
+		})
+	}
+
+	sort.SliceStable(summary.Groups, func(i, j int) bool {
+		return summary.Groups[i].Count > summary.Groups[j].Count
+	})
+
+	return summary
+}
+
+// sortMessages like sortCounts, but with the message of each message key.
+func (a *analyzer) sortMessages(top int) []Count {
+	counts := sortCounts(a.messages, top)
+	for i := range counts {
+		counts[i].Key = a.texts[counts[i].Key]
+	}
+
+	return counts
+}
+
+// fingerprintKey groups records with the same fingerprint, as written by erkreport.
+// Records without a fingerprint use the fallback key, which is based on the raw message inferred from the params (see rawMessage).
+func fingerprintKey(rec *record, fallback string) string {
+	if rec.Fingerprint != "" {
+		return "fingerprint\n" + rec.Fingerprint
+	}
+
+	return fallback
+}
+
+func recordKind(rec *record) string {
+	if rec.Kind == nil || *rec.Kind == "" {
+		return noKind
+	}
+
+	return *rec.Kind
+}
+
+// sortCounts by count descending, then by key.
+// If top is greater than 0, at most top counts are returned.
+func sortCounts(counts map[string]int, top int) []Count {
+	sorted := make([]Count, 0, len(counts))
+	for key, count := range counts {
+		sorted = append(sorted, Count{Key: key, Count: count})
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	if top > 0 && len(sorted) > top {
+		sorted = sorted[:top]
+	}
+
+	return sorted
+}
+
+// maxDenseBuckets limits the number of buckets in the time histogram when including empty buckets.
+// Otherwise, an old timestamp with a narrow bucket width could create millions of empty buckets.
+const maxDenseBuckets = 1000
+
+// buildHistogram counts the times in buckets of the provided width, including empty buckets between the first and last time.
+// If there would be more than maxDenseBuckets buckets, only the buckets containing times are included.
+func buildHistogram(times []time.Time, width time.Duration) []Bucket {
+	if len(times) == 0 || width <= 0 {
+		return []Bucket{}
+	}
+
+	counts := map[int64]int{}
+	first, last := int64(0), int64(0)
+
+	for i, t := range times {
+		start := t.UTC().Truncate(width).UnixNano()
+		counts[start]++
+
+		if i == 0 || start < first {
+			first = start
+		}
+
+		if i == 0 || start > last {
+			last = start
+		}
+	}
+
+	buckets := []Bucket{}
+
+	if (last-first)/int64(width) >= maxDenseBuckets {
+		starts := make([]int64, 0, len(counts))
+		for start := range counts {
+			starts = append(starts, start)
+		}
+		sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+		for _, start := range starts {
+			buckets = append(buckets, Bucket{Start: time.Unix(0, start).UTC(), Count: counts[start]})
+		}
+
+		return buckets
+	}
+
+	for start := first; start <= last; start += int64(width) {
+		buckets = append(buckets, Bucket{Start: time.Unix(0, start).UTC(), Count: counts[start]})
+	}
+
+	return buckets
+}
+
+// formatValue as a string, truncating long values.
+func formatValue(value interface{}) string {
+	var formatted string
+
+	switch v := value.(type) {
+	case string:
+		formatted = v
+	case json.Number:
+		formatted = v.String()
+	case nil:
+		formatted = "null"
+	case bool:
+		formatted = fmt.Sprint(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			formatted = fmt.Sprint(v)
+		} else {
+			formatted = string(data)
+		}
+	}
+
+	if utf8.RuneCountInString(formatted) > maxValueLength {
+		runes := []rune(formatted)
+		formatted = string(runes[:maxValueLength-3]) + "..."
+	}
+
+	return formatted
+}
+
+// rawMessage approximates the message template, by replacing param values in the rendered message with references to the params.
+// Longer values are replaced first, and values are only replaced when they are not part of a larger word.
+func rawMessage(message string, params map[string]interface{}) string {
+	type replacement struct {
+		name  string
+		value string
+	}
+
+	replacements := []replacement{}
+	for name, value := range params {
+		switch value.(type) {
+		case string, json.Number, bool:
+			if formatted := fmt.Sprint(value); formatted != "" {
+				replacements = append(replacements, replacement{name: name, value: formatted})
+			}
+		}
+	}
+
+	sort.Slice(replacements, func(i, j int) bool {
+		if len(replacements[i].value) != len(replacements[j].value) {
+			return len(replacements[i].value) > len(replacements[j].value)
+		}
+
+		return replacements[i].name < replacements[j].name
+	})
+
+	for _, r := range replacements {
+		message = replaceWords(message, r.value, "{{."+r.name+"}}")
+	}
+
+	return message
+}
+
+// replaceWords replaces occurrences of old that are not surrounded by letters or digits.
+func replaceWords(s, old, replacement string) string {
+	var b strings.Builder
+
+	for {
+		i := strings.Index(s, old)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+
+		end := i + len(old)
+		if isWordBoundary(s, i, end) {
+			b.WriteString(s[:i])
+			b.WriteString(replacement)
+		} else {
+			b.WriteString(s[:end])
+		}
+
+		s = s[end:]
+	}
+}
+
+func isWordBoundary(s string, start, end int) bool {
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		if isWordRune(before) {
+			return false
+		}
+	}
+
+	if end < len(s) {
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if isWordRune(after) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
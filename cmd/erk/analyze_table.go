@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+func writeSummaryTable(w io.Writer, summary *Summary) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Records: %d\n", summary.Records)
+	if summary.Invalid > 0 {
+		fmt.Fprintf(tw, "Invalid lines: %d\n", summary.Invalid)
+	}
+
+	writeCounts(tw, "KIND", summary.Kinds)
+	writeCounts(tw, "MESSAGE", summary.Messages)
+
+	for _, param := range summary.Params {
+		fmt.Fprintf(tw, "\nPARAM %s (%d values, %d distinct)\tCOUNT\n", param.Name, param.Count, param.Distinct)
+		for _, value := range param.Values {
+			fmt.Fprintf(tw, "  %s\t%d\n", value.Key, value.Count)
+		}
+	}
+
+	for _, group := range summary.Groups {
+		fmt.Fprintf(tw, "\nGROUP %s: %s (%d groups)\tCOUNT\n", group.Kind, group.Message, group.Count)
+		for _, child := range group.Children {
+			fmt.Fprintf(tw, "  %s\t%d\n", child.Key, child.Count)
+		}
+	}
+
+	if len(summary.Histogram) > 0 {
+		fmt.Fprintf(tw, "\nTIME\tCOUNT\n")
+		for _, bucket := range summary.Histogram {
+			fmt.Fprintf(tw, "%s\t%d\n", bucket.Start.Format(time.RFC3339), bucket.Count)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func writeCounts(w io.Writer, title string, counts []Count) {
+	fmt.Fprintf(w, "\n%s\tCOUNT\n", title)
+	for _, count := range counts {
+		fmt.Fprintf(w, "%s\t%d\n", count.Key, count.Count)
+	}
+}
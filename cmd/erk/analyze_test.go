@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+	"github.com/JosiahWitt/erk/erkreport"
+)
+
+type ErkExample struct{ erk.DefaultKind }
+
+type ErkOther struct{ erk.DefaultKind }
+
+var (
+	errNotFound = erk.New(ErkExample{}, "item {{.key}} was not found in {{.table}}")
+	errFailed   = erk.New(ErkOther{}, "failed to read {{.count}} items")
+)
+
+func TestAnalyze(t *testing.T) {
+	ensure := ensure.New(t)
+
+	lines := []string{
+		marshalLine(ensure, erk.WithParams(errNotFound, erk.Params{"key": "a", "table": "users"})),
+		marshalLine(ensure, erk.WithParams(errNotFound, erk.Params{"key": "b", "table": "users"})),
+		marshalLine(ensure, erk.WithParams(errNotFound, erk.Params{"key": "a", "table": "orders"})),
+		marshalLine(ensure, erg.NewAs(
+			erk.WithParams(errFailed, erk.Params{"count": 3}),
+			erk.WithParams(errNotFound, erk.Params{"key": "c", "table": "users"}),
+			erk.WithParams(errNotFound, erk.Params{"key": "d", "table": "users"}),
+		)),
+		`{"timestamp":"2020-01-02T03:04:05Z","error":{"kind":"my:kind","message":"wrapped"}}`,
+		`{"timestamp":"2020-01-02T05:59:00Z","error":{"kind":"my:kind","message":"wrapped"}}`,
+		`not json`,
+		``,
+	}
+
+	ensure.Run("summarizes records", func(ensure ensurepkg.Ensure) {
+		a := newAnalyzer()
+		ensure(a.read(strings.NewReader(strings.Join(lines, "\n")), "stdin")).IsNotError()
+
+		summary := a.summarize(analyzeOptions{top: 2, bucket: time.Hour})
+		ensure(summary.Records).Equals(6)
+		ensure(summary.Invalid).Equals(1)
+
+		exampleKind := erk.GetKindString(errNotFound)
+		otherKind := erk.GetKindString(errFailed)
+
+		ensure(summary.Kinds).Equals([]Count{
+			{Key: exampleKind, Count: 3},
+			{Key: "my:kind", Count: 2},
+			{Key: otherKind, Count: 1},
+		})
+
+		ensure(summary.Messages).Equals([]Count{
+			{Key: "item {{.key}} was not found in {{.table}}", Count: 3},
+			{Key: "wrapped", Count: 2},
+		})
+
+		ensure(summary.Params).Equals([]ParamSummary{
+			{Name: "count", Count: 1, Distinct: 1, Values: []Count{{Key: "3", Count: 1}}},
+			{Name: "key", Count: 3, Distinct: 2, Values: []Count{{Key: "a", Count: 2}, {Key: "b", Count: 1}}},
+			{Name: "table", Count: 3, Distinct: 2, Values: []Count{{Key: "users", Count: 2}, {Key: "orders", Count: 1}}},
+		})
+
+		ensure(summary.Groups).Equals([]GroupSummary{
+			{
+				Kind:     otherKind,
+				Message:  "failed to read {{.count}} items",
+				Count:    1,
+				Children: []Count{{Key: exampleKind, Count: 2}},
+			},
+		})
+
+		ensure(summary.Histogram).Equals([]Bucket{
+			{Start: time.Date(2020, 1, 2, 3, 0, 0, 0, time.UTC), Count: 1},
+			{Start: time.Date(2020, 1, 2, 4, 0, 0, 0, time.UTC), Count: 0},
+			{Start: time.Date(2020, 1, 2, 5, 0, 0, 0, time.UTC), Count: 1},
+		})
+	})
+
+	ensure.Run("counts children of deduplicated groups", func(ensure ensurepkg.Ensure) {
+		groupErr := erg.Dedupe(erg.NewAs(erk.WithParams(errFailed, erk.Params{"count": 3})), erg.KeyByFingerprint)
+		groupErr = erg.Append(groupErr,
+			erk.WithParams(errNotFound, erk.Params{"key": "c", "table": "users"}),
+			erk.WithParams(errNotFound, erk.Params{"key": "d", "table": "users"}),
+		)
+
+		a := newAnalyzer()
+		ensure(a.read(strings.NewReader(marshalLine(ensure, groupErr)), "stdin")).IsNotError()
+
+		summary := a.summarize(analyzeOptions{bucket: time.Hour})
+		ensure(summary.Groups[0].Children).Equals([]Count{{Key: erk.GetKindString(errNotFound), Count: 2}})
+		ensure(summary.Histogram).Equals([]Bucket{})
+	})
+
+	ensure.Run("groups reports by fingerprint", func(ensure ensurepkg.Ensure) {
+		// The truncated keys are not in the messages, so the raw messages cannot be inferred from the params
+		errTruncated := erk.New(ErkExample{}, "item {{truncate 6 .key}} was not found")
+		errAbc := erk.WithParams(errTruncated, erk.Params{"key": "abcdefgh"})
+		errXyz := erk.WithParams(errTruncated, erk.Params{"key": "xyzwvuts"})
+		errFailedThree := erk.WithParams(errFailed, erk.Params{"count": 3})
+
+		reportLines := []string{
+			marshalLine(ensure, errAbc),
+			marshalReport(ensure, erg.NewAs(errFailedThree, errAbc)),
+			marshalReport(ensure, errAbc),
+			marshalReport(ensure, errXyz),
+			marshalReport(ensure, erg.NewAs(errFailedThree, errXyz)),
+		}
+
+		a := newAnalyzer()
+		ensure(a.read(strings.NewReader(strings.Join(reportLines, "\n")), "stdin")).IsNotError()
+
+		summary := a.summarize(analyzeOptions{bucket: time.Hour})
+		ensure(summary.Messages).Equals([]Count{
+			{Key: "item abc... was not found", Count: 2},
+			{Key: "failed to read {{.count}} items", Count: 2},
+			{Key: "item abc... was not found", Count: 1},
+		})
+
+		ensure(summary.Groups).Equals([]GroupSummary{
+			{
+				Kind:     erk.GetKindString(errFailed),
+				Message:  "failed to read {{.count}} items",
+				Count:    2,
+				Children: []Count{{Key: erk.GetKindString(errNotFound), Count: 2}},
+			},
+		})
+	})
+
+	ensure.Run("with table output", func(ensure ensurepkg.Ensure) {
+		path := filepath.Join(t.TempDir(), "errors.jsonl")
+		ensure(os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600)).IsNotError()
+
+		stdout := &bytes.Buffer{}
+		ensure(runAnalyze([]string{"-top", "1", path}, streams{stdout: stdout, stderr: &bytes.Buffer{}})).IsNotError()
+
+		output := stdout.String()
+		ensure(strings.Contains(output, "Records: 6\n")).IsTrue()
+		ensure(strings.Contains(output, "Invalid lines: 1\n")).IsTrue()
+		ensure(strings.Contains(output, "item {{.key}} was not found in {{.table}}  3\n")).IsTrue()
+		ensure(strings.Contains(output, "PARAM table (3 values, 2 distinct)")).IsTrue()
+		ensure(strings.Contains(output, "2020-01-02T04:00:00Z")).IsTrue()
+	})
+
+	ensure.Run("with JSON output", func(ensure ensurepkg.Ensure) {
+		stdout := &bytes.Buffer{}
+		err := runAnalyze([]string{"-format", "json"}, streams{
+			stdin:  strings.NewReader(strings.Join(lines, "\n")),
+			stdout: stdout,
+			stderr: &bytes.Buffer{},
+		})
+		ensure(err).IsNotError()
+
+		summary := Summary{}
+		ensure(json.Unmarshal(stdout.Bytes(), &summary)).IsNotError()
+		ensure(summary.Records).Equals(6)
+		ensure(len(summary.Messages)).Equals(3)
+	})
+
+	ensure.Run("with unknown format", func(ensure ensurepkg.Ensure) {
+		err := runAnalyze([]string{"-format", "xml"}, streams{stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}})
+		ensure(err).MatchesAllErrors(ErrAnalyzeFormat)
+	})
+
+	ensure.Run("with missing file", func(ensure ensurepkg.Ensure) {
+		err := runAnalyze([]string{filepath.Join(t.TempDir(), "missing")}, streams{stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}})
+		ensure(err).MatchesAllErrors(ErrAnalyzeOpen)
+	})
+}
+
+func TestRawMessage(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name     string
+		Message  string
+		Params   map[string]interface{}
+		Expected string
+	}{
+		{
+			Name:     "without params",
+			Message:  "my message",
+			Expected: "my message",
+		},
+		{
+			Name:     "with string and number params",
+			Message:  "item abc was not found after 10 tries",
+			Params:   map[string]interface{}{"key": "abc", "tries": json.Number("10")},
+			Expected: "item {{.key}} was not found after {{.tries}} tries",
+		},
+		{
+			Name:     "with value inside a word",
+			Message:  "page 1 of 10",
+			Params:   map[string]interface{}{"page": json.Number("1")},
+			Expected: "page {{.page}} of 10",
+		},
+		{
+			Name:     "with overlapping values",
+			Message:  "copy users to users_backup",
+			Params:   map[string]interface{}{"from": "users", "to": "users_backup"},
+			Expected: "copy {{.from}} to {{.to}}",
+		},
+		{
+			Name:     "with object param",
+			Message:  "invalid map[a:1]",
+			Params:   map[string]interface{}{"value": map[string]interface{}{"a": json.Number("1")}},
+			Expected: "invalid map[a:1]",
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+		ensure(rawMessage(entry.Message, entry.Params)).Equals(entry.Expected)
+	})
+}
+
+func TestBuildHistogram(t *testing.T) {
+	ensure := ensure.New(t)
+
+	start := time.Date(2020, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	ensure.Run("includes empty buckets", func(ensure ensurepkg.Ensure) {
+		ensure(buildHistogram([]time.Time{start.Add(2 * time.Minute), start}, time.Minute)).Equals([]Bucket{
+			{Start: start, Count: 1},
+			{Start: start.Add(time.Minute), Count: 0},
+			{Start: start.Add(2 * time.Minute), Count: 1},
+		})
+	})
+
+	ensure.Run("excludes empty buckets when there are too many", func(ensure ensurepkg.Ensure) {
+		end := start.Add(maxDenseBuckets * time.Minute)
+		ensure(buildHistogram([]time.Time{end, start, end}, time.Minute)).Equals([]Bucket{
+			{Start: start, Count: 1},
+			{Start: end, Count: 2},
+		})
+	})
+
+	ensure.Run("without times", func(ensure ensurepkg.Ensure) {
+		ensure(buildHistogram(nil, time.Minute)).Equals([]Bucket{})
+	})
+}
+
+func marshalLine(ensure ensurepkg.Ensure, err error) string {
+	ensure.T().Helper()
+
+	data, marshalErr := json.Marshal(err)
+	ensure(marshalErr).IsNotError()
+
+	return string(data)
+}
+
+func marshalReport(ensure ensurepkg.Ensure, err error) string {
+	ensure.T().Helper()
+
+	report := erkreport.NewReport(err, erkreport.Options{Host: "host", Now: func() time.Time { return time.Time{} }})
+	data, marshalErr := json.Marshal(report)
+	ensure(marshalErr).IsNotError()
+
+	return string(data)
+}
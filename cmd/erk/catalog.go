@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/catalog"
+)
+
+// ErrCatalogFormat is returned when the catalog format is unknown.
+var ErrCatalogFormat = erk.New(ErkUsage{}, "unknown format {{.format}}, expected json, markdown, or csv")
+
+//nolint:gochecknoglobals // Read only
+var catalogWriters = map[string]func(w io.Writer, entries []*catalog.Entry) error{
+	"json":     catalog.WriteJSON,
+	"markdown": catalog.WriteMarkdown,
+	"csv":      catalog.WriteCSV,
+}
+
+func runCatalog(args []string, s streams) error {
+	fs := flag.NewFlagSet("catalog", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: erk catalog [flags] [packages...]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Lists the errors declared as package level variables using erk.New, erk.NewWith, or erg.New.")
+		fmt.Fprintln(fs.Output(), "The packages default to ./...")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	format := fs.String("format", "json", "output format: json, markdown, or csv")
+	dir := fs.String("dir", ".", "directory the packages are loaded from")
+
+	if err := parseFlags(fs, args, s); err != nil {
+		return err
+	}
+
+	write, ok := catalogWriters[*format]
+	if !ok {
+		return erk.WithParams(ErrCatalogFormat, erk.Params{"format": *format})
+	}
+
+	entries, err := loadCatalog(*dir, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	return write(s.stdout, entries)
+}
+
+func loadCatalog(dir string, patterns []string) ([]*catalog.Entry, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	return catalog.Load(dir, patterns...)
+}
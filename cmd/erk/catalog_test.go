@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+)
+
+func TestCatalog(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with markdown format", func(ensure ensurepkg.Ensure) {
+		stdout := &bytes.Buffer{}
+		err := runCatalog([]string{"-format", "markdown", "-dir", "internal/catalog/testdata", "./example"}, streams{stdout: stdout, stderr: &bytes.Buffer{}})
+		ensure(err).IsNotError()
+		ensure(strings.Contains(stdout.String(), "| ErrNotFound |")).IsTrue()
+	})
+
+	ensure.Run("with unknown format", func(ensure ensurepkg.Ensure) {
+		err := runCatalog([]string{"-format", "xml"}, streams{stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}})
+		ensure(err).MatchesAllErrors(ErrCatalogFormat)
+	})
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/snapshot"
+)
+
+// ErkDiff is the kind of errors returned by the diff command.
+type ErkDiff struct{ erk.DefaultKind }
+
+// Errors returned by the diff command.
+var (
+	ErrDiffFormat      = erk.New(ErkUsage{}, "unknown format {{.format}}, expected text or json")
+	ErrDiffOpen        = erk.New(ErkDiff{}, "failed to open {{.path}}: {{.err}}")
+	ErrBreakingChanges = erk.New(ErkDiff{}, "found {{.count}} breaking changes since {{.path}}")
+)
+
+//nolint:gochecknoglobals // Read only
+var diffWriters = map[string]func(w io.Writer, changes []*snapshot.Change) error{
+	"text": snapshot.WriteText,
+	"json": writeChangesJSON,
+}
+
+func runDiff(args []string, s streams) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: erk diff [flags] [packages...]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Compares the errors declared in the packages against a snapshot written by erk snapshot.")
+		fmt.Fprintln(fs.Output(), "Exits with status 1 if there are breaking changes, such as removed errors, kinds, or params.")
+		fmt.Fprintln(fs.Output(), "The packages default to ./...")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	snapshotPath := fs.String("snapshot", DefaultSnapshotPath, "snapshot to compare against")
+	format := fs.String("format", "text", "output format: text or json")
+	dir := fs.String("dir", ".", "directory the packages are loaded from")
+
+	if err := parseFlags(fs, args, s); err != nil {
+		return err
+	}
+
+	write, ok := diffWriters[*format]
+	if !ok {
+		return erk.WithParams(ErrDiffFormat, erk.Params{"format": *format})
+	}
+
+	oldSnapshot, err := readSnapshot(*snapshotPath)
+	if err != nil {
+		return err
+	}
+
+	entries, err := loadCatalog(*dir, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	changes := snapshot.Diff(oldSnapshot, snapshot.New(entries))
+	if err := write(s.stdout, changes); err != nil {
+		return err
+	}
+
+	if breaking := snapshot.CountBreaking(changes); breaking > 0 {
+		return erk.WithParams(ErrBreakingChanges, erk.Params{"count": breaking, "path": *snapshotPath})
+	}
+
+	return nil
+}
+
+func readSnapshot(path string) (*snapshot.Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, erk.WrapWith(ErrDiffOpen, err, erk.Params{"path": path})
+	}
+	defer f.Close()
+
+	return snapshot.Read(f)
+}
+
+func writeChangesJSON(w io.Writer, changes []*snapshot.Change) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(changes)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+)
+
+func TestSnapshotAndDiff(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("without changes", func(ensure ensurepkg.Ensure) {
+		path := filepath.Join(t.TempDir(), "errors.snapshot.json")
+		err := runSnapshot([]string{"-o", path, "-dir", "internal/catalog/testdata", "./example"}, streams{stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}})
+		ensure(err).IsNotError()
+
+		stdout := &bytes.Buffer{}
+		err = runDiff([]string{"-snapshot", path, "-dir", "internal/catalog/testdata", "./example"}, streams{stdout: stdout, stderr: &bytes.Buffer{}})
+		ensure(err).IsNotError()
+		ensure(stdout.String()).Equals("No changes\n")
+	})
+
+	ensure.Run("with breaking changes", func(ensure ensurepkg.Ensure) {
+		stdout := &bytes.Buffer{}
+		err := runSnapshot([]string{"-o", "-", "-dir", "internal/catalog/testdata", "./example"}, streams{stdout: stdout, stderr: &bytes.Buffer{}})
+		ensure(err).IsNotError()
+
+		modified := strings.Replace(stdout.String(), `"value"`, `"old", "value"`, 1)
+		modified = strings.Replace(modified, `"name": "ErrThrottled"`, `"name": "ErrThrottled2"`, 1)
+		path := filepath.Join(t.TempDir(), "errors.snapshot.json")
+		ensure(os.WriteFile(path, []byte(modified), 0o600)).IsNotError()
+
+		stdout.Reset()
+		err = runDiff([]string{"-snapshot", path, "-dir", "internal/catalog/testdata", "./example"}, streams{stdout: stdout, stderr: &bytes.Buffer{}})
+		ensure(err).MatchesAllErrors(ErrBreakingChanges)
+		ensure(exitCodeFor(err)).Equals(1)
+		ensure(strings.Contains(stdout.String(), "/example.ErrCustom no longer references param \"old\"")).IsTrue()
+		ensure(strings.Contains(stdout.String(), "/example.ErrThrottled2 was renamed to ")).IsTrue()
+	})
+
+	ensure.Run("with missing snapshot", func(ensure ensurepkg.Ensure) {
+		err := runDiff([]string{"-snapshot", filepath.Join(t.TempDir(), "missing.json")}, streams{stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}})
+		ensure(err).MatchesAllErrors(ErrDiffOpen)
+	})
+
+	ensure.Run("with unknown format", func(ensure ensurepkg.Ensure) {
+		err := runDiff([]string{"-format", "xml"}, streams{stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}})
+		ensure(err).MatchesAllErrors(ErrDiffFormat)
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/docs"
+)
+
+// ErkDocs is the kind of errors returned by the docs command.
+type ErkDocs struct{ erk.DefaultKind }
+
+// Errors returned by the docs command.
+var (
+	ErrDocsFormat = erk.New(ErkUsage{}, "unknown format {{.format}}, expected markdown or html")
+	ErrDocsWrite  = erk.New(ErkDocs{}, "failed to write {{.path}}: {{.err}}")
+)
+
+//nolint:gochecknoglobals // Read only
+var docsWriters = map[string]func(w io.Writer, doc *docs.Document) error{
+	"markdown": docs.WriteMarkdown,
+	"html":     docs.WriteHTML,
+}
+
+func runDocs(args []string, s streams) error {
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: erk docs [flags] [packages...]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Generates reference documentation for the errors declared in the packages.")
+		fmt.Fprintln(fs.Output(), "The packages default to ./...")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Example go generate directive:")
+		fmt.Fprintln(fs.Output(), "  //go:generate go run github.com/JosiahWitt/erk/cmd/erk docs -o ERRORS.md ./...")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	format := fs.String("format", "markdown", "output format: markdown or html")
+	title := fs.String("title", docs.DefaultTitle, "title of the documentation")
+	output := fs.String("o", "", "file to write the documentation to, instead of stdout")
+	dir := fs.String("dir", ".", "directory the packages are loaded from")
+
+	if err := parseFlags(fs, args, s); err != nil {
+		return err
+	}
+
+	write, ok := docsWriters[*format]
+	if !ok {
+		return erk.WithParams(ErrDocsFormat, erk.Params{"format": *format})
+	}
+
+	entries, err := loadCatalog(*dir, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := write(&b, docs.NewDocument(*title, entries)); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err := s.stdout.Write(b.Bytes())
+		return err
+	}
+
+	if err := os.WriteFile(*output, b.Bytes(), 0o644); err != nil { //nolint:gosec // Documentation is not secret
+		return erk.WrapWith(ErrDocsWrite, err, erk.Params{"path": *output})
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+)
+
+func TestDocs(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("writes to stdout", func(ensure ensurepkg.Ensure) {
+		stdout := &bytes.Buffer{}
+		err := runDocs([]string{"-dir", "internal/catalog/testdata", "./example"}, streams{stdout: stdout, stderr: &bytes.Buffer{}})
+		ensure(err).IsNotError()
+		ensure(strings.Contains(stdout.String(), "### ErrNotFound\n")).IsTrue()
+	})
+
+	ensure.Run("renders placeholder message examples", func(ensure ensurepkg.Ensure) {
+		stdout := &bytes.Buffer{}
+		err := runDocs([]string{"-dir", "internal/catalog/testdata", "./example"}, streams{stdout: stdout, stderr: &bytes.Buffer{}})
+		ensure(err).IsNotError()
+		ensure(strings.Contains(stdout.String(), "Example: `item <key> has {braces}`\n")).IsTrue()
+	})
+
+	ensure.Run("writes to file", func(ensure ensurepkg.Ensure) {
+		path := filepath.Join(t.TempDir(), "errors.html")
+		err := runDocs([]string{"-format", "html", "-title", "Example", "-o", path, "-dir", "internal/catalog/testdata", "./example"}, streams{
+			stdout: &bytes.Buffer{},
+			stderr: &bytes.Buffer{},
+		})
+		ensure(err).IsNotError()
+
+		contents, err := os.ReadFile(path)
+		ensure(err).IsNotError()
+		ensure(strings.Contains(string(contents), "<title>Example</title>")).IsTrue()
+	})
+
+	ensure.Run("with unknown format", func(ensure ensurepkg.Ensure) {
+		err := runDocs([]string{"-format", "pdf"}, streams{stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}})
+		ensure(err).MatchesAllErrors(ErrDocsFormat)
+	})
+}
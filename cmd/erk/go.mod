@@ -0,0 +1,23 @@
+module github.com/JosiahWitt/erk/cmd/erk
+
+// Go 1.25 is required by golang.org/x/tools v0.44.0+, which is the oldest version whose go/packages
+// loads type information from current Go toolchains. The erk library itself supports Go 1.13+.
+go 1.25.0
+
+require (
+	github.com/JosiahWitt/ensure v0.3.10
+	github.com/JosiahWitt/erk v0.5.8
+	golang.org/x/tools v0.45.0
+)
+
+require (
+	github.com/go-test/deep v1.0.7 // indirect
+	github.com/golang/mock v1.5.0 // indirect
+	github.com/kr/pretty v0.2.2-0.20201124222238-a883a8422cd2 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/rogpeppe/go-internal v1.6.1 // indirect
+	golang.org/x/mod v0.36.0 // indirect
+	golang.org/x/sync v0.20.0 // indirect
+)
+
+replace github.com/JosiahWitt/erk => ../..
@@ -0,0 +1,278 @@
+// Package catalog discovers the erk errors declared in Go packages.
+package catalog
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/JosiahWitt/erk"
+	"golang.org/x/tools/go/packages"
+)
+
+// Import paths of the packages that declare errors.
+const (
+	erkPath = "github.com/JosiahWitt/erk"
+	ergPath = "github.com/JosiahWitt/erk/erg"
+)
+
+// ErkCatalog is the kind of errors returned when the catalog cannot be loaded.
+type ErkCatalog struct{ erk.DefaultKind }
+
+// Errors returned when loading the catalog.
+var (
+	ErrLoad         = erk.New(ErkCatalog{}, "failed to load packages: {{.err}}")
+	ErrPackageError = erk.New(ErkCatalog{}, "package {{.package}} has errors: {{.errors}}")
+)
+
+// Entry describes an error declared as a package level variable.
+type Entry struct {
+	// Package is the import path of the package declaring the error.
+	Package string `json:"package"`
+
+	// Name of the variable.
+	Name string `json:"name"`
+
+	// Position of the variable declaration, relative to the loaded directory when possible.
+	Position string `json:"position"`
+
+	// Constructor used to create the error, such as erk.New.
+	Constructor string `json:"constructor"`
+
+	// Group reports if the error is an error group header created by erg.New.
+	Group bool `json:"group"`
+
+	// KindType is the kind's type, such as *github.com/username/package.ErkNotFound.
+	KindType string `json:"kindType"`
+
+	// Kind is the kind string returned by erk.GetKindString.
+	// It is empty if the kind overrides KindStringFor, and the returned value cannot be determined statically.
+	Kind string `json:"kind"`
+
+	// Message is the raw message template.
+	// It is empty if the message is not a constant.
+	Message string `json:"message"`
+
+	// MessageFormat of the message, such as "placeholder", declared by the kind's MessageFormatFor method.
+	// It is empty for the default template format.
+	MessageFormat string `json:"messageFormat,omitempty"`
+
+	// Params referenced by the message template, sorted by name.
+	Params []string `json:"params"`
+
+	// Doc comment of the variable.
+	Doc string `json:"doc,omitempty"`
+
+	// KindDoc is the doc comment of the kind type.
+	KindDoc string `json:"kindDoc,omitempty"`
+
+	// Severity declared by the kind, such as by embedding an erkwarning kind.
+	Severity string `json:"severity,omitempty"`
+
+	// Retryable reports if the kind embeds erkretry.RetryableKind.
+	Retryable bool `json:"retryable,omitempty"`
+
+	// HTTPStatus declared by the kind, or 0 if it is not declared. See resolveHTTPStatus.
+	HTTPStatus int `json:"httpStatus,omitempty"`
+
+	// Problems found while resolving the entry, such as an invalid message template.
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Load the errors declared in the packages matching the patterns, relative to dir.
+// Entries are sorted by package and then by name.
+func Load(dir string, patterns ...string) ([]*Entry, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedImports | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:  dir,
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, erk.WrapAs(ErrLoad, err)
+	}
+
+	loaded := map[string]*packages.Package{}
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, erk.WithParams(ErrPackageError, erk.Params{"package": pkg.PkgPath, "errors": joinPackageErrors(pkg.Errors)})
+		}
+
+		loaded[pkg.PkgPath] = pkg
+	}
+
+	entries := []*Entry{}
+	for _, pkg := range pkgs {
+		entries = append(entries, findEntries(dir, loaded, pkg)...)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Package != entries[j].Package {
+			return entries[i].Package < entries[j].Package
+		}
+
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries, nil
+}
+
+func joinPackageErrors(errs []packages.Error) string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func findEntries(dir string, loaded map[string]*packages.Package, pkg *packages.Package) []*Entry {
+	entries := []*Entry{}
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+
+				for i, name := range valueSpec.Names {
+					if i >= len(valueSpec.Values) || name.Name == "_" {
+						continue
+					}
+
+					entry := buildEntry(loaded, pkg, name, valueSpec.Values[i])
+					if entry != nil {
+						entry.Position = relativePosition(dir, pkg, name)
+						entry.Doc = specDoc(genDecl, valueSpec.Doc)
+						entries = append(entries, entry)
+					}
+				}
+			}
+		}
+	}
+
+	return entries
+}
+
+// buildEntry for the variable, if it is initialized by calling a constructor.
+func buildEntry(loaded map[string]*packages.Package, pkg *packages.Package, name *ast.Ident, value ast.Expr) *Entry {
+	call, ok := ast.Unparen(value).(*ast.CallExpr)
+	if !ok || len(call.Args) < 2 {
+		return nil
+	}
+
+	constructor, isGroup := resolveConstructor(pkg.TypesInfo, call.Fun)
+	if constructor == "" {
+		return nil
+	}
+
+	entry := &Entry{
+		Package:     pkg.PkgPath,
+		Name:        name.Name,
+		Constructor: constructor,
+		Group:       isGroup,
+		Params:      []string{},
+	}
+
+	format := erk.MessageFormatTemplate
+	kindType := pkg.TypesInfo.TypeOf(call.Args[0])
+	if kindType != nil {
+		entry.KindType = kindType.String()
+		entry.Kind = resolveKindString(loaded, kindType)
+		entry.KindDoc = resolveKindDoc(loaded, kindType)
+		entry.Severity = resolveSeverity(loaded, kindType)
+		entry.Retryable = resolveRetryable(kindType)
+		entry.HTTPStatus = resolveHTTPStatus(loaded, kindType)
+
+		var ok bool
+		if format, ok = resolveMessageFormat(loaded, kindType); !ok {
+			entry.Problems = append(entry.Problems, "the message format cannot be determined statically")
+		}
+	}
+
+	if format != erk.MessageFormatTemplate {
+		entry.MessageFormat = format.String()
+	}
+
+	if entry.Kind == "" {
+		entry.Problems = append(entry.Problems, "the kind string cannot be determined statically")
+	}
+
+	messageValue := pkg.TypesInfo.Types[call.Args[1]].Value
+	if messageValue == nil || messageValue.Kind() != constant.String {
+		entry.Problems = append(entry.Problems, "the message is not a constant string")
+		return entry
+	}
+
+	entry.Message = constant.StringVal(messageValue)
+
+	params, err := templateParams(entry.Message, format)
+	if err != nil {
+		entry.Problems = append(entry.Problems, "invalid message template: "+err.Error())
+		return entry
+	}
+
+	entry.Params = params
+	return entry
+}
+
+// specDoc returns the doc comment of the spec, or the doc comment of the declaration if it only has one spec without parentheses.
+func specDoc(genDecl *ast.GenDecl, doc *ast.CommentGroup) string {
+	if doc == nil && !genDecl.Lparen.IsValid() {
+		doc = genDecl.Doc
+	}
+
+	return strings.TrimSpace(doc.Text())
+}
+
+// resolveConstructor returns the name of the constructor called by fun, and if it creates a group.
+// An empty name is returned if fun is not a constructor.
+func resolveConstructor(info *types.Info, fun ast.Expr) (string, bool) {
+	var ident *ast.Ident
+	switch f := ast.Unparen(fun).(type) {
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	case *ast.Ident:
+		ident = f
+	default:
+		return "", false
+	}
+
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return "", false
+	}
+
+	switch {
+	case fn.Pkg().Path() == erkPath && (fn.Name() == "New" || fn.Name() == "NewWith"):
+		return "erk." + fn.Name(), false
+	case fn.Pkg().Path() == ergPath && fn.Name() == "New":
+		return "erg.New", true
+	default:
+		return "", false
+	}
+}
+
+func relativePosition(dir string, pkg *packages.Package, name *ast.Ident) string {
+	position := pkg.Fset.Position(name.Pos())
+
+	filename := position.Filename
+	if absDir, err := filepath.Abs(dir); err == nil {
+		if rel, err := filepath.Rel(absDir, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			filename = rel
+		}
+	}
+
+	position.Filename = filepath.ToSlash(filename)
+	return position.String()
+}
@@ -0,0 +1,211 @@
+package catalog_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/catalog"
+)
+
+const examplePkg = "github.com/JosiahWitt/erk/cmd/erk/internal/catalog/testdata/example"
+
+func loadExample(ensure ensurepkg.Ensure) []*catalog.Entry {
+	ensure.T().Helper()
+
+	entries, err := catalog.Load("testdata", "./example")
+	ensure(err).IsNotError()
+
+	return entries
+}
+
+func TestLoad(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("finds declared errors", func(ensure ensurepkg.Ensure) {
+		entries := loadExample(ensure)
+
+		ensure(entries).Equals([]*catalog.Entry{
+			{
+				Package:     examplePkg,
+				Name:        "ErrCoded",
+				Position:    "example/example.go:80:2",
+				Constructor: "erk.New",
+				KindType:    examplePkg + ".ErkCoded",
+				Kind:        "example:coded",
+				KindDoc:     "ErkCoded is the kind of errors with a code declared using the KindCode method.",
+				Message:     "coded",
+				Params:      []string{},
+			},
+			{
+				Package:     examplePkg,
+				Name:        "ErrCustom",
+				Position:    "example/example.go:46:2",
+				Constructor: "erk.New",
+				KindType:    examplePkg + ".ErkCustom",
+				Kind:        "example:custom",
+				Message:     "custom {{type .value}}",
+				Params:      []string{"value"},
+				Severity:    "critical",
+			},
+			{
+				Package:     examplePkg,
+				Name:        "ErrDynamic",
+				Position:    "example/example.go:47:2",
+				Constructor: "erk.New",
+				KindType:    examplePkg + ".ErkDynamic",
+				Kind:        "",
+				Message:     "dynamic",
+				Params:      []string{},
+				Problems:    []string{"the kind string cannot be determined statically"},
+			},
+			{
+				Package:     examplePkg,
+				Name:        "ErrGroup",
+				Position:    "example/example.go:48:2",
+				Constructor: "erg.New",
+				Group:       true,
+				KindType:    examplePkg + ".ErkNotFound",
+				Kind:        examplePkg + ":ErkNotFound",
+				KindDoc:     "ErkNotFound is the kind of errors for missing items.",
+				Message:     "multiple items were not found",
+				Params:      []string{},
+			},
+			{
+				Package:     examplePkg,
+				Name:        "ErrInvalid",
+				Position:    "example/example.go:49:2",
+				Constructor: "erk.New",
+				KindType:    examplePkg + ".ErkNotFound",
+				Kind:        examplePkg + ":ErkNotFound",
+				KindDoc:     "ErkNotFound is the kind of errors for missing items.",
+				Message:     "invalid {{.key",
+				Params:      []string{},
+				Problems:    []string{`invalid message template: template: message:1: unclosed action`},
+			},
+			{
+				Package:     examplePkg,
+				Name:        "ErrMessage",
+				Position:    "example/example.go:50:2",
+				Constructor: "erk.New",
+				KindType:    examplePkg + ".ErkNotFound",
+				Kind:        examplePkg + ":ErkNotFound",
+				KindDoc:     "ErkNotFound is the kind of errors for missing items.",
+				Message:     "",
+				Params:      []string{},
+				Problems:    []string{"the message is not a constant string"},
+			},
+			{
+				Package:     examplePkg,
+				Name:        "ErrNotFound",
+				Position:    "example/example.go:42:5",
+				Constructor: "erk.New",
+				KindType:    examplePkg + ".ErkNotFound",
+				Kind:        examplePkg + ":ErkNotFound",
+				KindDoc:     "ErkNotFound is the kind of errors for missing items.",
+				Message:     "item {{.key}} was not found in {{.table}}",
+				Params:      []string{"key", "table"},
+				Doc:         "ErrNotFound is returned when an item is not found.",
+			},
+			{
+				Package:       examplePkg,
+				Name:          "ErrPlaceholder",
+				Position:      "example/example.go:90:5",
+				Constructor:   "erk.New",
+				KindType:      examplePkg + ".ErkPlaceholder",
+				Kind:          examplePkg + ":ErkPlaceholder",
+				KindDoc:       "ErkPlaceholder is the kind of errors with placeholder messages.",
+				Message:       "item {key} has {{braces}}",
+				MessageFormat: "placeholder",
+				Params:        []string{"key"},
+			},
+			{
+				Package:     examplePkg,
+				Name:        "ErrPtr",
+				Position:    "example/example.go:45:2",
+				Constructor: "erk.NewWith",
+				KindType:    "*" + examplePkg + ".ErkPtr",
+				Kind:        examplePkg + ":ErkPtr",
+				Message:     "{{if .verbose}}{{range .items}}{{.name}}{{end}}{{end}}{{$.err}}",
+				Params:      []string{"err", "items", "verbose"},
+			},
+			{
+				Package:     examplePkg,
+				Name:        "ErrTagged",
+				Position:    "example/example.go:79:2",
+				Constructor: "erk.New",
+				KindType:    examplePkg + ".ErkTagged",
+				Kind:        "example:tagged",
+				KindDoc:     "ErkTagged is the kind of errors with a code declared using a struct tag.",
+				Message:     "tagged",
+				Params:      []string{},
+			},
+			{
+				Package:     examplePkg,
+				Name:        "ErrThrottled",
+				Position:    "example/example.go:54:2",
+				Constructor: "erk.New",
+				KindType:    examplePkg + ".ErkThrottled",
+				Kind:        examplePkg + ":ErkThrottled",
+				Message:     "request was throttled, retry after {{.retryAfter}}",
+				Params:      []string{"retryAfter"},
+				Doc:         "ErrThrottled is returned when too many requests are made.\nRetry after the duration in the retryAfter param.",
+				KindDoc:     "ErkThrottled is the kind of errors for throttled requests.",
+				Severity:    "warning",
+				Retryable:   true,
+				HTTPStatus:  429,
+			},
+		})
+	})
+
+	ensure.Run("with invalid package", func(ensure ensurepkg.Ensure) {
+		entries, err := catalog.Load("testdata", "./missing")
+		ensure(err).MatchesAllErrors(catalog.ErrPackageError)
+		ensure(entries).IsEmpty()
+	})
+}
+
+func TestWrite(t *testing.T) {
+	ensure := ensure.New(t)
+
+	entries := []*catalog.Entry{
+		{
+			Package:     "example.com/pkg",
+			Name:        "ErrNotFound",
+			Position:    "pkg/errors.go:10:2",
+			Constructor: "erk.New",
+			KindType:    "example.com/pkg.ErkNotFound",
+			Kind:        "example.com/pkg:ErkNotFound",
+			Message:     "item {{.key}} | not found",
+			Params:      []string{"key", "table"},
+		},
+	}
+
+	ensure.Run("JSON", func(ensure ensurepkg.Ensure) {
+		b := &bytes.Buffer{}
+		ensure(catalog.WriteJSON(b, entries)).IsNotError()
+		ensure(strings.Contains(b.String(), `"message": "item {{.key}} | not found"`)).IsTrue()
+		ensure(strings.Contains(b.String(), `"problems"`)).IsFalse()
+	})
+
+	ensure.Run("Markdown", func(ensure ensurepkg.Ensure) {
+		b := &bytes.Buffer{}
+		ensure(catalog.WriteMarkdown(b, entries)).IsNotError()
+		ensure(b.String()).Equals(
+			"| Package | Name | Kind | Message | Params |\n" +
+				"| --- | --- | --- | --- | --- |\n" +
+				"| example.com/pkg | ErrNotFound | `example.com/pkg:ErkNotFound` | `item {{.key}} \\| not found` | key, table |\n",
+		)
+	})
+
+	ensure.Run("CSV", func(ensure ensurepkg.Ensure) {
+		b := &bytes.Buffer{}
+		ensure(catalog.WriteCSV(b, entries)).IsNotError()
+		ensure(b.String()).Equals(
+			"package,name,position,constructor,group,kindType,kind,message,params,problems\n" +
+				"example.com/pkg,ErrNotFound,pkg/errors.go:10:2,erk.New,false,example.com/pkg.ErkNotFound,example.com/pkg:ErkNotFound,item {{.key}} | not found,key;table,\n",
+		)
+	})
+}
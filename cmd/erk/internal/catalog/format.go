@@ -0,0 +1,90 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteJSON writes the entries as an indented JSON array.
+func WriteJSON(w io.Writer, entries []*Entry) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(entries)
+}
+
+// WriteMarkdown writes the entries as a Markdown table.
+func WriteMarkdown(w io.Writer, entries []*Entry) error {
+	if _, err := fmt.Fprintln(w, "| Package | Name | Kind | Message | Params |"); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(w, "| --- | --- | --- | --- | --- |"); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		_, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n",
+			markdownCell(entry.Package),
+			markdownCell(entry.Name),
+			markdownCode(entry.Kind),
+			markdownCode(entry.Message),
+			markdownCell(strings.Join(entry.Params, ", ")),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteCSV writes the entries as CSV with a header row.
+// Params and problems are separated by semicolons.
+func WriteCSV(w io.Writer, entries []*Entry) error {
+	csvWriter := csv.NewWriter(w)
+
+	header := []string{"package", "name", "position", "constructor", "group", "kindType", "kind", "message", "params", "problems"}
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		record := []string{
+			entry.Package,
+			entry.Name,
+			entry.Position,
+			entry.Constructor,
+			fmt.Sprint(entry.Group),
+			entry.KindType,
+			entry.Kind,
+			entry.Message,
+			strings.Join(entry.Params, ";"),
+			strings.Join(entry.Problems, ";"),
+		}
+
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + markdownCell(strings.ReplaceAll(s, "`", "'")) + "`"
+}
@@ -0,0 +1,141 @@
+package catalog
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// resolveKindString returns the kind string erk.GetKindString would return for the kind type.
+//
+// Kinds using the KindStringFor method of erk.DefaultKind or erk.DefaultPtrKind use their stable code,
+// declared with a KindCode method or an erk struct tag, or otherwise the package path and type name,
+// matching how erk builds the default kind string.
+// Codes assigned at runtime using erk.RegisterKindCode cannot be resolved.
+// Kinds overriding KindStringFor or KindCode are resolved if the method only returns a constant string, and is declared in a loaded package.
+// Otherwise, an empty string is returned.
+func resolveKindString(loaded map[string]*packages.Package, kindType types.Type) string {
+	named := namedType(kindType)
+	if named == nil {
+		return ""
+	}
+
+	method := findMethod(kindType, "KindStringFor")
+	if method == nil || isDefaultKindMethod(method) {
+		return defaultKindCode(loaded, named)
+	}
+
+	if value := constantReturn(loaded, method); value != nil && value.Kind() == constant.String {
+		return constant.StringVal(value)
+	}
+
+	return ""
+}
+
+// kindCodeTag matches erk.KindCodeTag.
+const kindCodeTag = "erk"
+
+func namedType(t types.Type) *types.Named {
+	for {
+		switch typ := t.(type) {
+		case *types.Pointer:
+			t = typ.Elem()
+		case *types.Named:
+			return typ
+		default:
+			return nil
+		}
+	}
+}
+
+// defaultKindCode matches the kind string built by erk.DefaultKind, including stable codes.
+func defaultKindCode(loaded map[string]*packages.Package, named *types.Named) string {
+	if method := findMethod(named, "KindCode"); method != nil {
+		if value := constantReturn(loaded, method); value != nil && value.Kind() == constant.String {
+			return constant.StringVal(value)
+		}
+
+		return ""
+	}
+
+	// Like erk, only tags on fields declared directly in the kind's struct are used
+	if st, ok := named.Underlying().(*types.Struct); ok {
+		for i := 0; i < st.NumFields(); i++ {
+			if code := reflect.StructTag(st.Tag(i)).Get(kindCodeTag); code != "" {
+				return code
+			}
+		}
+	}
+
+	return defaultKindString(named)
+}
+
+// defaultKindString matches the kind string built by erk.DefaultKind.
+func defaultKindString(named *types.Named) string {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return ":" + obj.Name()
+	}
+
+	return obj.Pkg().Path() + ":" + obj.Name()
+}
+
+func findMethod(t types.Type, name string) *types.Func {
+	// Include the pointer method set, since a kind's value can be a pointer
+	methodType := t
+	if _, ok := t.(*types.Pointer); !ok {
+		methodType = types.NewPointer(t)
+	}
+
+	selection := types.NewMethodSet(methodType).Lookup(nil, name)
+	if selection == nil {
+		return nil
+	}
+
+	fn, _ := selection.Obj().(*types.Func)
+	return fn
+}
+
+func isDefaultKindMethod(method *types.Func) bool {
+	recv := receiverType(method)
+	if recv == nil || recv.Obj().Pkg() == nil {
+		return false
+	}
+
+	name := recv.Obj().Name()
+	return recv.Obj().Pkg().Path() == erkPath && (name == "DefaultKind" || name == "DefaultPtrKind")
+}
+
+// constantReturn returns the constant returned by the method, if its body is a single return statement.
+// The method must be declared in one of the loaded packages.
+func constantReturn(loaded map[string]*packages.Package, method *types.Func) constant.Value {
+	declPkg := loaded[method.Pkg().Path()]
+	if declPkg == nil {
+		return nil
+	}
+
+	for _, file := range declPkg.Syntax {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || declPkg.TypesInfo.Defs[funcDecl.Name] != method {
+				continue
+			}
+
+			if funcDecl.Body == nil || len(funcDecl.Body.List) != 1 {
+				return nil
+			}
+
+			ret, ok := funcDecl.Body.List[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				return nil
+			}
+
+			return declPkg.TypesInfo.Types[ret.Results[0]].Value
+		}
+	}
+
+	return nil
+}
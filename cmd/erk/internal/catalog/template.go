@@ -0,0 +1,23 @@
+package catalog
+
+import (
+	"errors"
+
+	"github.com/JosiahWitt/erk"
+)
+
+// templateParams returns the sorted names of the params referenced by the message, using the message format.
+// See erk.ParseMessage.
+func templateParams(message string, format erk.MessageFormat) ([]string, error) {
+	info, err := erk.ParseMessage(message, format)
+	if err != nil {
+		// Only return the parse error, since the caller already describes the message template
+		if parseErr := errors.Unwrap(err); parseErr != nil {
+			return nil, parseErr
+		}
+
+		return nil, err //nolint:wrapcheck // Already an erk error
+	}
+
+	return info.Params, nil
+}
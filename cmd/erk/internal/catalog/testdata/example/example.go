@@ -0,0 +1,90 @@
+// Package example declares errors for testing the catalog.
+package example
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/JosiahWitt/erk"
+	e "github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+	"github.com/JosiahWitt/erk/erkretry"
+	"github.com/JosiahWitt/erk/erkwarning"
+)
+
+type (
+	// ErkNotFound is the kind of errors for missing items.
+	ErkNotFound struct{ erk.DefaultKind }
+
+	ErkPtr     struct{ erk.DefaultPtrKind }
+	ErkCustom  struct{ erk.DefaultKind }
+	ErkDynamic struct{ erk.DefaultKind }
+)
+
+// ErkThrottled is the kind of errors for throttled requests.
+type ErkThrottled struct {
+	erk.DefaultKind
+	erkretry.RetryableKind
+	erkwarning.WarningKind
+}
+
+func (ErkCustom) KindStringFor(erk.Kind) string { return "example:custom" }
+
+func (ErkCustom) SeverityStringFor(erk.Kind) string { return "critical" }
+
+func (ErkDynamic) KindStringFor(erk.Kind) string { return fmt.Sprint("example:", "dynamic") }
+
+func (ErkThrottled) HTTPStatus() int { return http.StatusTooManyRequests }
+
+const prefix = "item"
+
+// ErrNotFound is returned when an item is not found.
+var ErrNotFound = erk.New(ErkNotFound{}, prefix+" {{.key}} was not found in {{.table}}")
+
+var (
+	ErrPtr     = erk.NewWith(&ErkPtr{}, "{{if .verbose}}{{range .items}}{{.name}}{{end}}{{end}}{{$.err}}", erk.Params{"verbose": true})
+	ErrCustom  = e.New(ErkCustom{}, "custom {{type .value}}")
+	ErrDynamic = erk.New(ErkDynamic{}, "dynamic")
+	ErrGroup   = erg.New(ErkNotFound{}, "multiple items were not found")
+	ErrInvalid = erk.New(ErkNotFound{}, "invalid {{.key")
+	ErrMessage = erk.New(ErkNotFound{}, fmt.Sprint("not constant"))
+
+	// ErrThrottled is returned when too many requests are made.
+	// Retry after the duration in the retryAfter param.
+	ErrThrottled = erk.New(ErkThrottled{}, "request was throttled, retry after {{.retryAfter}}")
+
+	errNotDeclared = fmt.Errorf("not an erk error")
+	_              = erk.New(ErkNotFound{}, "ignored")
+)
+
+func init() {
+	_ = errNotDeclared
+}
+
+func notPackageLevel() error {
+	return erk.New(ErkNotFound{}, "not package level")
+}
+
+// ErkTagged is the kind of errors with a code declared using a struct tag.
+type ErkTagged struct {
+	erk.DefaultKind `erk:"example:tagged"`
+}
+
+// ErkCoded is the kind of errors with a code declared using the KindCode method.
+type ErkCoded struct{ erk.DefaultKind }
+
+func (ErkCoded) KindCode() string { return "example:coded" }
+
+var (
+	ErrTagged = erk.New(ErkTagged{}, "tagged")
+	ErrCoded  = erk.New(ErkCoded{}, "coded")
+)
+
+// ErkPlaceholder is the kind of errors with placeholder messages.
+type ErkPlaceholder struct{ erk.DefaultKind }
+
+func (ErkPlaceholder) MessageFormatFor(erk.Kind) erk.MessageFormat {
+	return erk.MessageFormatPlaceholder
+}
+
+var ErrPlaceholder = erk.New(ErkPlaceholder{}, "item {key} has {{braces}}")
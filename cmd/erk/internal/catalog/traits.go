@@ -0,0 +1,127 @@
+package catalog
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"github.com/JosiahWitt/erk"
+	"golang.org/x/tools/go/packages"
+)
+
+// Import paths of the packages declaring kinds that can be embedded.
+const (
+	erkwarningPath = "github.com/JosiahWitt/erk/erkwarning"
+	erkretryPath   = "github.com/JosiahWitt/erk/erkretry"
+)
+
+// httpStatusMethods are the method names checked for an HTTP status, in order.
+//
+//nolint:gochecknoglobals // Read only
+var httpStatusMethods = []string{"HTTPStatusFor", "HTTPStatus", "StatusCodeFor", "StatusCode"}
+
+// resolveKindDoc returns the doc comment of the kind type, if it is declared in a loaded package.
+func resolveKindDoc(loaded map[string]*packages.Package, kindType types.Type) string {
+	named := namedType(kindType)
+	if named == nil || named.Obj().Pkg() == nil {
+		return ""
+	}
+
+	declPkg := loaded[named.Obj().Pkg().Path()]
+	if declPkg == nil {
+		return ""
+	}
+
+	for _, file := range declPkg.Syntax {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if ok && declPkg.TypesInfo.Defs[typeSpec.Name] == named.Obj() {
+					return specDoc(genDecl, typeSpec.Doc)
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// resolveSeverity returns the severity declared by the kind.
+// The severity kinds in erkwarning are recognized, and other kinds are resolved if SeverityStringFor returns a constant string.
+func resolveSeverity(loaded map[string]*packages.Package, kindType types.Type) string {
+	method := findMethod(kindType, "SeverityStringFor")
+	if method == nil {
+		return ""
+	}
+
+	if recv := receiverType(method); recv != nil && recv.Obj().Pkg() != nil && recv.Obj().Pkg().Path() == erkwarningPath {
+		return strings.ToLower(strings.TrimSuffix(recv.Obj().Name(), "Kind"))
+	}
+
+	if value := constantReturn(loaded, method); value != nil && value.Kind() == constant.String {
+		return constant.StringVal(value)
+	}
+
+	return ""
+}
+
+// resolveRetryable reports if the kind embeds erkretry.RetryableKind.
+func resolveRetryable(kindType types.Type) bool {
+	method := findMethod(kindType, "RetryHintsFor")
+	if method == nil {
+		return false
+	}
+
+	recv := receiverType(method)
+	return recv != nil && recv.Obj().Pkg() != nil && recv.Obj().Pkg().Path() == erkretryPath && recv.Obj().Name() == "RetryableKind"
+}
+
+// resolveHTTPStatus returns the HTTP status declared by the kind, if one of the httpStatusMethods returns a constant integer.
+func resolveHTTPStatus(loaded map[string]*packages.Package, kindType types.Type) int {
+	for _, name := range httpStatusMethods {
+		method := findMethod(kindType, name)
+		if method == nil {
+			continue
+		}
+
+		if value := constantReturn(loaded, method); value != nil && value.Kind() == constant.Int {
+			if status, ok := constant.Int64Val(value); ok {
+				return int(status)
+			}
+		}
+	}
+
+	return 0
+}
+
+// resolveMessageFormat returns the message format declared by the kind, and if it could be determined.
+// Kinds overriding MessageFormatFor are resolved if the method only returns a constant, and is declared in a loaded package.
+func resolveMessageFormat(loaded map[string]*packages.Package, kindType types.Type) (erk.MessageFormat, bool) {
+	method := findMethod(kindType, "MessageFormatFor")
+	if method == nil || isDefaultKindMethod(method) {
+		return erk.MessageFormatTemplate, true
+	}
+
+	if value := constantReturn(loaded, method); value != nil && value.Kind() == constant.Int {
+		if format, ok := constant.Int64Val(value); ok {
+			return erk.MessageFormat(format), true
+		}
+	}
+
+	return erk.MessageFormatTemplate, false
+}
+
+func receiverType(method *types.Func) *types.Named {
+	signature, ok := method.Type().(*types.Signature)
+	if !ok || signature.Recv() == nil {
+		return nil
+	}
+
+	return namedType(signature.Recv().Type())
+}
@@ -0,0 +1,161 @@
+// Package docs renders reference documentation for the errors in a catalog.
+package docs
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/catalog"
+)
+
+// DefaultTitle of the generated documentation.
+const DefaultTitle = "Error Reference"
+
+// Package of errors in the documentation.
+type Package struct {
+	Path   string
+	Errors []*Error
+}
+
+// Error in the documentation.
+type Error struct {
+	*catalog.Entry
+
+	// Example is the message rendered with a placeholder for each param, such as <key>.
+	// It is empty if the message cannot be rendered.
+	Example string
+}
+
+// Document containing the errors, grouped by package.
+type Document struct {
+	Title    string
+	Packages []*Package
+}
+
+// NewDocument from the catalog entries.
+// Packages are sorted by path, and the errors in each package keep the order of the entries.
+func NewDocument(title string, entries []*catalog.Entry) *Document {
+	if title == "" {
+		title = DefaultTitle
+	}
+
+	doc := &Document{Title: title}
+	byPath := map[string]*Package{}
+
+	for _, entry := range entries {
+		pkg, ok := byPath[entry.Package]
+		if !ok {
+			pkg = &Package{Path: entry.Package}
+			byPath[entry.Package] = pkg
+			doc.Packages = append(doc.Packages, pkg)
+		}
+
+		pkg.Errors = append(pkg.Errors, &Error{Entry: entry, Example: RenderExample(entry.Message, entry.MessageFormat, entry.Params)})
+	}
+
+	sort.SliceStable(doc.Packages, func(i, j int) bool {
+		return doc.Packages[i].Path < doc.Packages[j].Path
+	})
+
+	return doc
+}
+
+// RenderExample renders the message with a placeholder for each param, such as <key>.
+// The format is the message format of the catalog entry, where an empty format is a text/template message.
+// The template functions of erk.DefaultKind are available to text/template messages.
+// An empty string is returned if the message cannot be rendered, for example if it uses custom template functions.
+func RenderExample(message, format string, params []string) string {
+	if format == erk.MessageFormatPlaceholder.String() {
+		return renderPlaceholderExample(message)
+	}
+
+	tmpl, err := texttemplate.New("example").Funcs(erk.DefaultKind{}.TemplateFuncsFor(nil)).Parse(message)
+	if err != nil {
+		return ""
+	}
+
+	placeholders := make(map[string]interface{}, len(params))
+	for _, param := range params {
+		placeholders[param] = "<" + param + ">"
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, placeholders); err != nil {
+		return ""
+	}
+
+	return b.String()
+}
+
+// renderPlaceholderExample renders a placeholder message, replacing each {key} with <key>, and "{{" and "}}" with literal braces.
+func renderPlaceholderExample(message string) string {
+	if _, err := erk.ParseMessage(message, erk.MessageFormatPlaceholder); err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(message); i++ {
+		switch {
+		case strings.HasPrefix(message[i:], "{{"), strings.HasPrefix(message[i:], "}}"):
+			b.WriteByte(message[i])
+			i++
+		case message[i] == '{':
+			end := i + strings.IndexByte(message[i:], '}')
+			b.WriteString("<" + strings.TrimSpace(message[i+1:end]) + ">")
+			i = end
+		default:
+			b.WriteByte(message[i])
+		}
+	}
+
+	return b.String()
+}
+
+// WriteMarkdown writes the document as Markdown.
+func WriteMarkdown(w io.Writer, doc *Document) error {
+	return markdownTemplate.Execute(w, doc)
+}
+
+// WriteHTML writes the document as a static HTML page.
+func WriteHTML(w io.Writer, doc *Document) error {
+	return htmlTemplate.Execute(w, doc)
+}
+
+//nolint:gochecknoglobals // Read only
+var funcs = template.FuncMap{
+	"code":   markdownCode,
+	"params": formatParams,
+	"anchor": anchor,
+}
+
+func markdownCode(s string) string {
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+
+	return fence + s + fence
+}
+
+func formatParams(params []string) string {
+	codes := make([]string, 0, len(params))
+	for _, param := range params {
+		codes = append(codes, markdownCode(param))
+	}
+
+	return strings.Join(codes, ", ")
+}
+
+// anchor returns an HTML id for the error.
+func anchor(pkg, name string) string {
+	replacer := strings.NewReplacer("/", "-", ".", "-", ":", "-")
+	return strings.ToLower(replacer.Replace(pkg + "-" + name))
+}
@@ -0,0 +1,165 @@
+package docs_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/catalog"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/docs"
+)
+
+func exampleEntries() []*catalog.Entry {
+	return []*catalog.Entry{
+		{
+			Package:    "example.com/b",
+			Name:       "ErrThrottled",
+			Kind:       "example.com/b:ErkThrottled",
+			Message:    "throttled, retry after {{.retryAfter}}",
+			Params:     []string{"retryAfter"},
+			Doc:        "ErrThrottled is returned when too many requests are made.",
+			KindDoc:    "ErkThrottled is the kind of throttling errors.",
+			Severity:   "warning",
+			Retryable:  true,
+			HTTPStatus: 429,
+		},
+		{
+			Package: "example.com/a",
+			Name:    "ErrNotFound",
+			Kind:    "example.com/a:ErkNotFound",
+			Message: "item {{.key}} <not> found",
+			Params:  []string{"key"},
+		},
+		{
+			Package: "example.com/a",
+			Name:    "ErrGroup",
+			Group:   true,
+			Message: "multiple {{custom .a}}",
+			Params:  []string{"a"},
+		},
+	}
+}
+
+func TestNewDocument(t *testing.T) {
+	ensure := ensure.New(t)
+
+	doc := docs.NewDocument("", exampleEntries())
+	ensure(doc.Title).Equals(docs.DefaultTitle)
+	ensure(len(doc.Packages)).Equals(2)
+	ensure(doc.Packages[0].Path).Equals("example.com/a")
+	ensure(doc.Packages[0].Errors[0].Name).Equals("ErrNotFound")
+	ensure(doc.Packages[0].Errors[0].Example).Equals("item <key> <not> found")
+	ensure(doc.Packages[0].Errors[1].Example).Equals("")
+	ensure(doc.Packages[1].Path).Equals("example.com/b")
+}
+
+func TestRenderExample(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name     string
+		Message  string
+		Format   string
+		Params   []string
+		Expected string
+	}{
+		{Name: "without params", Message: "my message", Expected: "my message"},
+		{Name: "with params", Message: "{{.a}} and {{.b}}", Params: []string{"a", "b"}, Expected: "<a> and <b>"},
+		{Name: "with erk template funcs", Message: "{{type .a}}", Params: []string{"a"}, Expected: "string"},
+		{Name: "with unknown template funcs", Message: "{{custom .a}}", Params: []string{"a"}, Expected: ""},
+		{Name: "with invalid template", Message: "{{.a", Params: []string{"a"}, Expected: ""},
+		{Name: "with execution error", Message: "{{index .a 5}}", Params: []string{"a"}, Expected: ""},
+		{Name: "with placeholders", Message: "item { key } has {{braces}}", Format: "placeholder", Params: []string{"key"}, Expected: "item <key> has {braces}"},
+		{Name: "with placeholders and template actions", Message: "{{.a}}", Format: "placeholder", Expected: "{.a}"},
+		{Name: "with invalid placeholder", Message: "item {key", Format: "placeholder", Params: []string{"key"}, Expected: ""},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+		ensure(docs.RenderExample(entry.Message, entry.Format, entry.Params)).Equals(entry.Expected)
+	})
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	ensure := ensure.New(t)
+
+	b := &bytes.Buffer{}
+	ensure(docs.WriteMarkdown(b, docs.NewDocument("My Errors", exampleEntries()))).IsNotError()
+
+	ensure(b.String()).Equals("<!-- Code generated by erk docs. DO NOT EDIT. -->\n" +
+		"\n" +
+		"# My Errors\n" +
+		"\n" +
+		"## `example.com/a`\n" +
+		"\n" +
+		"### ErrNotFound\n" +
+		"\n" +
+		"| | |\n" +
+		"| --- | --- |\n" +
+		"| Kind | `example.com/a:ErkNotFound` |\n" +
+		"| Params | `key` |\n" +
+		"\n" +
+		"Message template:\n" +
+		"\n" +
+		"```\n" +
+		"item {{.key}} <not> found\n" +
+		"```\n" +
+		"\n" +
+		"Example: `item <key> <not> found`\n" +
+		"\n" +
+		"### ErrGroup\n" +
+		"\n" +
+		"| | |\n" +
+		"| --- | --- |\n" +
+		"| Kind | _unknown_ |\n" +
+		"| Group | yes |\n" +
+		"| Params | `a` |\n" +
+		"\n" +
+		"Message template:\n" +
+		"\n" +
+		"```\n" +
+		"multiple {{custom .a}}\n" +
+		"```\n" +
+		"\n" +
+		"## `example.com/b`\n" +
+		"\n" +
+		"### ErrThrottled\n" +
+		"\n" +
+		"ErrThrottled is returned when too many requests are made.\n" +
+		"\n" +
+		"| | |\n" +
+		"| --- | --- |\n" +
+		"| Kind | `example.com/b:ErkThrottled` |\n" +
+		"| Severity | warning |\n" +
+		"| Retryable | yes |\n" +
+		"| HTTP Status | 429 |\n" +
+		"| Params | `retryAfter` |\n" +
+		"\n" +
+		"ErkThrottled is the kind of throttling errors.\n" +
+		"\n" +
+		"Message template:\n" +
+		"\n" +
+		"```\n" +
+		"throttled, retry after {{.retryAfter}}\n" +
+		"```\n" +
+		"\n" +
+		"Example: `throttled, retry after <retryAfter>`\n",
+	)
+}
+
+func TestWriteHTML(t *testing.T) {
+	ensure := ensure.New(t)
+
+	b := &bytes.Buffer{}
+	ensure(docs.WriteHTML(b, docs.NewDocument("My Errors", exampleEntries()))).IsNotError()
+
+	html := b.String()
+	ensure(strings.HasPrefix(html, "<!DOCTYPE html>")).IsTrue()
+	ensure(strings.Contains(html, "<title>My Errors</title>")).IsTrue()
+	ensure(strings.Contains(html, `<a href="#example-com-a-errnotfound">ErrNotFound</a>`)).IsTrue()
+	ensure(strings.Contains(html, `<h3 id="example-com-a-errnotfound">ErrNotFound</h3>`)).IsTrue()
+	ensure(strings.Contains(html, "<pre>item {{.key}} &lt;not&gt; found</pre>")).IsTrue()
+	ensure(strings.Contains(html, "<tr><th>HTTP Status</th><td>429</td></tr>")).IsTrue()
+}
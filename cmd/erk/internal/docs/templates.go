@@ -0,0 +1,125 @@
+package docs
+
+import (
+	"html/template"
+	texttemplate "text/template"
+)
+
+//nolint:gochecknoglobals // Parsed once
+var markdownTemplate = texttemplate.Must(texttemplate.New("markdown").Funcs(texttemplate.FuncMap(funcs)).Parse(
+	`<!-- Code generated by erk docs. DO NOT EDIT. -->
+
+# {{.Title}}
+{{range .Packages}}
+## {{code .Path}}
+{{range .Errors}}
+### {{.Name}}
+{{- if .Doc}}
+
+{{.Doc}}
+{{- end}}
+
+| | |
+| --- | --- |
+| Kind | {{if .Kind}}{{code .Kind}}{{else}}_unknown_{{end}} |
+{{- if .Group}}
+| Group | yes |
+{{- end}}
+{{- if .Severity}}
+| Severity | {{.Severity}} |
+{{- end}}
+{{- if .Retryable}}
+| Retryable | yes |
+{{- end}}
+{{- if .HTTPStatus}}
+| HTTP Status | {{.HTTPStatus}} |
+{{- end}}
+{{- if .Params}}
+| Params | {{params .Params}} |
+{{- end}}
+{{- if .KindDoc}}
+
+{{.KindDoc}}
+{{- end}}
+{{- if .Message}}
+
+Message template:
+
+` + "```" + `
+{{.Message}}
+` + "```" + `
+{{- end}}
+{{- if .Example}}
+
+Example: {{code .Example}}
+{{- end}}
+{{end}}{{end}}`,
+))
+
+//nolint:gochecknoglobals // Parsed once
+var htmlTemplate = template.Must(template.New("html").Funcs(funcs).Parse(`<!DOCTYPE html>
+<!-- Code generated by erk docs. DO NOT EDIT. -->
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 0 auto; padding: 1em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; vertical-align: top; }
+pre { background: #f5f5f5; padding: 0.5em; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<ul>
+{{- range $pkg := .Packages}}
+<li><code>{{$pkg.Path}}</code>
+<ul>
+{{- range $pkg.Errors}}
+<li><a href="#{{anchor $pkg.Path .Name}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+</li>
+{{- end}}
+</ul>
+{{- range $pkg := .Packages}}
+<h2><code>{{$pkg.Path}}</code></h2>
+{{- range $pkg.Errors}}
+<h3 id="{{anchor $pkg.Path .Name}}">{{.Name}}</h3>
+{{- if .Doc}}
+<p>{{.Doc}}</p>
+{{- end}}
+<table>
+<tr><th>Kind</th><td>{{if .Kind}}<code>{{.Kind}}</code>{{else}}<em>unknown</em>{{end}}</td></tr>
+{{- if .Group}}
+<tr><th>Group</th><td>yes</td></tr>
+{{- end}}
+{{- if .Severity}}
+<tr><th>Severity</th><td>{{.Severity}}</td></tr>
+{{- end}}
+{{- if .Retryable}}
+<tr><th>Retryable</th><td>yes</td></tr>
+{{- end}}
+{{- if .HTTPStatus}}
+<tr><th>HTTP Status</th><td>{{.HTTPStatus}}</td></tr>
+{{- end}}
+{{- if .Params}}
+<tr><th>Params</th><td>{{range $i, $param := .Params}}{{if $i}}, {{end}}<code>{{$param}}</code>{{end}}</td></tr>
+{{- end}}
+</table>
+{{- if .KindDoc}}
+<p>{{.KindDoc}}</p>
+{{- end}}
+{{- if .Message}}
+<p>Message template:</p>
+<pre>{{.Message}}</pre>
+{{- end}}
+{{- if .Example}}
+<p>Example: <code>{{.Example}}</code></p>
+{{- end}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
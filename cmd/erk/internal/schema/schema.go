@@ -0,0 +1,306 @@
+// Package schema generates JSON Schemas for exported erk errors.
+package schema
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/JosiahWitt/erk/cmd/erk/internal/catalog"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/docs"
+)
+
+// JSONSchemaDialect is the JSON Schema version of the generated schemas.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Names of the generated schemas for the exported formats.
+const (
+	ExportedErrorName = "ExportedError"
+	ExportedGroupName = "ExportedGroup"
+	ErkJSONErrorName  = "ErkJSONError"
+	InvalidJSONName   = "InvalidJSONError"
+)
+
+const (
+	invalidJSONKind    = "erk:error_is_invalid_json"
+	jsonSchemaRefRoot  = "#/$defs/"
+	openAPIRefRoot     = "#/components/schemas/"
+	openAPIVersion     = "3.1.0"
+	defaultSchemaTitle = "Erk Errors"
+)
+
+// Schema is a JSON Schema object.
+type Schema map[string]interface{}
+
+// generator of schemas, where refRoot is the prefix of references to other schemas.
+type generator struct {
+	refRoot string
+}
+
+func (g *generator) ref(name string) Schema {
+	return Schema{"$ref": g.refRoot + name}
+}
+
+// Schemas for the exported formats, and a schema for each kind in the entries, keyed by name.
+//
+// Kind schemas are named using the last element of the package path and the kind type name, such as store.ErkNotFound.
+// Entries with a kind string that cannot be determined are skipped.
+func (g *generator) schemas(entries []*catalog.Entry) map[string]Schema {
+	schemas := map[string]Schema{
+		ExportedErrorName: g.exportedError(),
+		ExportedGroupName: g.exportedGroup(),
+		InvalidJSONName:   g.invalidJSON(),
+		ErkJSONErrorName: {
+			"description": "The JSON returned by erkjson.ExportError.",
+			"anyOf":       []Schema{g.ref(ExportedGroupName), g.ref(ExportedErrorName), g.ref(InvalidJSONName)},
+		},
+	}
+
+	for name, kindEntries := range entriesByKind(entries) {
+		schemas[name] = g.kind(kindEntries)
+	}
+
+	return schemas
+}
+
+func (g *generator) exportedError() Schema {
+	return Schema{
+		"description": "An error exported by erk.Export.",
+		"type":        "object",
+		"required":    []string{"kind", "message"},
+		"properties": Schema{
+			"kind": Schema{
+				"description": "The kind string, or null if the error was not created by erk.",
+				"type":        []string{"string", "null"},
+			},
+			"type": Schema{
+				"description": "The type of the original error, if it was not created by erk.",
+				"type":        "string",
+			},
+			"message":  Schema{"type": "string"},
+			"params":   Schema{"type": "object"},
+			"metadata": Schema{"type": "object"},
+			"severity": Schema{"type": "string"},
+			"code": Schema{
+				"description": "The numeric code registered for the kind, if any.",
+				"type":        "integer",
+			},
+			"errorStack": Schema{
+				"description": "The wrapped errors, from outermost to innermost.",
+				"type":        "array",
+				"items":       g.ref(ExportedErrorName),
+			},
+		},
+	}
+}
+
+func (g *generator) exportedGroup() Schema {
+	return Schema{
+		"description": "An error group exported by erg.Group.Export.",
+		"allOf": []Schema{
+			g.ref(ExportedErrorName),
+			{
+				"type":     "object",
+				"required": []string{"errors"},
+				"properties": Schema{
+					"errors": Schema{
+						"type":  "array",
+						"items": Schema{"anyOf": []Schema{g.ref(ExportedGroupName), g.ref(ExportedErrorName)}},
+					},
+					"counts": Schema{
+						"description": "The occurrences of each error, if the group is deduplicated.",
+						"type":        "array",
+						"items":       Schema{"type": "integer"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (g *generator) invalidJSON() Schema {
+	return Schema{
+		"description": "Returned by erkjson.ExportError when the error cannot be marshalled to JSON.",
+		"type":        "object",
+		"required":    []string{"kind", "message"},
+		"properties": Schema{
+			"kind":    Schema{"const": invalidJSONKind},
+			"message": Schema{"type": "string"},
+			"params": Schema{
+				"type":       "object",
+				"properties": Schema{"err": Schema{"type": "string"}},
+			},
+		},
+	}
+}
+
+// kind schema for entries with the same kind string.
+// The params are the params referenced by any of the messages, excluding the wrapped error, which is exported in the error stack.
+func (g *generator) kind(entries []*catalog.Entry) Schema {
+	base := ExportedGroupName
+	params := Schema{}
+	examples := []string{}
+	descriptions := []string{}
+
+	for _, entry := range entries {
+		if !entry.Group {
+			base = ExportedErrorName
+		}
+
+		for _, param := range entry.Params {
+			if param != "err" {
+				params[param] = Schema{}
+			}
+		}
+
+		if example := docs.RenderExample(entry.Message, entry.MessageFormat, entry.Params); example != "" {
+			examples = appendUnique(examples, example)
+		}
+
+		if entry.KindDoc != "" {
+			descriptions = appendUnique(descriptions, entry.KindDoc)
+		}
+	}
+
+	properties := Schema{
+		"kind":   Schema{"const": entries[0].Kind},
+		"params": Schema{"type": "object", "properties": params},
+	}
+
+	if len(examples) > 0 {
+		properties["message"] = Schema{"type": "string", "examples": examples}
+	}
+
+	schema := Schema{
+		"allOf": []Schema{
+			g.ref(base),
+			{"type": "object", "properties": properties},
+		},
+	}
+
+	if len(descriptions) > 0 {
+		schema["description"] = strings.Join(descriptions, "\n\n")
+	}
+
+	return schema
+}
+
+// entriesByKind groups the entries by kind, keyed by the schema name of the kind.
+func entriesByKind(entries []*catalog.Entry) map[string][]*catalog.Entry {
+	byKind := map[string][]*catalog.Entry{}
+	names := map[string]string{}
+
+	for _, entry := range entries {
+		if entry.Kind == "" || entry.KindType == "" {
+			continue
+		}
+
+		name, ok := names[entry.Kind]
+		if !ok {
+			name = uniqueName(kindSchemaName(entry.KindType), byKind)
+			names[entry.Kind] = name
+		}
+
+		byKind[name] = append(byKind[name], entry)
+	}
+
+	return byKind
+}
+
+// kindSchemaName converts a kind type, such as *example.com/store.ErkNotFound, to store.ErkNotFound.
+func kindSchemaName(kindType string) string {
+	return path.Base(strings.TrimLeft(kindType, "*"))
+}
+
+func uniqueName(name string, existing map[string][]*catalog.Entry) string {
+	unique := name
+	for i := 2; existing[unique] != nil; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+
+	return unique
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
+
+// KindSchemaNames returns the sorted names of the kind schemas generated for the entries.
+func KindSchemaNames(entries []*catalog.Entry) []string {
+	byKind := entriesByKind(entries)
+
+	names := make([]string, 0, len(byKind))
+	for name := range byKind {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// WriteJSONSchema writes a JSON Schema document with the schemas in $defs.
+// The root schema matches any kind schema, or any JSON returned by erkjson.ExportError if there are no kinds.
+func WriteJSONSchema(w io.Writer, title string, entries []*catalog.Entry) error {
+	g := &generator{refRoot: jsonSchemaRefRoot}
+
+	document := Schema{
+		"$schema": JSONSchemaDialect,
+		"title":   defaultTitle(title),
+		"$defs":   g.schemas(entries),
+	}
+
+	kindNames := KindSchemaNames(entries)
+	if len(kindNames) == 0 {
+		document["$ref"] = jsonSchemaRefRoot + ErkJSONErrorName
+	} else {
+		refs := make([]Schema, 0, len(kindNames))
+		for _, name := range kindNames {
+			refs = append(refs, g.ref(name))
+		}
+
+		document["anyOf"] = refs
+	}
+
+	return writeJSON(w, document)
+}
+
+// WriteOpenAPI writes an OpenAPI document with the schemas as components, which can be merged into an existing specification.
+func WriteOpenAPI(w io.Writer, title string, entries []*catalog.Entry) error {
+	g := &generator{refRoot: openAPIRefRoot}
+
+	document := Schema{
+		"openapi": openAPIVersion,
+		"info":    Schema{"title": defaultTitle(title), "version": "1.0.0"},
+		"paths":   Schema{},
+		"components": Schema{
+			"schemas": g.schemas(entries),
+		},
+	}
+
+	return writeJSON(w, document)
+}
+
+func defaultTitle(title string) string {
+	if title == "" {
+		return defaultSchemaTitle
+	}
+
+	return title
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(v)
+}
@@ -0,0 +1,179 @@
+package schema_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/catalog"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/schema"
+	"github.com/JosiahWitt/erk/erg"
+)
+
+func exampleEntries() []*catalog.Entry {
+	return []*catalog.Entry{
+		{
+			Name:     "ErrNotFound",
+			KindType: "*example.com/store.ErkNotFound",
+			Kind:     "example.com/store:ErkNotFound",
+			Message:  "item {{.key}} was not found: {{.err}}",
+			Params:   []string{"err", "key"},
+			KindDoc:  "ErkNotFound is the kind of missing items.",
+		},
+		{
+			Name:     "ErrTableNotFound",
+			KindType: "*example.com/store.ErkNotFound",
+			Kind:     "example.com/store:ErkNotFound",
+			Message:  "table {{.table}} was not found",
+			Params:   []string{"table"},
+			KindDoc:  "ErkNotFound is the kind of missing items.",
+		},
+		{
+			Name:     "ErrOther",
+			KindType: "example.com/other.ErkNotFound",
+			Kind:     "example.com/other:ErkNotFound",
+			Message:  "multiple errors",
+			Params:   []string{},
+			Group:    true,
+		},
+		{
+			Name:     "ErrUnknown",
+			KindType: "example.com/other.ErkUnknown",
+			Message:  "unknown",
+		},
+	}
+}
+
+func TestWriteJSONSchema(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with kinds", func(ensure ensurepkg.Ensure) {
+		document := writeJSONSchema(ensure, exampleEntries())
+		defs := document["$defs"].(map[string]interface{})
+
+		ensure(document["$schema"]).Equals(schema.JSONSchemaDialect)
+		ensure(document["title"]).Equals("My Errors")
+		ensure(document["anyOf"]).Equals([]interface{}{
+			map[string]interface{}{"$ref": "#/$defs/other.ErkNotFound"},
+			map[string]interface{}{"$ref": "#/$defs/store.ErkNotFound"},
+		})
+
+		ensure(defs["store.ErkNotFound"]).Equals(map[string]interface{}{
+			"description": "ErkNotFound is the kind of missing items.",
+			"allOf": []interface{}{
+				map[string]interface{}{"$ref": "#/$defs/ExportedError"},
+				map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"kind": map[string]interface{}{"const": "example.com/store:ErkNotFound"},
+						"message": map[string]interface{}{
+							"type":     "string",
+							"examples": []interface{}{"item <key> was not found: <err>", "table <table> was not found"},
+						},
+						"params": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"key":   map[string]interface{}{},
+								"table": map[string]interface{}{},
+							},
+						},
+					},
+				},
+			},
+		})
+
+		other := defs["other.ErkNotFound"].(map[string]interface{})
+		ensure(other["allOf"].([]interface{})[0]).Equals(map[string]interface{}{"$ref": "#/$defs/ExportedGroup"})
+
+		ensure(defs["ErkJSONError"].(map[string]interface{})["anyOf"]).Equals([]interface{}{
+			map[string]interface{}{"$ref": "#/$defs/ExportedGroup"},
+			map[string]interface{}{"$ref": "#/$defs/ExportedError"},
+			map[string]interface{}{"$ref": "#/$defs/InvalidJSONError"},
+		})
+	})
+
+	ensure.Run("without kinds", func(ensure ensurepkg.Ensure) {
+		document := writeJSONSchema(ensure, nil)
+		ensure(document["$ref"]).Equals("#/$defs/ErkJSONError")
+		ensure(document["anyOf"]).IsNil()
+	})
+
+	ensure.Run("with the properties of the exported types", func(ensure ensurepkg.Ensure) {
+		defs := writeJSONSchema(ensure, nil)["$defs"].(map[string]interface{})
+
+		errorProperties := defs["ExportedError"].(map[string]interface{})["properties"].(map[string]interface{})
+		for _, tag := range jsonTags(reflect.TypeOf(erk.ExportedError{})) {
+			_, ok := errorProperties[tag]
+			ensure(ok).IsTrue()
+		}
+
+		groupSchema := defs["ExportedGroup"].(map[string]interface{})["allOf"].([]interface{})[1].(map[string]interface{})
+		groupProperties := groupSchema["properties"].(map[string]interface{})
+		for _, tag := range jsonTags(reflect.TypeOf(erg.ExportedGroup{})) {
+			_, ok := groupProperties[tag]
+			ensure(ok).IsTrue()
+		}
+	})
+}
+
+func TestWriteOpenAPI(t *testing.T) {
+	ensure := ensure.New(t)
+
+	b := &bytes.Buffer{}
+	ensure(schema.WriteOpenAPI(b, "", exampleEntries())).IsNotError()
+
+	document := map[string]interface{}{}
+	ensure(json.Unmarshal(b.Bytes(), &document)).IsNotError()
+
+	ensure(document["openapi"]).Equals("3.1.0")
+	ensure(document["info"].(map[string]interface{})["title"]).Equals("Erk Errors")
+
+	schemas := document["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	ensure(schemas["ExportedGroup"].(map[string]interface{})["allOf"].([]interface{})[0]).Equals(
+		map[string]interface{}{"$ref": "#/components/schemas/ExportedError"},
+	)
+	ensure(strings.Contains(b.String(), "#/$defs/")).IsFalse()
+}
+
+func TestKindSchemaNames(t *testing.T) {
+	ensure := ensure.New(t)
+
+	entries := append(exampleEntries(), &catalog.Entry{
+		KindType: "example.com/v2/store.ErkNotFound",
+		Kind:     "example.com/v2/store:ErkNotFound",
+	})
+
+	ensure(schema.KindSchemaNames(entries)).Equals([]string{"other.ErkNotFound", "store.ErkNotFound", "store.ErkNotFound_2"})
+}
+
+func writeJSONSchema(ensure ensurepkg.Ensure, entries []*catalog.Entry) map[string]interface{} {
+	ensure.T().Helper()
+
+	b := &bytes.Buffer{}
+	ensure(schema.WriteJSONSchema(b, "My Errors", entries)).IsNotError()
+
+	document := map[string]interface{}{}
+	ensure(json.Unmarshal(b.Bytes(), &document)).IsNotError()
+
+	return document
+}
+
+// jsonTags returns the JSON names of the fields of the struct type, excluding embedded fields.
+func jsonTags(t reflect.Type) []string {
+	tags := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			continue
+		}
+
+		tags = append(tags, strings.Split(field.Tag.Get("json"), ",")[0])
+	}
+
+	return tags
+}
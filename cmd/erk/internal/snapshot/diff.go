@@ -0,0 +1,272 @@
+package snapshot
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Severity of a change.
+type Severity string
+
+// Severities of changes, from most to least severe.
+const (
+	// SeverityBreaking changes can break clients, such as removing a kind string that clients match on.
+	SeverityBreaking Severity = "breaking"
+
+	// SeverityWarning changes might break clients, such as changing a message that clients display or match on.
+	SeverityWarning Severity = "warning"
+
+	// SeverityInfo changes are backwards compatible, such as adding an error.
+	SeverityInfo Severity = "info"
+)
+
+// ChangeType describes what changed.
+type ChangeType string
+
+// Types of changes.
+const (
+	ChangeErrorRemoved  ChangeType = "error_removed"
+	ChangeErrorAdded    ChangeType = "error_added"
+	ChangeErrorRenamed  ChangeType = "error_renamed"
+	ChangeKindRemoved   ChangeType = "kind_removed"
+	ChangeKindChanged   ChangeType = "kind_changed"
+	ChangeParamRemoved  ChangeType = "param_removed"
+	ChangeParamAdded    ChangeType = "param_added"
+	ChangeMessageChange ChangeType = "message_changed"
+)
+
+// Change between two snapshots.
+type Change struct {
+	Severity Severity   `json:"severity"`
+	Type     ChangeType `json:"type"`
+	Package  string     `json:"package,omitempty"`
+	Name     string     `json:"name,omitempty"`
+	Old      string     `json:"old,omitempty"`
+	New      string     `json:"new,omitempty"`
+}
+
+// Diff the old snapshot against the new snapshot.
+// Changes are sorted by severity, and then by package, name, and type.
+//
+// Errors are matched by package and variable name.
+// If a removed error and an added error have the same kind and message, the error is considered renamed instead.
+func Diff(oldSnapshot, newSnapshot *Snapshot) []*Change {
+	oldErrors := indexErrors(oldSnapshot)
+	newErrors := indexErrors(newSnapshot)
+	changes := []*Change{}
+
+	removed := []*Error{}
+	for _, oldErr := range oldSnapshot.Errors {
+		newErr, ok := newErrors[oldErr.id()]
+		if !ok {
+			removed = append(removed, oldErr)
+			continue
+		}
+
+		changes = append(changes, diffError(oldErr, newErr)...)
+	}
+
+	added := []*Error{}
+	for _, newErr := range newSnapshot.Errors {
+		if _, ok := oldErrors[newErr.id()]; !ok {
+			added = append(added, newErr)
+		}
+	}
+
+	changes = append(changes, diffRemovedAndAdded(removed, added)...)
+	changes = append(changes, diffKinds(oldSnapshot, newSnapshot)...)
+
+	sortChanges(changes)
+	return changes
+}
+
+// CountBreaking returns the number of breaking changes.
+func CountBreaking(changes []*Change) int {
+	count := 0
+	for _, change := range changes {
+		if change.Severity == SeverityBreaking {
+			count++
+		}
+	}
+
+	return count
+}
+
+// WriteText writes the changes with one change per line.
+func WriteText(w io.Writer, changes []*Change) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "No changes")
+		return err
+	}
+
+	for _, change := range changes {
+		if _, err := fmt.Fprintf(w, "%-8s %s\n", change.Severity, change.describe()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Change) describe() string {
+	subject := c.Package + "." + c.Name
+
+	switch c.Type {
+	case ChangeErrorRemoved:
+		return fmt.Sprintf("%s was removed", subject)
+	case ChangeErrorAdded:
+		return fmt.Sprintf("%s was added", subject)
+	case ChangeErrorRenamed:
+		return fmt.Sprintf("%s was renamed to %s", c.Old, c.New)
+	case ChangeKindRemoved:
+		return fmt.Sprintf("kind %q is no longer used by any error", c.Old)
+	case ChangeKindChanged:
+		return fmt.Sprintf("%s changed kind from %q to %q", subject, c.Old, c.New)
+	case ChangeParamRemoved:
+		return fmt.Sprintf("%s no longer references param %q", subject, c.Old)
+	case ChangeParamAdded:
+		return fmt.Sprintf("%s now references param %q", subject, c.New)
+	case ChangeMessageChange:
+		return fmt.Sprintf("%s changed message from %q to %q", subject, c.Old, c.New)
+	default:
+		return fmt.Sprintf("%s changed: %s", subject, c.Type)
+	}
+}
+
+func indexErrors(snapshot *Snapshot) map[string]*Error {
+	index := make(map[string]*Error, len(snapshot.Errors))
+	for _, err := range snapshot.Errors {
+		index[err.id()] = err
+	}
+
+	return index
+}
+
+func diffError(oldErr, newErr *Error) []*Change {
+	changes := []*Change{}
+	change := func(severity Severity, changeType ChangeType, oldValue, newValue string) {
+		changes = append(changes, &Change{
+			Severity: severity,
+			Type:     changeType,
+			Package:  newErr.Package,
+			Name:     newErr.Name,
+			Old:      oldValue,
+			New:      newValue,
+		})
+	}
+
+	if oldErr.Kind != newErr.Kind {
+		change(SeverityBreaking, ChangeKindChanged, oldErr.Kind, newErr.Kind)
+	}
+
+	if oldErr.Message != newErr.Message {
+		change(SeverityWarning, ChangeMessageChange, oldErr.Message, newErr.Message)
+	}
+
+	for _, param := range difference(oldErr.Params, newErr.Params) {
+		change(SeverityBreaking, ChangeParamRemoved, param, "")
+	}
+
+	for _, param := range difference(newErr.Params, oldErr.Params) {
+		change(SeverityInfo, ChangeParamAdded, "", param)
+	}
+
+	return changes
+}
+
+func diffRemovedAndAdded(removed, added []*Error) []*Change {
+	changes := []*Change{}
+	renamedTo := map[*Error]bool{}
+
+	for _, oldErr := range removed {
+		renamed := false
+
+		for _, newErr := range added {
+			if !renamedTo[newErr] && oldErr.Kind == newErr.Kind && oldErr.Message == newErr.Message {
+				renamedTo[newErr] = true
+				renamed = true
+
+				changes = append(changes, &Change{
+					Severity: SeverityInfo,
+					Type:     ChangeErrorRenamed,
+					Package:  newErr.Package,
+					Name:     newErr.Name,
+					Old:      oldErr.id(),
+					New:      newErr.id(),
+				})
+
+				break
+			}
+		}
+
+		if !renamed {
+			changes = append(changes, &Change{Severity: SeverityBreaking, Type: ChangeErrorRemoved, Package: oldErr.Package, Name: oldErr.Name})
+		}
+	}
+
+	for _, newErr := range added {
+		if !renamedTo[newErr] {
+			changes = append(changes, &Change{Severity: SeverityInfo, Type: ChangeErrorAdded, Package: newErr.Package, Name: newErr.Name})
+		}
+	}
+
+	return changes
+}
+
+// diffKinds returns a change for each kind string that was used by the old snapshot, but is not used by the new snapshot.
+func diffKinds(oldSnapshot, newSnapshot *Snapshot) []*Change {
+	newKinds := map[string]bool{}
+	for _, err := range newSnapshot.Errors {
+		newKinds[err.Kind] = true
+	}
+
+	changes := []*Change{}
+	seen := map[string]bool{}
+
+	for _, err := range oldSnapshot.Errors {
+		if err.Kind == "" || newKinds[err.Kind] || seen[err.Kind] {
+			continue
+		}
+
+		seen[err.Kind] = true
+		changes = append(changes, &Change{Severity: SeverityBreaking, Type: ChangeKindRemoved, Old: err.Kind})
+	}
+
+	return changes
+}
+
+// difference returns the values in a that are not in b.
+func difference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, value := range b {
+		inB[value] = true
+	}
+
+	diff := []string{}
+	for _, value := range a {
+		if !inB[value] {
+			diff = append(diff, value)
+		}
+	}
+
+	return diff
+}
+
+//nolint:gochecknoglobals // Read only
+var severityOrder = map[Severity]int{SeverityBreaking: 0, SeverityWarning: 1, SeverityInfo: 2}
+
+func sortChanges(changes []*Change) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+
+		if severityOrder[a.Severity] != severityOrder[b.Severity] {
+			return severityOrder[a.Severity] < severityOrder[b.Severity]
+		}
+
+		aKey := strings.Join([]string{a.Package, a.Name, string(a.Type), a.Old, a.New}, "\n")
+		bKey := strings.Join([]string{b.Package, b.Name, string(b.Type), b.Old, b.New}, "\n")
+		return aKey < bKey
+	})
+}
@@ -0,0 +1,134 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/snapshot"
+)
+
+func TestDiff(t *testing.T) {
+	ensure := ensure.New(t)
+
+	notFound := func() *snapshot.Error {
+		return &snapshot.Error{Package: "store", Name: "ErrNotFound", Kind: "store:not_found", Message: "{{.key}} was not found", Params: []string{"key"}}
+	}
+
+	table := []struct {
+		Name            string
+		Old             []*snapshot.Error
+		New             []*snapshot.Error
+		ExpectedChanges []*snapshot.Change
+	}{
+		{
+			Name:            "without changes",
+			Old:             []*snapshot.Error{notFound()},
+			New:             []*snapshot.Error{notFound()},
+			ExpectedChanges: []*snapshot.Change{},
+		},
+		{
+			Name: "with removed error",
+			Old:  []*snapshot.Error{notFound()},
+			New:  []*snapshot.Error{},
+			ExpectedChanges: []*snapshot.Change{
+				{Severity: snapshot.SeverityBreaking, Type: snapshot.ChangeKindRemoved, Old: "store:not_found"},
+				{Severity: snapshot.SeverityBreaking, Type: snapshot.ChangeErrorRemoved, Package: "store", Name: "ErrNotFound"},
+			},
+		},
+		{
+			Name: "with added error",
+			Old:  []*snapshot.Error{},
+			New:  []*snapshot.Error{notFound()},
+			ExpectedChanges: []*snapshot.Change{
+				{Severity: snapshot.SeverityInfo, Type: snapshot.ChangeErrorAdded, Package: "store", Name: "ErrNotFound"},
+			},
+		},
+		{
+			Name: "with renamed error",
+			Old:  []*snapshot.Error{notFound()},
+			New: []*snapshot.Error{func() *snapshot.Error {
+				e := notFound()
+				e.Name = "ErrMissing"
+				return e
+			}()},
+			ExpectedChanges: []*snapshot.Change{
+				{Severity: snapshot.SeverityInfo, Type: snapshot.ChangeErrorRenamed, Package: "store", Name: "ErrMissing", Old: "store.ErrNotFound", New: "store.ErrMissing"},
+			},
+		},
+		{
+			Name: "with changed kind, message, and params",
+			Old:  []*snapshot.Error{notFound()},
+			New: []*snapshot.Error{func() *snapshot.Error {
+				e := notFound()
+				e.Kind = "store:missing"
+				e.Message = "{{.id}} was not found"
+				e.Params = []string{"id"}
+				return e
+			}()},
+			ExpectedChanges: []*snapshot.Change{
+				{Severity: snapshot.SeverityBreaking, Type: snapshot.ChangeKindRemoved, Old: "store:not_found"},
+				{Severity: snapshot.SeverityBreaking, Type: snapshot.ChangeKindChanged, Package: "store", Name: "ErrNotFound", Old: "store:not_found", New: "store:missing"},
+				{Severity: snapshot.SeverityBreaking, Type: snapshot.ChangeParamRemoved, Package: "store", Name: "ErrNotFound", Old: "key"},
+				{Severity: snapshot.SeverityWarning, Type: snapshot.ChangeMessageChange, Package: "store", Name: "ErrNotFound", Old: "{{.key}} was not found", New: "{{.id}} was not found"},
+				{Severity: snapshot.SeverityInfo, Type: snapshot.ChangeParamAdded, Package: "store", Name: "ErrNotFound", New: "id"},
+			},
+		},
+		{
+			Name: "with kind still used by another error",
+			Old: []*snapshot.Error{notFound(), func() *snapshot.Error {
+				e := notFound()
+				e.Name = "ErrTableNotFound"
+				e.Message = "table was not found"
+				return e
+			}()},
+			New: []*snapshot.Error{notFound()},
+			ExpectedChanges: []*snapshot.Change{
+				{Severity: snapshot.SeverityBreaking, Type: snapshot.ChangeErrorRemoved, Package: "store", Name: "ErrTableNotFound"},
+			},
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		changes := snapshot.Diff(
+			&snapshot.Snapshot{Version: snapshot.Version, Errors: entry.Old},
+			&snapshot.Snapshot{Version: snapshot.Version, Errors: entry.New},
+		)
+		ensure(changes).Equals(entry.ExpectedChanges)
+	})
+}
+
+func TestCountBreaking(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure(snapshot.CountBreaking([]*snapshot.Change{
+		{Severity: snapshot.SeverityBreaking},
+		{Severity: snapshot.SeverityWarning},
+		{Severity: snapshot.SeverityBreaking},
+	})).Equals(2)
+}
+
+func TestWriteText(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("without changes", func(ensure ensurepkg.Ensure) {
+		var b bytes.Buffer
+		ensure(snapshot.WriteText(&b, nil)).IsNotError()
+		ensure(b.String()).Equals("No changes\n")
+	})
+
+	ensure.Run("with changes", func(ensure ensurepkg.Ensure) {
+		var b bytes.Buffer
+		ensure(snapshot.WriteText(&b, []*snapshot.Change{
+			{Severity: snapshot.SeverityBreaking, Type: snapshot.ChangeKindRemoved, Old: "store:not_found"},
+			{Severity: snapshot.SeverityWarning, Type: snapshot.ChangeMessageChange, Package: "store", Name: "ErrNotFound", Old: "a", New: "b"},
+		})).IsNotError()
+		ensure(b.String()).Equals(
+			"breaking kind \"store:not_found\" is no longer used by any error\n" +
+				"warning  store.ErrNotFound changed message from \"a\" to \"b\"\n",
+		)
+	})
+}
@@ -0,0 +1,91 @@
+// Package snapshot records the errors in a catalog, and detects changes between snapshots that could break clients.
+package snapshot
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/catalog"
+)
+
+// Version of the snapshot format.
+const Version = 1
+
+// ErkSnapshot is the kind of errors returned when reading a snapshot.
+type ErkSnapshot struct{ erk.DefaultKind }
+
+// Errors returned when reading a snapshot.
+var (
+	ErrRead    = erk.New(ErkSnapshot{}, "failed to read the snapshot: {{.err}}")
+	ErrVersion = erk.New(ErkSnapshot{}, "unsupported snapshot version {{.version}}, expected {{.expected}}")
+)
+
+// Snapshot of the errors in a catalog.
+type Snapshot struct {
+	Version int      `json:"version"`
+	Errors  []*Error `json:"errors"`
+}
+
+// Error in a snapshot.
+// Only the fields that clients can observe are recorded.
+type Error struct {
+	Package string   `json:"package"`
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind"`
+	Message string   `json:"message"`
+	Params  []string `json:"params"`
+}
+
+// New snapshot of the catalog entries, sorted by package and then by name.
+func New(entries []*catalog.Entry) *Snapshot {
+	snapshot := &Snapshot{Version: Version, Errors: []*Error{}}
+
+	for _, entry := range entries {
+		params := append([]string{}, entry.Params...)
+		sort.Strings(params)
+
+		snapshot.Errors = append(snapshot.Errors, &Error{
+			Package: entry.Package,
+			Name:    entry.Name,
+			Kind:    entry.Kind,
+			Message: entry.Message,
+			Params:  params,
+		})
+	}
+
+	sort.Slice(snapshot.Errors, func(i, j int) bool {
+		return snapshot.Errors[i].id() < snapshot.Errors[j].id()
+	})
+
+	return snapshot
+}
+
+// Read a snapshot written by Write.
+func Read(r io.Reader) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	if err := json.NewDecoder(r).Decode(snapshot); err != nil {
+		return nil, erk.WrapAs(ErrRead, err)
+	}
+
+	if snapshot.Version != Version {
+		return nil, erk.WithParams(ErrVersion, erk.Params{"version": snapshot.Version, "expected": Version})
+	}
+
+	return snapshot, nil
+}
+
+// Write the snapshot as indented JSON.
+func (s *Snapshot) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(s)
+}
+
+// id uniquely identifies the error by its package and variable name.
+func (e *Error) id() string {
+	return e.Package + "." + e.Name
+}
@@ -0,0 +1,59 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/catalog"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/snapshot"
+)
+
+func TestNew(t *testing.T) {
+	ensure := ensure.New(t)
+
+	s := snapshot.New([]*catalog.Entry{
+		{Package: "example.com/store", Name: "ErrTimeout", Kind: "store:timeout", Message: "timed out", Params: []string{}},
+		{Package: "example.com/store", Name: "ErrNotFound", Kind: "store:not_found", Message: "{{.key}} in {{.table}}", Params: []string{"table", "key"}},
+	})
+
+	ensure(s).Equals(&snapshot.Snapshot{
+		Version: snapshot.Version,
+		Errors: []*snapshot.Error{
+			{Package: "example.com/store", Name: "ErrNotFound", Kind: "store:not_found", Message: "{{.key}} in {{.table}}", Params: []string{"key", "table"}},
+			{Package: "example.com/store", Name: "ErrTimeout", Kind: "store:timeout", Message: "timed out", Params: []string{}},
+		},
+	})
+}
+
+func TestReadWrite(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("round trips", func(ensure ensurepkg.Ensure) {
+		s := snapshot.New([]*catalog.Entry{
+			{Package: "example.com/store", Name: "ErrNotFound", Kind: "store:not_found", Message: "<{{.key}}>", Params: []string{"key"}},
+		})
+
+		var b bytes.Buffer
+		ensure(s.Write(&b)).IsNotError()
+		ensure(strings.Contains(b.String(), `"message": "<{{.key}}>"`)).IsTrue()
+
+		read, err := snapshot.Read(&b)
+		ensure(err).IsNotError()
+		ensure(read).Equals(s)
+	})
+
+	ensure.Run("with invalid JSON", func(ensure ensurepkg.Ensure) {
+		read, err := snapshot.Read(strings.NewReader("{"))
+		ensure(err).MatchesAllErrors(snapshot.ErrRead)
+		ensure(read).IsNil()
+	})
+
+	ensure.Run("with unsupported version", func(ensure ensurepkg.Ensure) {
+		read, err := snapshot.Read(strings.NewReader(`{"version":2,"errors":[]}`))
+		ensure(err).MatchesAllErrors(snapshot.ErrVersion)
+		ensure(read).IsNil()
+	})
+}
@@ -0,0 +1,128 @@
+// Command erk provides tooling for projects using erk errors.
+//
+// Usage:
+//
+//	erk <command> [flags] [args]
+//
+// Commands:
+//
+//	analyze   Summarize JSON lines of exported errors
+//	catalog   List the errors declared in Go packages
+//	diff      Detect breaking changes to declared errors since a snapshot
+//	docs      Generate reference documentation for declared errors
+//	schema    Generate JSON Schemas for exported errors
+//	snapshot  Record declared errors for detecting breaking changes
+//
+// Run erk <command> -h for the flags of each command.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/JosiahWitt/erk"
+)
+
+// ErkUsage is the kind of errors caused by invalid command line usage.
+type ErkUsage struct{ erk.DefaultKind }
+
+// Errors caused by invalid command line usage.
+var (
+	ErrUnknownCommand = erk.New(ErkUsage{}, "unknown command: {{.command}}")
+	ErrInvalidFlags   = erk.New(ErkUsage{}, "invalid flags: {{.err}}")
+)
+
+type streams struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+type command struct {
+	summary string
+	run     func(args []string, s streams) error
+}
+
+//nolint:gochecknoglobals // Read only
+var commands = map[string]command{
+	"analyze":  {summary: "Summarize JSON lines of exported errors", run: runAnalyze},
+	"catalog":  {summary: "List the errors declared in Go packages", run: runCatalog},
+	"diff":     {summary: "Detect breaking changes to declared errors since a snapshot", run: runDiff},
+	"docs":     {summary: "Generate reference documentation for declared errors", run: runDocs},
+	"schema":   {summary: "Generate JSON Schemas for exported errors", run: runSchema},
+	"snapshot": {summary: "Record declared errors for detecting breaking changes", run: runSnapshot},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		printUsage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintln(stderr, erk.WithParams(ErrUnknownCommand, erk.Params{"command": args[0]}))
+		printUsage(stderr)
+		return 2
+	}
+
+	err := cmd.run(args[1:], streams{stdin: stdin, stdout: stdout, stderr: stderr})
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitCodeFor(err)
+	}
+
+	return 0
+}
+
+// exitCodeFor the error: 2 for usage errors, and 1 otherwise.
+func exitCodeFor(err error) int {
+	if erk.IsKind(err, ErkUsage{}) {
+		return 2
+	}
+
+	return 1
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: erk <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+}
+
+// parseFlags parses the flags, returning flag.ErrHelp if help was requested.
+func parseFlags(fs *flag.FlagSet, args []string, s streams) error {
+	fs.SetOutput(s.stderr)
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+
+		return erk.WrapAs(ErrInvalidFlags, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+)
+
+func TestRun(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("without command", func(ensure ensurepkg.Ensure) {
+		stderr := &bytes.Buffer{}
+		ensure(run(nil, nil, &bytes.Buffer{}, stderr)).Equals(2)
+		ensure(strings.Contains(stderr.String(), "Usage: erk <command>")).IsTrue()
+	})
+
+	ensure.Run("with unknown command", func(ensure ensurepkg.Ensure) {
+		stderr := &bytes.Buffer{}
+		ensure(run([]string{"unknown"}, nil, &bytes.Buffer{}, stderr)).Equals(2)
+		ensure(strings.Contains(stderr.String(), "unknown command: unknown")).IsTrue()
+	})
+
+	ensure.Run("with invalid flags", func(ensure ensurepkg.Ensure) {
+		stderr := &bytes.Buffer{}
+		ensure(run([]string{"analyze", "-unknown"}, nil, &bytes.Buffer{}, stderr)).Equals(2)
+		ensure(strings.Contains(stderr.String(), "invalid flags")).IsTrue()
+	})
+
+	ensure.Run("with help flag", func(ensure ensurepkg.Ensure) {
+		stderr := &bytes.Buffer{}
+		ensure(run([]string{"analyze", "-h"}, nil, &bytes.Buffer{}, stderr)).Equals(0)
+		ensure(strings.Contains(stderr.String(), "Usage: erk analyze")).IsTrue()
+	})
+
+	ensure.Run("with command", func(ensure ensurepkg.Ensure) {
+		stdout := &bytes.Buffer{}
+		ensure(run([]string{"analyze"}, strings.NewReader(`{"kind":"a","message":"b"}`), stdout, &bytes.Buffer{})).Equals(0)
+		ensure(strings.Contains(stdout.String(), "Records: 1")).IsTrue()
+	})
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/catalog"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/schema"
+)
+
+// ErrSchemaFormat is returned when the schema format is unknown.
+var ErrSchemaFormat = erk.New(ErkUsage{}, "unknown format {{.format}}, expected jsonschema or openapi")
+
+//nolint:gochecknoglobals // Read only
+var schemaWriters = map[string]func(w io.Writer, title string, entries []*catalog.Entry) error{
+	"jsonschema": schema.WriteJSONSchema,
+	"openapi":    schema.WriteOpenAPI,
+}
+
+func runSchema(args []string, s streams) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: erk schema [flags] [packages...]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Generates a JSON Schema or OpenAPI components describing exported errors, error groups, and erkjson output,")
+		fmt.Fprintln(fs.Output(), "with a schema for each kind declared in the packages.")
+		fmt.Fprintln(fs.Output(), "The packages default to ./...")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	format := fs.String("format", "jsonschema", "output format: jsonschema or openapi")
+	title := fs.String("title", "", "title of the schema")
+	dir := fs.String("dir", ".", "directory the packages are loaded from")
+
+	if err := parseFlags(fs, args, s); err != nil {
+		return err
+	}
+
+	write, ok := schemaWriters[*format]
+	if !ok {
+		return erk.WithParams(ErrSchemaFormat, erk.Params{"format": *format})
+	}
+
+	entries, err := loadCatalog(*dir, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	return write(s.stdout, *title, entries)
+}
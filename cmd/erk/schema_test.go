@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+)
+
+func TestSchema(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with openapi format", func(ensure ensurepkg.Ensure) {
+		stdout := &bytes.Buffer{}
+		err := runSchema([]string{"-format", "openapi", "-dir", "internal/catalog/testdata", "./example"}, streams{stdout: stdout, stderr: &bytes.Buffer{}})
+		ensure(err).IsNotError()
+		ensure(strings.Contains(stdout.String(), `"example.ErkThrottled": {`)).IsTrue()
+	})
+
+	ensure.Run("renders placeholder message examples", func(ensure ensurepkg.Ensure) {
+		stdout := &bytes.Buffer{}
+		err := runSchema([]string{"-dir", "internal/catalog/testdata", "./example"}, streams{stdout: stdout, stderr: &bytes.Buffer{}})
+		ensure(err).IsNotError()
+		ensure(strings.Contains(stdout.String(), `"item <key> has {braces}"`)).IsTrue()
+	})
+
+	ensure.Run("with unknown format", func(ensure ensurepkg.Ensure) {
+		err := runSchema([]string{"-format", "yaml"}, streams{stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}})
+		ensure(err).MatchesAllErrors(ErrSchemaFormat)
+	})
+}
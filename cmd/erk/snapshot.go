@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/cmd/erk/internal/snapshot"
+)
+
+// DefaultSnapshotPath is the default file used by the snapshot and diff commands.
+const DefaultSnapshotPath = "errors.snapshot.json"
+
+// ErkSnapshot is the kind of errors returned by the snapshot command.
+type ErkSnapshot struct{ erk.DefaultKind }
+
+// ErrSnapshotWrite is returned when the snapshot cannot be written.
+var ErrSnapshotWrite = erk.New(ErkSnapshot{}, "failed to write {{.path}}: {{.err}}")
+
+func runSnapshot(args []string, s streams) error {
+	fs := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: erk snapshot [flags] [packages...]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Records the kind strings, messages, and params of the errors declared in the packages,")
+		fmt.Fprintln(fs.Output(), "so erk diff can detect changes that could break clients.")
+		fmt.Fprintln(fs.Output(), "The packages default to ./...")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	output := fs.String("o", DefaultSnapshotPath, "file to write the snapshot to, or - for stdout")
+	dir := fs.String("dir", ".", "directory the packages are loaded from")
+
+	if err := parseFlags(fs, args, s); err != nil {
+		return err
+	}
+
+	entries, err := loadCatalog(*dir, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := snapshot.New(entries).Write(&b); err != nil {
+		return err
+	}
+
+	if *output == "-" {
+		_, err := s.stdout.Write(b.Bytes())
+		return err
+	}
+
+	if err := os.WriteFile(*output, b.Bytes(), 0o644); err != nil { //nolint:gosec // Snapshots are not secret
+		return erk.WrapWith(ErrSnapshotWrite, err, erk.Params{"path": *output})
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package erk
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Metadatable errors that support appending Metadata and getting Metadata.
+type Metadatable interface {
+	WithMetadata(metadata Metadata) error
+	Metadata() Metadata
+}
+
+// Metadata are key value pairs describing where an error occurred, such as a request ID.
+//
+// Unlike Params, Metadata cannot be referenced in the message template.
+type Metadata map[string]interface{}
+
+// ContextExtractor extracts Metadata from a context.
+// It should return nil if the context does not contain the metadata.
+type ContextExtractor func(ctx context.Context) Metadata
+
+//nolint:gochecknoglobals // Registered once, and read when errors are created
+var contextExtractors struct {
+	sync.RWMutex
+	extractors []ContextExtractor
+}
+
+// RegisterContextExtractor used by WithContext.
+//
+// Extractors are usually registered once during initialization.
+// If multiple extractors return the same key, the last registered extractor wins.
+func RegisterContextExtractor(extractor ContextExtractor) {
+	contextExtractors.Lock()
+	defer contextExtractors.Unlock()
+
+	contextExtractors.extractors = append(contextExtractors.extractors, extractor)
+}
+
+// ClearContextExtractors removes all registered context extractors.
+// This is mostly useful in tests.
+func ClearContextExtractors() {
+	contextExtractors.Lock()
+	defer contextExtractors.Unlock()
+
+	contextExtractors.extractors = nil
+}
+
+// ExtractContext returns the Metadata extracted from the context by the registered extractors.
+// Nil values are skipped.
+func ExtractContext(ctx context.Context) Metadata {
+	contextExtractors.RLock()
+	defer contextExtractors.RUnlock()
+
+	metadata := Metadata{}
+	for _, extractor := range contextExtractors.extractors {
+		for key, value := range extractor(ctx) {
+			if value != nil {
+				metadata[key] = value
+			}
+		}
+	}
+
+	return metadata
+}
+
+// WithContext adds the Metadata extracted from the context to an error.
+//
+// If err does not satisfy Metadatable, the original error is returned.
+func WithContext(ctx context.Context, err error) error {
+	return WithMetadata(err, ExtractContext(ctx))
+}
+
+// NewWithContext creates an error with a kind, message, params, and the Metadata extracted from the context.
+//
+// It is equivalent to calling erk.WithContext(ctx, erk.NewWith(kind, message, params)).
+func NewWithContext(ctx context.Context, kind Kind, message string, params Params) error {
+	return WithContext(ctx, NewWith(kind, message, params))
+}
+
+// WrapWithContext wraps an error as an erk error with params, and the Metadata extracted from the context.
+//
+// It is equivalent to calling erk.WithContext(ctx, erk.WrapWith(erkError, err, params)).
+func WrapWithContext(ctx context.Context, erkError error, err error, params Params) error {
+	return WithContext(ctx, WrapWith(erkError, err, params))
+}
+
+// WithMetadata adds metadata to an error.
+//
+// If err does not satisfy Metadatable, the original error is returned.
+// A nil metadata value deletes the metadata key.
+func WithMetadata(err error, metadata Metadata) error {
+	if len(metadata) == 0 {
+		return err
+	}
+
+	var m Metadatable
+	if errors.As(err, &m) {
+		return m.WithMetadata(metadata)
+	}
+
+	return err
+}
+
+// GetMetadata returns the error's metadata.
+//
+// If err does not satisfy Metadatable, nil is returned.
+func GetMetadata(err error) Metadata {
+	var m Metadatable
+	if errors.As(err, &m) {
+		return m.Metadata()
+	}
+
+	return nil
+}
+
+// Clone the metadata into a copy.
+func (m Metadata) Clone() Metadata {
+	metadataCopy := Metadata{}
+	for k, v := range m {
+		metadataCopy[k] = v
+	}
+
+	return metadataCopy
+}
+
+func (m Metadata) merge(metadata Metadata) Metadata {
+	merged := m.Clone()
+	for key, value := range metadata {
+		if value == nil {
+			delete(merged, key)
+		} else {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,10 @@
+//go:build !go1.20
+// +build !go1.20
+
+package erk
+
+import "context"
+
+func contextCause(ctx context.Context) error {
+	return ctx.Err()
+}
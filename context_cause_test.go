@@ -0,0 +1,40 @@
+//go:build go1.20
+// +build go1.20
+
+package erk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+)
+
+func TestFromContextCause(t *testing.T) {
+	ensure := ensure.New(t)
+
+	errCause := errors.New("shutting down")
+
+	ensure.Run("when canceled with cause", func(ensure ensurepkg.Ensure) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(errCause)
+
+		err := erk.FromContext(ctx)
+		ensure(erk.IsKind(err, erk.ErkContextCanceled{})).IsTrue()
+		ensure(errors.Is(err, errCause)).IsTrue()
+		ensure(err.Error()).Equals("shutting down")
+	})
+
+	ensure.Run("when deadline exceeded with cause", func(ensure ensurepkg.Ensure) {
+		ctx, cancel := context.WithDeadlineCause(context.Background(), time.Now().Add(-time.Second), errCause)
+		defer cancel()
+
+		err := erk.FromContext(ctx)
+		ensure(erk.IsKind(err, erk.ErkContextDeadlineExceeded{})).IsTrue()
+		ensure(errors.Is(err, errCause)).IsTrue()
+	})
+}
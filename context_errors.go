@@ -0,0 +1,78 @@
+package erk
+
+import (
+	"context"
+	"errors"
+)
+
+type (
+	// ErkContextCanceled is the kind of errors caused by a canceled context.
+	ErkContextCanceled struct{ DefaultKind }
+
+	// ErkContextDeadlineExceeded is the kind of errors caused by a context deadline being exceeded.
+	ErkContextDeadlineExceeded struct{ DefaultKind }
+)
+
+// KindStringFor the provided kind.
+func (ErkContextCanceled) KindStringFor(Kind) string {
+	return "erk:context_canceled"
+}
+
+// KindStringFor the provided kind.
+func (ErkContextDeadlineExceeded) KindStringFor(Kind) string {
+	return "erk:context_deadline_exceeded"
+}
+
+// Errors wrapping context errors.
+// The message is the message of the wrapped error.
+var (
+	ErrContextCanceled         = New(ErkContextCanceled{}, "{{.err}}")
+	ErrContextDeadlineExceeded = New(ErkContextDeadlineExceeded{}, "{{.err}}")
+)
+
+// FromContextError converts context.Canceled and context.DeadlineExceeded errors to erk errors.
+//
+// If err is context.Canceled, or wraps it, it is wrapped with ErrContextCanceled.
+// If err is context.DeadlineExceeded, or wraps it, it is wrapped with ErrContextDeadlineExceeded.
+// Otherwise, or if err is already an erk.Erkable, err is returned unchanged.
+func FromContextError(err error) error {
+	var e Erkable
+	if errors.As(err, &e) {
+		return err
+	}
+
+	if classified := classifyContextError(err); classified != nil {
+		return classified
+	}
+
+	return err
+}
+
+func classifyContextError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return WrapAs(ErrContextCanceled, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return WrapAs(ErrContextDeadlineExceeded, err)
+	default:
+		return nil
+	}
+}
+
+// FromContext converts the context's error to an erk error.
+// If the context is not done, nil is returned.
+//
+// The kind is determined by ctx.Err().
+// If the context was canceled with a cause, and the Go version supports context.Cause, the cause is wrapped instead of ctx.Err().
+func FromContext(ctx context.Context) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil {
+		return nil
+	}
+
+	if errors.Is(ctxErr, context.DeadlineExceeded) {
+		return WrapAs(ErrContextDeadlineExceeded, contextCause(ctx))
+	}
+
+	return WrapAs(ErrContextCanceled, contextCause(ctx))
+}
@@ -0,0 +1,115 @@
+package erk_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+)
+
+func TestFromContextError(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name            string
+		Err             error
+		ExpectedKind    erk.Kind
+		ExpectedMessage string
+	}{
+		{
+			Name:            "with context.Canceled",
+			Err:             context.Canceled,
+			ExpectedKind:    erk.ErkContextCanceled{},
+			ExpectedMessage: "context canceled",
+		},
+		{
+			Name:            "with context.DeadlineExceeded",
+			Err:             context.DeadlineExceeded,
+			ExpectedKind:    erk.ErkContextDeadlineExceeded{},
+			ExpectedMessage: "context deadline exceeded",
+		},
+		{
+			Name:            "with wrapped context.Canceled",
+			Err:             fmt.Errorf("doing something: %w", context.Canceled),
+			ExpectedKind:    erk.ErkContextCanceled{},
+			ExpectedMessage: "doing something: context canceled",
+		},
+		{
+			Name:            "with other error",
+			Err:             errors.New("other"),
+			ExpectedKind:    nil,
+			ExpectedMessage: "other",
+		},
+		{
+			Name:            "with erk error wrapping context.Canceled",
+			Err:             erk.WrapAs(erk.New(ErkExample{}, "my message: {{.err}}"), context.Canceled),
+			ExpectedKind:    ErkExample{},
+			ExpectedMessage: "my message: context canceled",
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		err := erk.FromContextError(entry.Err)
+		ensure(erk.GetKind(err)).Equals(entry.ExpectedKind)
+		ensure(err.Error()).Equals(entry.ExpectedMessage)
+		ensure(errors.Is(err, entry.Err)).IsTrue()
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("when not done", func(ensure ensurepkg.Ensure) {
+		ensure(erk.FromContext(context.Background())).IsNotError()
+	})
+
+	ensure.Run("when canceled", func(ensure ensurepkg.Ensure) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := erk.FromContext(ctx)
+		ensure(erk.IsKind(err, erk.ErkContextCanceled{})).IsTrue()
+		ensure(errors.Is(err, context.Canceled)).IsTrue()
+		ensure(errors.Is(err, erk.ErrContextCanceled)).IsTrue()
+		ensure(err.Error()).Equals("context canceled")
+	})
+
+	ensure.Run("when deadline exceeded", func(ensure ensurepkg.Ensure) {
+		ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+
+		err := erk.FromContext(ctx)
+		ensure(erk.IsKind(err, erk.ErkContextDeadlineExceeded{})).IsTrue()
+		ensure(errors.Is(err, context.DeadlineExceeded)).IsTrue()
+		ensure(err.Error()).Equals("context deadline exceeded")
+	})
+}
+
+func TestExportContextErrors(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with context.Canceled", func(ensure ensurepkg.Ensure) {
+		bytes, err := json.Marshal(erk.Export(context.Canceled))
+		ensure(err).IsNotError()
+		ensure(string(bytes)).Equals(`{"kind":"erk:context_canceled","type":"errors:errorString","message":"context canceled"}`)
+	})
+
+	ensure.Run("with wrapped context.DeadlineExceeded", func(ensure ensurepkg.Ensure) {
+		exported := erk.Export(fmt.Errorf("calling service: %w", context.DeadlineExceeded))
+		ensure(exported.ErrorKind()).Equals("erk:context_deadline_exceeded")
+		ensure(exported.ErrorMessage()).Equals("calling service: context deadline exceeded")
+
+		errorStack := exported.(*erk.ExportedError).ErrorStack
+		ensure(len(errorStack)).Equals(1)
+		ensure(errorStack[0].ErrorKind()).Equals("erk:context_deadline_exceeded")
+		ensure(errorStack[0].ErrorMessage()).Equals("context deadline exceeded")
+	})
+}
@@ -0,0 +1,148 @@
+package erk_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+)
+
+type contextKey string
+
+func withRequestContext(requestID, tenant string) context.Context {
+	ctx := context.WithValue(context.Background(), contextKey("requestID"), requestID)
+	return context.WithValue(ctx, contextKey("tenant"), tenant)
+}
+
+func registerTestContextExtractors() {
+	erk.RegisterContextExtractor(func(ctx context.Context) erk.Metadata {
+		requestID, ok := ctx.Value(contextKey("requestID")).(string)
+		if !ok {
+			return nil
+		}
+
+		return erk.Metadata{"requestID": requestID}
+	})
+
+	erk.RegisterContextExtractor(func(ctx context.Context) erk.Metadata {
+		return erk.Metadata{"tenant": ctx.Value(contextKey("tenant"))}
+	})
+}
+
+func TestExtractContext(t *testing.T) {
+	ensure := ensure.New(t)
+	defer erk.ClearContextExtractors()
+
+	ensure.Run("with no extractors", func(ensure ensurepkg.Ensure) {
+		erk.ClearContextExtractors()
+		ensure(erk.ExtractContext(withRequestContext("req-1", "acme"))).Equals(erk.Metadata{})
+	})
+
+	ensure.Run("with extractors", func(ensure ensurepkg.Ensure) {
+		erk.ClearContextExtractors()
+		registerTestContextExtractors()
+		ensure(erk.ExtractContext(withRequestContext("req-1", "acme"))).Equals(erk.Metadata{"requestID": "req-1", "tenant": "acme"})
+	})
+
+	ensure.Run("with later extractor overriding key", func(ensure ensurepkg.Ensure) {
+		erk.ClearContextExtractors()
+		registerTestContextExtractors()
+		erk.RegisterContextExtractor(func(context.Context) erk.Metadata {
+			return erk.Metadata{"tenant": "override"}
+		})
+
+		ensure(erk.ExtractContext(withRequestContext("req-1", "acme"))).Equals(erk.Metadata{"requestID": "req-1", "tenant": "override"})
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	ensure := ensure.New(t)
+
+	erk.ClearContextExtractors()
+	registerTestContextExtractors()
+	defer erk.ClearContextExtractors()
+
+	ctx := withRequestContext("req-1", "acme")
+
+	ensure.Run("with erk error", func(ensure ensurepkg.Ensure) {
+		original := erk.New(ErkExample{}, "my message {{.a}}")
+		err := erk.WithContext(ctx, erk.WithParam(original, "a", "hello"))
+
+		ensure(err.Error()).Equals("my message hello")
+		ensure(erk.GetParams(err)).Equals(erk.Params{"a": "hello"})
+		ensure(erk.GetMetadata(err)).Equals(erk.Metadata{"requestID": "req-1", "tenant": "acme"})
+		ensure(erk.GetMetadata(original)).Equals(erk.Metadata{}) // Original is not modified
+		ensure(errors.Is(err, original)).IsTrue()
+	})
+
+	ensure.Run("with non erk error", func(ensure ensurepkg.Ensure) {
+		original := errors.New("my error")
+		err := erk.WithContext(ctx, original)
+
+		ensure(err).Equals(original)
+		ensure(erk.GetMetadata(err)).IsEmpty()
+	})
+
+	ensure.Run("with no metadata", func(ensure ensurepkg.Ensure) {
+		original := erk.New(ErkExample{}, "my message")
+		ensure(erk.WithContext(context.Background(), original)).Equals(original)
+	})
+
+	ensure.Run("exports metadata", func(ensure ensurepkg.Ensure) {
+		err := erk.WithContext(ctx, erk.NewWith(ErkExample{}, "my message {{.a}}", erk.Params{"a": "hello"}))
+
+		bytes, jsonErr := json.Marshal(err)
+		ensure(jsonErr).IsNotError()
+		ensure(string(bytes)).Equals(
+			`{"kind":"github.com/JosiahWitt/erk_test:ErkExample","message":"my message hello","params":{"a":"hello"},` +
+				`"metadata":{"requestID":"req-1","tenant":"acme"}}`,
+		)
+	})
+}
+
+func TestNewWithContext(t *testing.T) {
+	ensure := ensure.New(t)
+
+	erk.ClearContextExtractors()
+	registerTestContextExtractors()
+	defer erk.ClearContextExtractors()
+
+	err := erk.NewWithContext(withRequestContext("req-1", "acme"), ErkExample{}, "my message {{.a}}", erk.Params{"a": "hello"})
+	ensure(err.Error()).Equals("my message hello")
+	ensure(erk.GetMetadata(err)).Equals(erk.Metadata{"requestID": "req-1", "tenant": "acme"})
+}
+
+func TestWrapWithContext(t *testing.T) {
+	ensure := ensure.New(t)
+
+	erk.ClearContextExtractors()
+	registerTestContextExtractors()
+	defer erk.ClearContextExtractors()
+
+	wrappedErr := errors.New("wrapped")
+	err := erk.WrapWithContext(withRequestContext("req-1", "acme"), erk.New(ErkExample{}, "my message {{.a}}: {{.err}}"), wrappedErr, erk.Params{"a": "hello"})
+	ensure(err.Error()).Equals("my message hello: wrapped")
+	ensure(errors.Unwrap(err)).Equals(wrappedErr)
+	ensure(erk.GetMetadata(err)).Equals(erk.Metadata{"requestID": "req-1", "tenant": "acme"})
+}
+
+func TestWithMetadata(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("merges and deletes keys", func(ensure ensurepkg.Ensure) {
+		err := erk.WithMetadata(erk.New(ErkExample{}, "my message"), erk.Metadata{"a": "1", "b": "2"})
+		err = erk.WithMetadata(err, erk.Metadata{"a": nil, "c": "3"})
+		ensure(erk.GetMetadata(err)).Equals(erk.Metadata{"b": "2", "c": "3"})
+	})
+
+	ensure.Run("keeps metadata when adding params", func(ensure ensurepkg.Ensure) {
+		err := erk.WithMetadata(erk.New(ErkExample{}, "my message"), erk.Metadata{"a": "1"})
+		err = erk.WithParam(err, "b", "2")
+		ensure(erk.GetMetadata(err)).Equals(erk.Metadata{"a": "1"})
+		ensure(erk.GetParams(err)).Equals(erk.Params{"b": "2"})
+	})
+}
@@ -13,6 +13,8 @@ var (
 	_ erk.Erkable         = &Group{}
 	_ Groupable           = &Group{}
 	_ erk.ErrorIndentable = &Group{}
+	_ erk.Metadatable     = &Group{}
+	_ erk.Localizable     = &Group{}
 )
 
 var (
@@ -24,6 +26,16 @@ var (
 type Group struct {
 	header error
 	errors []error
+
+	// Set when the group is deduplicated
+	dedupeKey DedupeKeyFunc
+	indexes   map[string]int // Index of the error for each dedupe key
+
+	// Occurrences of each error, or nil if each error occurred once and the group is not deduplicated
+	counts []int
+
+	// Maximum number of errors rendered by IndentError, or 0 for no limit
+	maxRendered int
 }
 
 // ExportedGroup that can be used outside the erg package.
@@ -33,6 +45,10 @@ type Group struct {
 type ExportedGroup struct {
 	*erk.ExportedError
 	Errors []erk.ExportedErkable `json:"errors"`
+
+	// Counts of occurrences for each error in Errors.
+	// Only set when the group is deduplicated, or contains errors that were collapsed by deduplication.
+	Counts []int `json:"counts,omitempty"`
 }
 
 // New creates an error group with a kind and message.
@@ -61,24 +77,53 @@ func (g *Group) Error() string {
 
 // IndentError converts the error group to a string given the provided indentation.
 func (g *Group) IndentError(indentLevel string) string {
+	return g.LocalizedIndentError("", indentLevel)
+}
+
+// RenderLocalized converts the error group to a string, localizing the header and errors.
+// See erk.RenderLocalized.
+func (g *Group) RenderLocalized(locale string) string {
+	return g.LocalizedIndentError(locale, " ")
+}
+
+// LocalizedIndentError converts the error group to a string given the provided indentation, localizing the header and errors.
+// See erk.RenderLocalized.
+func (g *Group) LocalizedIndentError(locale, indentLevel string) string {
 	if indentLevel == "" {
 		indentLevel = " "
 	}
 
-	str := g.header.Error()
+	str := erk.RenderLocalized(g.header, locale)
 
 	if !strings.HasSuffix(str, ":") && len(g.errors) > 0 {
 		str += ":"
 	}
 
-	for _, err := range g.errors {
-		str += fmt.Sprintf("\n%s- %s", indentLevel, buildIndentedErrorMessage(err, indentLevel))
+	for i, err := range g.errors {
+		if g.maxRendered > 0 && i >= g.maxRendered {
+			str += fmt.Sprintf("\n%s- and %d more", indentLevel, len(g.errors)-i)
+			break
+		}
+
+		str += fmt.Sprintf("\n%s- %s%s", indentLevel, g.buildCountPrefix(i), buildIndentedErrorMessage(err, locale, indentLevel))
 	}
 
 	return str
 }
 
-func buildIndentedErrorMessage(err error, indentLevel string) string {
+func (g *Group) buildCountPrefix(i int) string {
+	if g.counts == nil || g.counts[i] <= 1 {
+		return ""
+	}
+
+	return fmt.Sprintf("(x%d) ", g.counts[i])
+}
+
+func buildIndentedErrorMessage(err error, locale, indentLevel string) string {
+	if localizable, ok := err.(erk.Localizable); ok && locale != "" {
+		return localizable.LocalizedIndentError(locale, indentLevel+erk.IndentSpaces)
+	}
+
 	if indentable, ok := err.(erk.ErrorIndentable); ok {
 		return indentable.IndentError(indentLevel + erk.IndentSpaces) // Add indentation to each level
 	}
@@ -116,6 +161,18 @@ func (g *Group) Params() erk.Params {
 	return erk.GetParams(g.header)
 }
 
+// WithMetadata adds metadata to the group header.
+func (g *Group) WithMetadata(metadata erk.Metadata) error {
+	g2 := g.clone()
+	g2.header = erk.WithMetadata(g.header, metadata)
+	return g2
+}
+
+// Metadata gets metadata from the group header.
+func (g *Group) Metadata() erk.Metadata {
+	return erk.GetMetadata(g.header)
+}
+
 // Kind returns the error Kind of the group header.
 func (g *Group) Kind() erk.Kind {
 	return erk.GetKind(g.header)
@@ -132,30 +189,68 @@ func (g *Group) ExportRawMessage() string {
 
 // Export the group to an ExportedGroup.
 func (g *Group) Export() erk.ExportedErkable {
+	return g.ExportLocalized("")
+}
+
+// ExportLocalized exports the group to an ExportedGroup, localizing the header and errors.
+// See erk.ExportLocalized.
+func (g *Group) ExportLocalized(locale string) erk.ExportedErkable {
 	exportedErrs := []erk.ExportedErkable{}
 	for _, err := range g.errors {
-		exportedErrs = append(exportedErrs, erk.Export(err))
+		exportedErrs = append(exportedErrs, erk.ExportLocalized(err, locale))
 	}
 
 	return &ExportedGroup{
-		ExportedError: g.buildExportedHeader(),
+		ExportedError: g.buildExportedHeader(locale),
 		Errors:        exportedErrs,
+		Counts:        g.Counts(),
 	}
 }
 
 // Append errors to the group.
 // Skips nil errors.
+//
+// If the group is deduplicated, errors matching an existing error only increment its count.
 func (g *Group) Append(errs ...error) error {
 	g2 := g.clone()
 	for _, err := range errs {
 		if err != nil {
-			g2.errors = append(g2.errors, err)
+			g2.appendWithCount(err, 1)
 		}
 	}
 
 	return g2
 }
 
+func (g *Group) appendWithCount(err error, count int) {
+	if g.dedupeKey != nil {
+		key := g.dedupeKey(err)
+		if i, ok := g.indexes[key]; ok {
+			g.counts[i] += count
+			return
+		}
+
+		if g.indexes == nil {
+			g.indexes = map[string]int{}
+		}
+
+		g.indexes[key] = len(g.errors)
+	}
+
+	// Counts are only tracked once they are needed, since most groups are not deduplicated
+	if g.counts == nil && (g.dedupeKey != nil || count != 1) {
+		g.counts = make([]int, len(g.errors), len(g.errors)+1)
+		for i := range g.counts {
+			g.counts[i] = 1
+		}
+	}
+
+	g.errors = append(g.errors, err)
+	if g.counts != nil {
+		g.counts = append(g.counts, count)
+	}
+}
+
 // Errors returns a copy of all errors of the group.
 func (g *Group) Errors() []error {
 	return g.clone().errors
@@ -170,14 +265,31 @@ func (g *Group) clone() *Group {
 	errorsCopy := make([]error, len(g.errors))
 	copy(errorsCopy, g.errors)
 
-	return &Group{
+	g2 := &Group{
 		header: g.header,
 		errors: errorsCopy,
+
+		dedupeKey:   g.dedupeKey,
+		maxRendered: g.maxRendered,
 	}
+
+	if g.indexes != nil {
+		g2.indexes = make(map[string]int, len(g.indexes))
+		for key, i := range g.indexes {
+			g2.indexes[key] = i
+		}
+	}
+
+	if g.counts != nil {
+		g2.counts = make([]int, len(g.counts))
+		copy(g2.counts, g.counts)
+	}
+
+	return g2
 }
 
-func (g *Group) buildExportedHeader() *erk.ExportedError {
-	exportedHeader := erk.Export(g.header)
+func (g *Group) buildExportedHeader(locale string) *erk.ExportedError {
+	exportedHeader := erk.ExportLocalized(g.header, locale)
 
 	if asExportedError, ok := exportedHeader.(*erk.ExportedError); ok {
 		return asExportedError
@@ -189,6 +301,9 @@ func (g *Group) buildExportedHeader() *erk.ExportedError {
 		Message: exportedHeader.ErrorMessage(),
 		Params:  exportedHeader.ErrorParams(),
 
+		Metadata:   nil,
+		Severity:   "",
+		Code:       nil,
 		ErrorStack: nil,
 	}
 }
@@ -494,3 +494,22 @@ func (err BaseExporter) WithParams(params erk.Params) error {
 	errWithParams := err.baseErkErr.WithParams(params).(*erk.Error)
 	return BaseExporter{baseErkErr: errWithParams, kind: err.kind}
 }
+
+func TestGroupWithMetadata(t *testing.T) {
+	ensure := ensure.New(t)
+
+	errs := []error{errors.New("err1"), errors.New("err2")}
+	err := erg.New(MyKind{}, "my message", errs...)
+	err2 := erk.WithMetadata(err, erk.Metadata{"requestID": "req-1"})
+
+	ensure(erg.GetErrors(err2)).Equals(errs) // Errors are not lost
+	ensure(erk.GetMetadata(err2)).Equals(erk.Metadata{"requestID": "req-1"})
+	ensure(erk.GetMetadata(err)).Equals(erk.Metadata{}) // Original group is not modified
+
+	bytes, jsonErr := json.Marshal(err2)
+	ensure(jsonErr).IsNotError()
+	ensure(string(bytes)).Equals(
+		`{"kind":"` + MyKindString + `","message":"my message","metadata":{"requestID":"req-1"},` +
+			`"errors":[{"kind":null,"type":"errors:errorString","message":"err1"},{"kind":null,"type":"errors:errorString","message":"err2"}]}`,
+	)
+}
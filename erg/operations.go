@@ -0,0 +1,193 @@
+package erg
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/JosiahWitt/erk"
+)
+
+// Filter the errors in a group, keeping only the errors where keep returns true.
+// If groupErr is not a *Group, it is returned unchanged.
+func Filter(groupErr error, keep func(err error) bool) error {
+	var g *Group
+	if errors.As(groupErr, &g) {
+		return g.Filter(keep)
+	}
+
+	return groupErr
+}
+
+// Partition the errors in a group into two groups with the same header.
+// The first group contains the errors where matches returns true, and the second contains the rest.
+// If groupErr is not a *Group, it is returned as the second group.
+func Partition(groupErr error, matches func(err error) bool) (matched error, unmatched error) {
+	var g *Group
+	if errors.As(groupErr, &g) {
+		return g.Partition(matches)
+	}
+
+	return nil, groupErr
+}
+
+// PartitionByKind splits the errors in a group into errors with one of the provided kinds, and the rest.
+// If groupErr is not a *Group, it is returned as the second group.
+func PartitionByKind(groupErr error, kinds ...erk.Kind) (matched error, unmatched error) {
+	var g *Group
+	if errors.As(groupErr, &g) {
+		return g.PartitionByKind(kinds...)
+	}
+
+	return nil, groupErr
+}
+
+// SortBy sorts the errors in a group using the provided less function.
+// If groupErr is not a *Group, it is returned unchanged.
+func SortBy(groupErr error, less func(a, b error) bool) error {
+	var g *Group
+	if errors.As(groupErr, &g) {
+		return g.SortBy(less)
+	}
+
+	return groupErr
+}
+
+// Flatten nested groups into a single level.
+// If groupErr is not a *Group, it is returned unchanged.
+func Flatten(groupErr error) error {
+	var g *Group
+	if errors.As(groupErr, &g) {
+		return g.Flatten()
+	}
+
+	return groupErr
+}
+
+// Map each error in a group to a new error.
+// If groupErr is not a *Group, it is returned unchanged.
+func Map(groupErr error, fn func(err error) error) error {
+	var g *Group
+	if errors.As(groupErr, &g) {
+		return g.Map(fn)
+	}
+
+	return groupErr
+}
+
+// LessByKindAndMessage orders errors by their kind string, and then by their rendered message.
+// It is useful with SortBy to produce deterministic output, such as in tests.
+func LessByKindAndMessage(a, b error) bool {
+	aKind, bKind := erk.GetKindString(a), erk.GetKindString(b)
+	if aKind != bKind {
+		return aKind < bKind
+	}
+
+	return a.Error() < b.Error()
+}
+
+// Filter returns a copy of the group containing only the errors where keep returns true.
+func (g *Group) Filter(keep func(err error) bool) error {
+	matched, _ := g.Partition(keep)
+	return matched
+}
+
+// Partition returns two copies of the group.
+// The first contains the errors where matches returns true, and the second contains the rest.
+func (g *Group) Partition(matches func(err error) bool) (matched error, unmatched error) {
+	matchedGroup, unmatchedGroup := g.withoutErrors(), g.withoutErrors()
+
+	for i, err := range g.errors {
+		if matches(err) {
+			matchedGroup.appendWithCount(err, g.countAt(i))
+		} else {
+			unmatchedGroup.appendWithCount(err, g.countAt(i))
+		}
+	}
+
+	return matchedGroup, unmatchedGroup
+}
+
+// PartitionByKind returns two copies of the group.
+// The first contains the errors with one of the provided kinds, and the second contains the rest.
+func (g *Group) PartitionByKind(kinds ...erk.Kind) (matched error, unmatched error) {
+	return g.Partition(func(err error) bool {
+		for _, kind := range kinds {
+			if erk.IsKind(err, kind) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// SortBy returns a copy of the group with the errors sorted using the provided less function.
+// The sort is stable, so equal errors keep their original order.
+func (g *Group) SortBy(less func(a, b error) bool) error {
+	indexes := make([]int, len(g.errors))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return less(g.errors[indexes[i]], g.errors[indexes[j]])
+	})
+
+	g2 := g.withoutErrors()
+	for _, i := range indexes {
+		g2.appendWithCount(g.errors[i], g.countAt(i))
+	}
+
+	return g2
+}
+
+// Flatten returns a copy of the group where nested groups are replaced by their errors, recursively.
+// The headers of nested groups are dropped.
+// Errors wrapping a group are not flattened.
+func (g *Group) Flatten() error {
+	g2 := g.withoutErrors()
+	g.flattenInto(g2, 1)
+	return g2
+}
+
+func (g *Group) flattenInto(target *Group, multiplier int) {
+	for i, err := range g.errors {
+		count := g.countAt(i) * multiplier
+
+		if nested, ok := err.(*Group); ok {
+			nested.flattenInto(target, count)
+			continue
+		}
+
+		target.appendWithCount(err, count)
+	}
+}
+
+// Map returns a copy of the group with each error replaced by the result of fn.
+// If fn returns nil, the error is dropped.
+func (g *Group) Map(fn func(err error) error) error {
+	g2 := g.withoutErrors()
+	for i, err := range g.errors {
+		if mappedErr := fn(err); mappedErr != nil {
+			g2.appendWithCount(mappedErr, g.countAt(i))
+		}
+	}
+
+	return g2
+}
+
+func (g *Group) withoutErrors() *Group {
+	g2 := g.clone()
+	g2.errors = nil
+	g2.indexes = nil
+	g2.counts = nil
+	return g2
+}
+
+func (g *Group) countAt(i int) int {
+	if g.counts == nil {
+		return 1
+	}
+
+	return g.counts[i]
+}
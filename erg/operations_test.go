@@ -0,0 +1,195 @@
+package erg_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+)
+
+type MyOtherKind struct{ erk.DefaultKind }
+
+func TestFilter(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("keeps matching errors", func(ensure ensurepkg.Ensure) {
+		original := erg.New(MyKind{}, "my message {{.val}}", errors.New("keep 1"), errors.New("drop"), errors.New("keep 2"))
+		original = erk.WithParam(original, "val", "my-val")
+
+		err := erg.Filter(original, func(err error) bool { return strings.HasPrefix(err.Error(), "keep") })
+
+		ensure(err.Error()).Equals("my message my-val:\n - keep 1\n - keep 2")
+		ensure(erg.GetErrors(original)).Equals([]error{errors.New("keep 1"), errors.New("drop"), errors.New("keep 2")})
+	})
+
+	ensure.Run("keeps counts", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message", errors.New("keep"), errors.New("drop"), errors.New("keep"))
+		err = erg.Dedupe(err, erg.KeyByKindAndMessage)
+
+		err = erg.Filter(err, func(err error) bool { return err.Error() == "keep" })
+
+		ensure(erg.GetCounts(err)).Equals([]int{2})
+		err = erg.Append(err, errors.New("keep"))
+		ensure(erg.GetCounts(err)).Equals([]int{3})
+	})
+
+	ensure.Run("with non group error", func(ensure ensurepkg.Ensure) {
+		err := errors.New("not a group")
+		ensure(erg.Filter(err, func(error) bool { return false })).Equals(err)
+	})
+}
+
+func TestPartitionByKind(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("splits by kind", func(ensure ensurepkg.Ensure) {
+		myKindErr := erk.New(MyKind{}, "my kind")
+		myOtherKindErr := erk.New(MyOtherKind{}, "my other kind")
+		plainErr := errors.New("plain")
+
+		err := erg.New(MyKind{}, "my message", myKindErr, plainErr, myOtherKindErr)
+
+		matched, unmatched := erg.PartitionByKind(err, MyOtherKind{}, MyKind{})
+		ensure(erg.GetErrors(matched)).Equals([]error{myKindErr, myOtherKindErr})
+		ensure(erg.GetErrors(unmatched)).Equals([]error{plainErr})
+		ensure(erk.GetKind(matched)).Equals(MyKind{})
+		ensure(erk.GetKind(unmatched)).Equals(MyKind{})
+	})
+
+	ensure.Run("with non group error", func(ensure ensurepkg.Ensure) {
+		err := errors.New("not a group")
+		matched, unmatched := erg.PartitionByKind(err, MyKind{})
+		ensure(matched).IsNil()
+		ensure(unmatched).Equals(err)
+	})
+}
+
+func TestPartition(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("splits by predicate", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message", errors.New("a1"), errors.New("b1"), errors.New("a2"))
+
+		matched, unmatched := erg.Partition(err, func(err error) bool { return strings.HasPrefix(err.Error(), "a") })
+		ensure(matched.Error()).Equals("my message:\n - a1\n - a2")
+		ensure(unmatched.Error()).Equals("my message:\n - b1")
+	})
+
+	ensure.Run("with non group error", func(ensure ensurepkg.Ensure) {
+		err := errors.New("not a group")
+		matched, unmatched := erg.Partition(err, func(error) bool { return true })
+		ensure(matched).IsNil()
+		ensure(unmatched).Equals(err)
+	})
+}
+
+func TestSortBy(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("by kind and message", func(ensure ensurepkg.Ensure) {
+		original := erg.New(MyKind{}, "my message",
+			erk.New(MyOtherKind{}, "b"),
+			erk.New(MyKind{}, "b"),
+			errors.New("z"),
+			erk.New(MyKind{}, "a"),
+		)
+
+		err := erg.SortBy(original, erg.LessByKindAndMessage)
+
+		ensure(err.Error()).Equals("my message:\n - z\n - a\n - b\n - b")
+		ensure(erk.GetKind(erg.GetErrors(err)[3])).Equals(MyOtherKind{})
+		ensure(original.Error()).Equals("my message:\n - b\n - b\n - z\n - a")
+	})
+
+	ensure.Run("keeps counts with their errors", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message", errors.New("b"), errors.New("a"), errors.New("b"))
+		err = erg.Dedupe(err, erg.KeyByKindAndMessage)
+
+		err = erg.SortBy(err, erg.LessByKindAndMessage)
+
+		ensure(err.Error()).Equals("my message:\n - a\n - (x2) b")
+		ensure(erg.GetCounts(err)).Equals([]int{1, 2})
+	})
+
+	ensure.Run("with non group error", func(ensure ensurepkg.Ensure) {
+		err := errors.New("not a group")
+		ensure(erg.SortBy(err, erg.LessByKindAndMessage)).Equals(err)
+	})
+}
+
+func TestFlatten(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("merges nested groups", func(ensure ensurepkg.Ensure) {
+		deeplyNested := erg.New(MyKind{}, "deeply nested", errors.New("err3"))
+		wrappedGroup := erk.WrapAs(erk.New(MyKind{}, "wrapped: {{.err}}"), erg.New(MyKind{}, "kept", errors.New("err4")))
+		nested := erg.New(MyKind{}, "nested", errors.New("err2"), deeplyNested, wrappedGroup)
+		err := erg.New(MyKind{}, "my message", errors.New("err1"), nested)
+
+		err = erg.Flatten(err)
+
+		ensure(err.Error()).Equals("my message:\n - err1\n - err2\n - err3\n - wrapped: kept:\n   - err4")
+	})
+
+	ensure.Run("multiplies counts of nested groups", func(ensure ensurepkg.Ensure) {
+		nested := erg.Dedupe(erg.New(MyKind{}, "nested", errors.New("err1"), errors.New("err1")), erg.KeyByKindAndMessage)
+		err := erg.New(MyKind{}, "my message", nested, nested, errors.New("err1"))
+		err = erg.Dedupe(err, erg.KeyByKindAndMessage)
+
+		err = erg.Flatten(err)
+
+		ensure(erg.GetCounts(err)).Equals([]int{5})
+	})
+
+	ensure.Run("keeps counts of nested groups when not deduplicated", func(ensure ensurepkg.Ensure) {
+		nested := erg.Dedupe(erg.New(MyKind{}, "nested", errors.New("err1"), errors.New("err1")), erg.KeyByKindAndMessage)
+		err := erg.New(MyKind{}, "my message", errors.New("err0"), nested, errors.New("err1"))
+
+		err = erg.Flatten(err)
+
+		ensure(erg.GetCounts(err)).Equals([]int{1, 2, 1})
+		ensure(err.Error()).Equals("my message:\n - err0\n - (x2) err1\n - err1")
+	})
+
+	ensure.Run("with non group error", func(ensure ensurepkg.Ensure) {
+		err := errors.New("not a group")
+		ensure(erg.Flatten(err)).Equals(err)
+	})
+}
+
+func TestMap(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("maps errors", func(ensure ensurepkg.Ensure) {
+		errWrapped := erk.New(MyKind{}, "wrapped: {{.err}}")
+		err := erg.New(MyKind{}, "my message", errors.New("err1"), errors.New("drop"), errors.New("err2"))
+
+		err = erg.Map(err, func(err error) error {
+			if err.Error() == "drop" {
+				return nil
+			}
+
+			return erk.WrapAs(errWrapped, err)
+		})
+
+		ensure(err.Error()).Equals("my message:\n - wrapped: err1\n - wrapped: err2")
+	})
+
+	ensure.Run("merges counts when deduplicated", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message", errors.New("err1"), errors.New("err1"), errors.New("err2"))
+		err = erg.Dedupe(err, erg.KeyByKindAndMessage)
+
+		err = erg.Map(err, func(error) error { return errors.New("same") })
+
+		ensure(erg.GetCounts(err)).Equals([]int{3})
+	})
+
+	ensure.Run("with non group error", func(ensure ensurepkg.Ensure) {
+		err := errors.New("not a group")
+		ensure(erg.Map(err, func(err error) error { return err })).Equals(err)
+	})
+}
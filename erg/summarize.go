@@ -0,0 +1,97 @@
+package erg
+
+import (
+	"errors"
+
+	"github.com/JosiahWitt/erk"
+)
+
+// DedupeKeyFunc returns the key used to determine if two errors in a group are duplicates.
+type DedupeKeyFunc func(err error) string
+
+// KeyByFingerprint considers errors duplicates if they have the same kind and raw message template.
+// Params are ignored, which matches how errors.Is compares erk errors.
+// Errors that are not erk.Exportable do not have a raw message, so their rendered message is used instead.
+func KeyByFingerprint(err error) string {
+	if exportable, ok := err.(erk.Exportable); ok {
+		return erk.GetKindString(err) + "\n" + exportable.ExportRawMessage()
+	}
+
+	return erk.GetKindString(err) + "\n" + err.Error()
+}
+
+// KeyByKindAndMessage considers errors duplicates if they have the same kind and rendered message.
+func KeyByKindAndMessage(err error) string {
+	return erk.GetKindString(err) + "\n" + err.Error()
+}
+
+// Dedupe the errors in a group using the provided key function.
+// If groupErr is not a *Group, it is returned unchanged.
+func Dedupe(groupErr error, key DedupeKeyFunc) error {
+	var g *Group
+	if errors.As(groupErr, &g) {
+		return g.Dedupe(key)
+	}
+
+	return groupErr
+}
+
+// Limit the number of errors rendered by the group.
+// If groupErr is not a *Group, it is returned unchanged.
+func Limit(groupErr error, maxRendered int) error {
+	var g *Group
+	if errors.As(groupErr, &g) {
+		return g.Limit(maxRendered)
+	}
+
+	return groupErr
+}
+
+// GetCounts of occurrences for each error in a deduplicated group.
+// If groupErr is not a *Group with counts, nil is returned.
+// See Group.Counts.
+func GetCounts(groupErr error) []int {
+	var g *Group
+	if errors.As(groupErr, &g) {
+		return g.Counts()
+	}
+
+	return nil
+}
+
+// Dedupe returns a copy of the group where errors with the same key are collapsed into the first occurrence.
+// Errors appended to the returned group are also deduplicated, and each occurrence is counted.
+// Passing a nil key disables deduplication for future appends, but errors that were already collapsed stay collapsed, and keep their counts.
+func (g *Group) Dedupe(key DedupeKeyFunc) error {
+	g2 := g.withoutErrors()
+	g2.dedupeKey = key
+
+	for i, err := range g.errors {
+		g2.appendWithCount(err, g.countAt(i))
+	}
+
+	return g2
+}
+
+// Limit returns a copy of the group that renders at most maxRendered errors,
+// followed by a line summarizing how many errors were omitted.
+// A maxRendered of 0 or less renders all errors.
+//
+// The limit only applies to the rendered message. All errors are still exported.
+func (g *Group) Limit(maxRendered int) error {
+	g2 := g.clone()
+	g2.maxRendered = maxRendered
+	return g2
+}
+
+// Counts returns a copy of the occurrence counts for each error in the group.
+// If the group is not deduplicated and does not contain errors that were collapsed by deduplication, nil is returned.
+func (g *Group) Counts() []int {
+	if g.counts == nil {
+		return nil
+	}
+
+	countsCopy := make([]int, len(g.counts))
+	copy(countsCopy, g.counts)
+	return countsCopy
+}
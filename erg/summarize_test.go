@@ -0,0 +1,156 @@
+package erg_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+)
+
+func TestKeyByFingerprint(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with erk error", func(ensure ensurepkg.Ensure) {
+		// Missing params would panic in strict mode if the error was rendered
+		err := erk.New(MyKind{}, "item {{.key}} not found")
+		ensure(erg.KeyByFingerprint(err)).Equals(MyKindString + "\nitem {{.key}} not found")
+	})
+
+	ensure.Run("with non-erk error", func(ensure ensurepkg.Ensure) {
+		ensure(erg.KeyByFingerprint(errors.New("other"))).Equals("\nother")
+	})
+}
+
+func TestDedupe(t *testing.T) {
+	ensure := ensure.New(t)
+
+	errNotFound := erk.New(MyKind{}, "item {{.key}} not found")
+
+	ensure.Run("by fingerprint", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message",
+			erk.WithParam(errNotFound, "key", "a"),
+			errors.New("other"),
+			erk.WithParam(errNotFound, "key", "b"),
+		)
+
+		err = erg.Dedupe(err, erg.KeyByFingerprint)
+		err = erg.Append(err, erk.WithParam(errNotFound, "key", "c"), errors.New("other"))
+
+		ensure(erg.GetErrors(err)).Equals([]error{erk.WithParam(errNotFound, "key", "a"), errors.New("other")})
+		ensure(erg.GetCounts(err)).Equals([]int{3, 2})
+		ensure(err.Error()).Equals("my message:\n - (x3) item a not found\n - (x2) other")
+	})
+
+	ensure.Run("by kind and message", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message",
+			erk.WithParam(errNotFound, "key", "a"),
+			erk.WithParam(errNotFound, "key", "b"),
+			erk.WithParam(errNotFound, "key", "a"),
+		)
+
+		err = erg.Dedupe(err, erg.KeyByKindAndMessage)
+
+		ensure(erg.GetCounts(err)).Equals([]int{2, 1})
+		ensure(err.Error()).Equals("my message:\n - (x2) item a not found\n - item b not found")
+	})
+
+	ensure.Run("when already deduplicated", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message",
+			erk.WithParam(errNotFound, "key", "a"),
+			erk.WithParam(errNotFound, "key", "b"),
+			erk.WithParam(errNotFound, "key", "a"),
+		)
+
+		err = erg.Dedupe(err, erg.KeyByKindAndMessage)
+		err = erg.Dedupe(err, erg.KeyByFingerprint)
+
+		ensure(erg.GetCounts(err)).Equals([]int{3})
+	})
+
+	ensure.Run("when disabled", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message", errors.New("err1"), errors.New("err1"))
+		err = erg.Dedupe(err, erg.KeyByKindAndMessage)
+		err = erg.Dedupe(err, nil)
+		err = erg.Append(err, errors.New("err1"))
+
+		ensure(erg.GetCounts(err)).Equals([]int{2, 1})
+		ensure(err.Error()).Equals("my message:\n - (x2) err1\n - err1")
+	})
+
+	ensure.Run("does not modify the original group", func(ensure ensurepkg.Ensure) {
+		original := erg.New(MyKind{}, "my message", errors.New("err1"), errors.New("err1"))
+		deduped := erg.Dedupe(original, erg.KeyByKindAndMessage)
+		_ = erg.Append(deduped, errors.New("err1"))
+
+		ensure(erg.GetCounts(original)).IsEmpty()
+		ensure(erg.GetCounts(deduped)).Equals([]int{2})
+	})
+
+	ensure.Run("with non group error", func(ensure ensurepkg.Ensure) {
+		err := errors.New("not a group")
+		ensure(erg.Dedupe(err, erg.KeyByFingerprint)).Equals(err)
+		ensure(erg.GetCounts(err)).IsEmpty()
+	})
+
+	ensure.Run("exports counts", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my group", erk.New(MyKind{}, "error"), erk.New(MyKind{}, "error"))
+		err = erg.Dedupe(err, erg.KeyByFingerprint)
+
+		bytes, jsonErr := json.Marshal(err)
+		ensure(jsonErr).IsNotError()
+		ensure(string(bytes)).Equals(
+			`{"kind":"` + MyKindString + `","message":"my group",` +
+				`"errors":[{"kind":"` + MyKindString + `","message":"error"}],"counts":[2]}`,
+		)
+	})
+}
+
+func TestLimit(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with more errors than the limit", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message", errors.New("err1"), errors.New("err2"), errors.New("err3"))
+		err = erg.Limit(err, 1)
+
+		ensure(err.Error()).Equals("my message:\n - err1\n - and 2 more")
+		ensure(erg.GetErrors(err)).Equals([]error{errors.New("err1"), errors.New("err2"), errors.New("err3")})
+		ensure(erk.Export(err).(*erg.ExportedGroup).Errors).Equals([]erk.ExportedErkable{
+			erk.Export(errors.New("err1")),
+			erk.Export(errors.New("err2")),
+			erk.Export(errors.New("err3")),
+		})
+	})
+
+	ensure.Run("with fewer errors than the limit", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message", errors.New("err1"))
+		err = erg.Limit(err, 2)
+		ensure(err.Error()).Equals("my message:\n - err1")
+	})
+
+	ensure.Run("with no limit", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message", errors.New("err1"), errors.New("err2"))
+		err = erg.Limit(erg.Limit(err, 1), 0)
+		ensure(err.Error()).Equals("my message:\n - err1\n - err2")
+	})
+
+	ensure.Run("with nested group", func(ensure ensurepkg.Ensure) {
+		nested := erg.Limit(erg.New(MyKind{}, "nested", errors.New("err1"), errors.New("err2")), 1)
+		err := erg.New(MyKind{}, "my message", nested)
+		ensure(err.Error()).Equals("my message:\n - nested:\n   - err1\n   - and 1 more")
+	})
+
+	ensure.Run("with deduplicated group", func(ensure ensurepkg.Ensure) {
+		err := erg.New(MyKind{}, "my message", errors.New("err1"), errors.New("err1"), errors.New("err2"), errors.New("err3"))
+		err = erg.Limit(erg.Dedupe(err, erg.KeyByKindAndMessage), 1)
+		ensure(err.Error()).Equals("my message:\n - (x2) err1\n - and 2 more")
+	})
+
+	ensure.Run("with non group error", func(ensure ensurepkg.Ensure) {
+		err := errors.New("not a group")
+		ensure(erg.Limit(err, 1)).Equals(err)
+	})
+}
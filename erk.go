@@ -38,13 +38,28 @@ func WrapWith(erkError error, err error, params Params) error {
 
 // ToErk converts an error to an erk.Erkable by wrapping it in an erk.Error.
 // If it is already an erk.Erkable, it returns the error without wrapping it.
+//
+// Errors recognized by a classifier, such as context errors, are converted using the classifier.
+// Otherwise, the error is wrapped with a nil kind.
+// See Classify.
 func ToErk(err error) Erkable {
 	var e Erkable
 	if errors.As(err, &e) {
 		return e
 	}
 
-	wrappedErr := Wrap(nil, err.Error(), err).(*Error) //nolint:forcetypeassert // We know this is an Error
-	wrappedErr.builtFromRegularError = err
-	return wrappedErr
+	classified := classify(err)
+	if classified == nil {
+		wrappedErr := Wrap(nil, err.Error(), err).(*Error) //nolint:forcetypeassert // We know this is an Error
+		wrappedErr.builtFromRegularError = err
+		return wrappedErr
+	}
+
+	if classifiedErr, ok := classified.(*Error); ok {
+		wrappedErr := classifiedErr.clone() // Clone, since the classifier could return a shared error
+		wrappedErr.builtFromRegularError = err
+		return wrappedErr
+	}
+
+	return classified
 }
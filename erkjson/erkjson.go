@@ -107,7 +107,10 @@ func ExportError(originalError error) error {
 }
 
 func getJSONWrapper(err erk.Kindable) JSONWrapable {
-	kind := err.Kind()
+	return getJSONWrapperForKind(err.Kind())
+}
+
+func getJSONWrapperForKind(kind erk.Kind) JSONWrapable {
 	wrapable, ok := kind.(JSONWrapable)
 	if ok && !wrapable.IsNil() {
 		return wrapable
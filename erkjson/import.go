@@ -0,0 +1,83 @@
+package erkjson
+
+import (
+	"encoding/json"
+
+	"github.com/JosiahWitt/erk"
+)
+
+// ErkImport is the kind of errors returned when importing a JSON error fails.
+type ErkImport struct{ erk.DefaultKind }
+
+// ErrInvalidJSONError is returned when the JSON cannot be imported as an error.
+var ErrInvalidJSONError = erk.New(ErkImport{}, "unable to import the JSON error: {{.err}}")
+
+// ErkUnregistered is the kind of imported errors whose kind string is not registered with erk.RegisterKind or erk.RegisterKindCode.
+// The kind string of the imported error is preserved.
+type ErkUnregistered struct {
+	erk.DefaultKind
+	kindString string
+}
+
+// KindStringFor the provided kind.
+func (k ErkUnregistered) KindStringFor(erk.Kind) string {
+	return k.kindString
+}
+
+type importedError struct {
+	Kind       *string         `json:"kind"`
+	Message    string          `json:"message"`
+	Params     erk.Params      `json:"params"`
+	Metadata   erk.Metadata    `json:"metadata"`
+	ErrorStack []importedError `json:"errorStack"`
+}
+
+// ImportError parses JSON produced by ExportError, or by marshalling an erk error, back into an erk error.
+//
+// The kind is resolved using erk.LookupKind, so old kind strings registered as aliases resolve to the current kind.
+// If the kind string is not registered, the kind is ErkUnregistered, which preserves the kind string.
+// The message is the exported message, and is not executed as a template (see erk.NewLiteral).
+// Errors in the error stack are wrapped using the err param, so errors.Unwrap walks the error stack.
+func ImportError(jsonError []byte) (erk.Erkable, error) {
+	var imported importedError
+	if err := json.Unmarshal(jsonError, &imported); err != nil {
+		return nil, erk.WrapAs(ErrInvalidJSONError, err)
+	}
+
+	var wrapped error
+	for i := len(imported.ErrorStack) - 1; i >= 0; i-- {
+		wrapped = imported.ErrorStack[i].build(wrapped)
+	}
+
+	return imported.build(wrapped), nil
+}
+
+func (e *importedError) build(wrapped error) erk.Erkable {
+	params := e.Params.Clone()
+	if wrapped != nil {
+		if params == nil {
+			params = erk.Params{}
+		}
+
+		params[erk.OriginalErrorParam] = wrapped
+	}
+
+	err := erk.NewLiteral(e.kind(), e.Message, params)
+	if len(e.Metadata) > 0 {
+		err = erk.WithMetadata(err, e.Metadata)
+	}
+
+	return err.(erk.Erkable) //nolint:forcetypeassert // erk.NewWith always returns an erk.Erkable
+}
+
+func (e *importedError) kind() erk.Kind {
+	if e.Kind == nil {
+		return nil
+	}
+
+	if kind, ok := erk.LookupKind(*e.Kind); ok {
+		return kind
+	}
+
+	return ErkUnregistered{kindString: *e.Kind}
+}
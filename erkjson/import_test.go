@@ -0,0 +1,99 @@
+package erkjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erkjson"
+)
+
+type ErkImportNotFound struct {
+	erk.DefaultPtrKind `erk:"example:not_found"`
+	erkjson.JSONWrapper
+}
+
+type ErkImportDatabase struct {
+	erk.DefaultKind `erk:"example:database"`
+}
+
+type ErkImportPlaceholder struct {
+	erk.DefaultKind `erk:"example:placeholder"`
+}
+
+func (ErkImportPlaceholder) MessageFormatFor(erk.Kind) erk.MessageFormat {
+	return erk.MessageFormatPlaceholder
+}
+
+func TestImportError(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("round trips an exported error", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+		ensure(erk.RegisterKind(&ErkImportNotFound{})).IsNotError()
+		ensure(erk.RegisterKind(ErkImportDatabase{}, "github.com/JosiahWitt/erk/erkjson_test:ErkImportDatabase")).IsNotError()
+
+		dbErr := erk.NewWith(ErkImportDatabase{}, "query {{.query}} failed", erk.Params{"query": "select"})
+		original := erk.WithMetadata(
+			erk.WrapWith(erk.New(&ErkImportNotFound{}, "{{.key}} was not found: {{.err}}"), dbErr, erk.Params{"key": "{{x}}"}),
+			erk.Metadata{"requestID": "123"},
+		)
+
+		imported, err := erkjson.ImportError([]byte(erkjson.ExportError(original).Error()))
+		ensure(err).IsNotError()
+		ensure(imported.Error()).Equals(original.Error())
+		ensure(erk.GetKind(imported)).Equals(&ErkImportNotFound{})
+		ensure(erk.GetParams(imported)).Equals(erk.Params{"key": "{{x}}", "err": errors.Unwrap(imported)})
+		ensure(erk.GetMetadata(imported)).Equals(erk.Metadata{"requestID": "123"})
+
+		wrapped := errors.Unwrap(imported)
+		ensure(wrapped.Error()).Equals("query select failed")
+		ensure(erk.GetKind(wrapped)).Equals(ErkImportDatabase{})
+		ensure(erk.GetParams(wrapped)).Equals(erk.Params{"query": "select"})
+	})
+
+	ensure.Run("round trips an error with a placeholder message", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+		ensure(erk.RegisterKind(ErkImportPlaceholder{})).IsNotError()
+
+		original := erk.NewWith(ErkImportPlaceholder{}, "item {key} has {{braces}}", erk.Params{"key": "{x}"})
+
+		imported, err := erkjson.ImportError([]byte(erkjson.ExportError(original).Error()))
+		ensure(err).IsNotError()
+		ensure(imported.Error()).Equals("item {x} has {braces}")
+		ensure(imported.Error()).Equals(original.Error())
+		ensure(erk.GetKind(imported)).Equals(ErkImportPlaceholder{})
+	})
+
+	ensure.Run("resolves aliases", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+		ensure(erk.RegisterKind(ErkImportDatabase{}, "example:old_database")).IsNotError()
+
+		imported, err := erkjson.ImportError([]byte(`{"kind":"example:old_database","message":"failed"}`))
+		ensure(err).IsNotError()
+		ensure(erk.GetKind(imported)).Equals(ErkImportDatabase{})
+		ensure(erk.GetKindString(imported)).Equals("example:database")
+	})
+
+	ensure.Run("preserves unregistered kind strings", func(ensure ensurepkg.Ensure) {
+		imported, err := erkjson.ImportError([]byte(`{"kind":"example:unknown","message":"failed"}`))
+		ensure(err).IsNotError()
+		ensure(erk.GetKindString(imported)).Equals("example:unknown")
+		ensure(erk.IsKind(imported, erkjson.ErkUnregistered{})).IsTrue()
+	})
+
+	ensure.Run("with null kind", func(ensure ensurepkg.Ensure) {
+		imported, err := erkjson.ImportError([]byte(`{"kind":null,"message":"failed"}`))
+		ensure(err).IsNotError()
+		ensure(erk.GetKind(imported)).IsNil()
+		ensure(imported.Error()).Equals("failed")
+	})
+
+	ensure.Run("with invalid JSON", func(ensure ensurepkg.Ensure) {
+		imported, err := erkjson.ImportError([]byte(`{`))
+		ensure(err).MatchesAllErrors(erkjson.ErrInvalidJSONError)
+		ensure(imported).IsNil()
+	})
+}
@@ -0,0 +1,203 @@
+package erkjson
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erkretry"
+)
+
+// ErrorName returns the error name AWS Step Functions sees when a Lambda function returns ExportError for an error with the kind.
+//
+// The AWS Lambda Go runtime uses the name of the returned error's type, without the package or pointer.
+// Thus, this is the name of the kind's type if the kind implements JSONWrapable, or "JSONWrapper" otherwise.
+func ErrorName(kind erk.Kind) string {
+	t := reflect.TypeOf(getJSONWrapperForKind(kind))
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
+
+// StepFunctionsRetrier is an AWS Step Functions Retry block.
+type StepFunctionsRetrier struct {
+	ErrorEquals     []string `json:"ErrorEquals"`
+	IntervalSeconds int      `json:"IntervalSeconds,omitempty"`
+	MaxAttempts     int      `json:"MaxAttempts"`
+	BackoffRate     float64  `json:"BackoffRate,omitempty"`
+	MaxDelaySeconds int      `json:"MaxDelaySeconds,omitempty"`
+	JitterStrategy  string   `json:"JitterStrategy,omitempty"`
+}
+
+// StepFunctionsCatcher is an AWS Step Functions Catch block.
+type StepFunctionsCatcher struct {
+	ErrorEquals []string `json:"ErrorEquals"`
+	Next        string   `json:"Next"`
+	ResultPath  string   `json:"ResultPath,omitempty"`
+}
+
+// StepFunctions generates AWS Step Functions Retry and Catch blocks for registered kinds.
+// The error names match the error names produced by ExportError.
+//
+// Example:
+//
+//	sfn := erkjson.NewStepFunctions()
+//	if err := sfn.Register(&ErkThrottled{}, &ErkNotFound{}); err != nil {
+//	  ...
+//	}
+//
+//	retriers, err := sfn.Retriers(erkretry.Policy{MaxAttempts: 3})
+//	if err != nil {
+//	  ...
+//	}
+//
+//	retriersJSON, _ := json.MarshalIndent(retriers, "", "  ")
+//	catchers, _ := json.MarshalIndent(sfn.Catchers("HandleError", "$.error"), "", "  ")
+type StepFunctions struct {
+	kinds []erk.Kind
+	types map[string]reflect.Type
+}
+
+// NewStepFunctions creates an empty StepFunctions generator.
+func NewStepFunctions() *StepFunctions {
+	return &StepFunctions{types: map[string]reflect.Type{}}
+}
+
+// Register kinds to include in the generated blocks.
+//
+// An error is returned if a kind has the same error name as a different registered kind,
+// since AWS Step Functions would not be able to distinguish between them.
+// Kinds that are not JSONWrapable are rejected, since their errors are all named "JSONWrapper".
+func (s *StepFunctions) Register(kinds ...erk.Kind) error {
+	for _, kind := range kinds {
+		if _, ok := getJSONWrapperForKind(kind).(*JSONWrapper); ok {
+			return fmt.Errorf("erkjson: kind %T does not implement JSONWrapable", kind) //nolint:err113 // Only returned during setup
+		}
+
+		name := ErrorName(kind)
+		kindType := reflect.TypeOf(kind)
+
+		if existingType, ok := s.types[name]; ok {
+			if existingType != kindType {
+				//nolint:err113 // Only returned during setup
+				return fmt.Errorf("erkjson: kinds %s and %s have the same error name %q", existingType, kindType, name)
+			}
+
+			continue
+		}
+
+		s.types[name] = kindType
+		s.kinds = append(s.kinds, kind)
+	}
+
+	return nil
+}
+
+// ErrorNames of the registered kinds, in the order they were registered.
+func (s *StepFunctions) ErrorNames() []string {
+	names := make([]string, 0, len(s.kinds))
+	for _, kind := range s.kinds {
+		names = append(names, ErrorName(kind))
+	}
+
+	return names
+}
+
+// Retriers for the registered kinds that are retryable, according to erkretry.
+//
+// Zero policy fields use the erkretry defaults, so the generated blocks match erkretry.Retry.
+// Kinds are grouped by their MaxAttempts hint, in the order they were registered.
+// The policy's MaxAttempts includes the first attempt, but the AWS Step Functions MaxAttempts does not.
+// Thus, the generated MaxAttempts is one less than the number of attempts,
+// and kinds limited to a single attempt are omitted, since they are never retried.
+//
+// AWS Step Functions only supports delays in whole seconds, so delays are rounded up to the next second.
+// An error is returned if the policy cannot be represented:
+// the Multiplier must be at least 1, and the Jitter must be 0 or 1, since AWS Step Functions only supports full jitter.
+func (s *StepFunctions) Retriers(policy erkretry.Policy) ([]StepFunctionsRetrier, error) {
+	policy = policy.WithDefaults()
+	if err := validateRetryPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	retriers := []StepFunctionsRetrier{}
+	indexByAttempts := map[int]int{}
+
+	for _, kind := range s.kinds {
+		hints := erkretry.Hints{}
+		if retrier, ok := kind.(erkretry.Retrier); ok {
+			hints = retrier.RetryHintsFor(kind)
+		}
+
+		attempts := policy.MaxAttemptsFor(hints)
+		if !hints.Retryable || attempts <= 1 {
+			continue
+		}
+
+		if i, ok := indexByAttempts[attempts]; ok {
+			retriers[i].ErrorEquals = append(retriers[i].ErrorEquals, ErrorName(kind))
+			continue
+		}
+
+		indexByAttempts[attempts] = len(retriers)
+		retriers = append(retriers, buildRetrier(policy, attempts, ErrorName(kind)))
+	}
+
+	return retriers, nil
+}
+
+// Catchers for all registered kinds, transitioning to the next state.
+// The resultPath is optional, and is omitted if empty.
+func (s *StepFunctions) Catchers(next, resultPath string) []StepFunctionsCatcher {
+	if len(s.kinds) == 0 {
+		return []StepFunctionsCatcher{}
+	}
+
+	return []StepFunctionsCatcher{
+		{
+			ErrorEquals: s.ErrorNames(),
+			Next:        next,
+			ResultPath:  resultPath,
+		},
+	}
+}
+
+func validateRetryPolicy(policy erkretry.Policy) error {
+	if policy.Multiplier < 1 {
+		//nolint:err113 // Only returned during setup
+		return fmt.Errorf("erkjson: multiplier %v is less than 1, which AWS Step Functions does not support", policy.Multiplier)
+	}
+
+	if policy.Jitter > 0 && policy.Jitter < 1 {
+		//nolint:err113 // Only returned during setup
+		return fmt.Errorf("erkjson: jitter %v is not 0 or 1, which AWS Step Functions does not support", policy.Jitter)
+	}
+
+	return nil
+}
+
+func buildRetrier(policy erkretry.Policy, attempts int, errorName string) StepFunctionsRetrier {
+	retrier := StepFunctionsRetrier{
+		ErrorEquals:     []string{errorName},
+		IntervalSeconds: ceilSeconds(policy.InitialDelay),
+		MaxAttempts:     attempts - 1,
+		BackoffRate:     policy.Multiplier,
+		MaxDelaySeconds: ceilSeconds(policy.MaxDelay),
+		JitterStrategy:  "",
+	}
+
+	if policy.Jitter >= 1 {
+		retrier.JitterStrategy = "FULL"
+	}
+
+	return retrier
+}
+
+// ceilSeconds rounds the duration up to whole seconds, so AWS Step Functions never retries sooner than erkretry.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,197 @@
+package erkjson_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erkjson"
+	"github.com/JosiahWitt/erk/erkretry"
+)
+
+type TestRetryableKind struct {
+	erk.DefaultPtrKind
+	erkjson.JSONWrapper
+	erkretry.RetryableKind
+}
+
+type TestLimitedKind struct {
+	erk.DefaultPtrKind
+	erkjson.JSONWrapper
+}
+
+func (*TestLimitedKind) RetryHintsFor(erk.Kind) erkretry.Hints {
+	return erkretry.Hints{Retryable: true, MaxAttempts: 2}
+}
+
+type TestOtherLimitedKind struct{ TestLimitedKind }
+
+func TestErrorName(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name     string
+		Kind     erk.Kind
+		Expected string
+	}{
+		{Name: "with pointer", Kind: &TestPtrWrapableKind{}, Expected: "TestPtrWrapableKind"},
+		{Name: "with pointer but not wrapable", Kind: &TestPtrNonWrapableKind{}, Expected: "JSONWrapper"},
+		{Name: "with value", Kind: TestValueWrapableKind{}, Expected: "JSONWrapper"},
+		{Name: "with value embedding nil pointer", Kind: TestValueWithPtrWrapableKind{}, Expected: "JSONWrapper"},
+		{Name: "with nil kind", Kind: nil, Expected: "JSONWrapper"},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+		ensure(erkjson.ErrorName(entry.Kind)).Equals(entry.Expected)
+
+		// Ensure the name matches the type returned by ExportError
+		exportedType := fmt.Sprintf("%T", erkjson.ExportError(erk.New(entry.Kind, "my message")))
+		ensure(exportedType[strings.LastIndex(exportedType, ".")+1:]).Equals(entry.Expected)
+	})
+}
+
+func TestStepFunctionsRegister(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with valid kinds", func(ensure ensurepkg.Ensure) {
+		sfn := erkjson.NewStepFunctions()
+		ensure(sfn.Register(&TestPtrWrapableKind{}, &TestRetryableKind{})).IsNotError()
+		ensure(sfn.Register(&TestPtrWrapableKind{})).IsNotError() // Registering twice is ignored
+		ensure(sfn.ErrorNames()).Equals([]string{"TestPtrWrapableKind", "TestRetryableKind"})
+	})
+
+	ensure.Run("with non wrapable kind", func(ensure ensurepkg.Ensure) {
+		sfn := erkjson.NewStepFunctions()
+		err := sfn.Register(&TestPtrNonWrapableKind{})
+		ensure(err.Error()).Equals("erkjson: kind *erkjson_test.TestPtrNonWrapableKind does not implement JSONWrapable")
+	})
+
+	ensure.Run("with duplicate error name", func(ensure ensurepkg.Ensure) {
+		globalKind := &TestPtrWrapableKind{}
+
+		type TestPtrWrapableKind struct {
+			erk.DefaultPtrKind
+			erkjson.JSONWrapper
+		}
+
+		sfn := erkjson.NewStepFunctions()
+		err := sfn.Register(globalKind, &TestPtrWrapableKind{})
+		ensure(err.Error()).Equals(
+			"erkjson: kinds *erkjson_test.TestPtrWrapableKind and *erkjson_test.TestPtrWrapableKind have the same error name \"TestPtrWrapableKind\"",
+		)
+	})
+}
+
+func TestStepFunctionsRetriers(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with retryable kinds", func(ensure ensurepkg.Ensure) {
+		sfn := erkjson.NewStepFunctions()
+		ensure(sfn.Register(
+			&TestRetryableKind{},
+			&TestPtrWrapableKind{},
+			&TestLimitedKind{},
+			&TestOtherLimitedKind{},
+		)).IsNotError()
+
+		retriers, err := sfn.Retriers(erkretry.Policy{
+			MaxAttempts:  4,
+			InitialDelay: 1500 * time.Millisecond,
+			MaxDelay:     time.Minute,
+			Multiplier:   1.5,
+			Jitter:       1,
+		})
+		ensure(err).IsNotError()
+
+		bytes, err := json.Marshal(retriers)
+		ensure(err).IsNotError()
+		ensure(string(bytes)).Equals(
+			`[{"ErrorEquals":["TestRetryableKind"],"IntervalSeconds":2,"MaxAttempts":3,"BackoffRate":1.5,"MaxDelaySeconds":60,"JitterStrategy":"FULL"},` +
+				`{"ErrorEquals":["TestLimitedKind","TestOtherLimitedKind"],"IntervalSeconds":2,"MaxAttempts":1,"BackoffRate":1.5,"MaxDelaySeconds":60,"JitterStrategy":"FULL"}]`,
+		)
+	})
+
+	ensure.Run("with default policy", func(ensure ensurepkg.Ensure) {
+		sfn := erkjson.NewStepFunctions()
+		ensure(sfn.Register(&TestRetryableKind{})).IsNotError()
+
+		retriers, err := sfn.Retriers(erkretry.Policy{})
+		ensure(err).IsNotError()
+
+		bytes, err := json.Marshal(retriers)
+		ensure(err).IsNotError()
+		ensure(string(bytes)).Equals(`[{"ErrorEquals":["TestRetryableKind"],"IntervalSeconds":1,"MaxAttempts":2,"BackoffRate":2,"MaxDelaySeconds":30}]`)
+	})
+
+	ensure.Run("with kinds limited to a single attempt", func(ensure ensurepkg.Ensure) {
+		sfn := erkjson.NewStepFunctions()
+		ensure(sfn.Register(&TestRetryableKind{}, &TestLimitedKind{})).IsNotError()
+
+		retriers, err := sfn.Retriers(erkretry.Policy{MaxAttempts: 1})
+		ensure(err).IsNotError()
+		ensure(retriers).IsEmpty()
+	})
+
+	ensure.Run("with no retryable kinds", func(ensure ensurepkg.Ensure) {
+		sfn := erkjson.NewStepFunctions()
+		ensure(sfn.Register(&TestPtrWrapableKind{})).IsNotError()
+
+		retriers, err := sfn.Retriers(erkretry.Policy{})
+		ensure(err).IsNotError()
+		ensure(retriers).IsEmpty()
+	})
+
+	ensure.Run("with unsupported policy", func(ensure ensurepkg.Ensure) {
+		table := []struct {
+			Name          string
+			Policy        erkretry.Policy
+			ExpectedError string
+		}{
+			{
+				Name:          "with multiplier less than 1",
+				Policy:        erkretry.Policy{Multiplier: 0.5},
+				ExpectedError: "erkjson: multiplier 0.5 is less than 1, which AWS Step Functions does not support",
+			},
+			{
+				Name:          "with partial jitter",
+				Policy:        erkretry.Policy{Jitter: 0.5},
+				ExpectedError: "erkjson: jitter 0.5 is not 0 or 1, which AWS Step Functions does not support",
+			},
+		}
+
+		ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+			entry := table[i]
+
+			sfn := erkjson.NewStepFunctions()
+			ensure(sfn.Register(&TestRetryableKind{})).IsNotError()
+
+			retriers, err := sfn.Retriers(entry.Policy)
+			ensure(err.Error()).Equals(entry.ExpectedError)
+			ensure(retriers).Equals([]erkjson.StepFunctionsRetrier(nil))
+		})
+	})
+}
+
+func TestStepFunctionsCatchers(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with kinds", func(ensure ensurepkg.Ensure) {
+		sfn := erkjson.NewStepFunctions()
+		ensure(sfn.Register(&TestRetryableKind{}, &TestPtrWrapableKind{})).IsNotError()
+
+		bytes, err := json.Marshal(sfn.Catchers("HandleError", "$.error"))
+		ensure(err).IsNotError()
+		ensure(string(bytes)).Equals(`[{"ErrorEquals":["TestRetryableKind","TestPtrWrapableKind"],"Next":"HandleError","ResultPath":"$.error"}]`)
+	})
+
+	ensure.Run("with no kinds", func(ensure ensurepkg.Ensure) {
+		sfn := erkjson.NewStepFunctions()
+		ensure(sfn.Catchers("HandleError", "")).IsEmpty()
+	})
+}
@@ -0,0 +1,197 @@
+// Package erklocale localizes error messages using translation catalogs.
+//
+// Translations are keyed by the kind string and raw message template of the error.
+// Translations without a kind string apply to every error with the raw message.
+// Like gettext, the raw message acts as the message ID, and the kind string acts as the context.
+//
+// Example:
+//
+//	catalog := erklocale.NewCatalog()
+//	if err := catalog.LoadFile("locales/fr.po"); err != nil {
+//	  ...
+//	}
+//
+//	erk.SetLocalizer(catalog)
+//
+//	...
+//
+//	message := erk.RenderLocalized(err, "fr-CA")
+//	exported := erk.ExportLocalized(err, "fr-CA")
+package erklocale
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/JosiahWitt/erk"
+)
+
+// Catalog of translations, which implements erk.Localizer.
+// It is safe to use concurrently.
+type Catalog struct {
+	mu        sync.RWMutex
+	messages  map[string]map[messageKey]string
+	fallbacks []string
+	formats   map[string]Format
+}
+
+// Catalog implements erk.Localizer.
+var _ erk.Localizer = &Catalog{}
+
+type messageKey struct {
+	kind    string
+	message string
+}
+
+// NewCatalog creates an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		messages: map[string]map[messageKey]string{},
+		formats:  map[string]Format{},
+	}
+}
+
+// Add a translation of the raw message for the locale.
+// If the kind string is empty, the translation applies to every kind with the raw message.
+func (c *Catalog) Add(locale, kindString, rawMessage, translation string) {
+	locale = NormalizeLocale(locale)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.messages[locale] == nil {
+		c.messages[locale] = map[messageKey]string{}
+	}
+
+	c.messages[locale][messageKey{kind: kindString, message: rawMessage}] = translation
+}
+
+// SetFallbacks sets the locales that are tried, in order, when a translation is not found for the requested locale or its parents.
+func (c *Catalog) SetFallbacks(locales ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fallbacks = make([]string, 0, len(locales))
+	for _, locale := range locales {
+		c.fallbacks = append(c.fallbacks, NormalizeLocale(locale))
+	}
+}
+
+// SetFormat used by the template functions for the locale, overriding the built in format.
+func (c *Catalog) SetFormat(locale string, format Format) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.formats[NormalizeLocale(locale)] = format
+}
+
+// Locales that have translations, sorted alphabetically.
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+
+	sort.Strings(locales)
+	return locales
+}
+
+// LocalizeMessage returns the translated message template for the locale.
+//
+// The locale, its parents (for example, "fr" for "fr-CA"), and then the fallbacks are tried in order.
+// For each locale, a translation for the kind string is preferred over a translation for any kind.
+// If no translation is found, false is returned.
+func (c *Catalog) LocalizeMessage(locale, kindString, rawMessage string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, candidate := range c.localeChain(locale) {
+		messages := c.messages[candidate]
+
+		if translation, ok := messages[messageKey{kind: kindString, message: rawMessage}]; ok {
+			return translation, true
+		}
+
+		if translation, ok := messages[messageKey{message: rawMessage}]; ok {
+			return translation, true
+		}
+	}
+
+	return "", false
+}
+
+// TemplateFuncsFor the locale.
+//
+// The functions are:
+//   - number: formats a number with the locale's decimal and group separators
+//   - date: formats a time.Time with the locale's date layout
+//   - plural: selects the form for the count using the locale's plural rule, for example: {{plural .count "item" "items"}}
+func (c *Catalog) TemplateFuncsFor(locale string) template.FuncMap {
+	format := c.formatFor(locale)
+
+	return template.FuncMap{
+		"number": format.number,
+		"date":   format.date,
+		"plural": format.plural,
+	}
+}
+
+func (c *Catalog) formatFor(locale string) Format {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, candidate := range parentLocales(NormalizeLocale(locale)) {
+		if format, ok := c.formats[candidate]; ok {
+			return format
+		}
+
+		if format, ok := builtInFormats[candidate]; ok {
+			return format
+		}
+	}
+
+	return builtInFormats["en"]
+}
+
+// localeChain returns the locale, its parents, and the fallbacks and their parents, without duplicates.
+func (c *Catalog) localeChain(locale string) []string {
+	chain := []string{}
+	seen := map[string]bool{}
+
+	for _, l := range append([]string{NormalizeLocale(locale)}, c.fallbacks...) {
+		for _, candidate := range parentLocales(l) {
+			if !seen[candidate] {
+				seen[candidate] = true
+				chain = append(chain, candidate)
+			}
+		}
+	}
+
+	return chain
+}
+
+// NormalizeLocale converts the locale to lowercase, and replaces underscores with hyphens.
+// For example, "fr_CA" becomes "fr-ca".
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// parentLocales returns the locale followed by its parents, for example: "zh-hant-tw", "zh-hant", "zh".
+func parentLocales(locale string) []string {
+	if locale == "" {
+		return nil
+	}
+
+	locales := []string{locale}
+	for i := strings.LastIndex(locale, "-"); i > 0; i = strings.LastIndex(locale, "-") {
+		locale = locale[:i]
+		locales = append(locales, locale)
+	}
+
+	return locales
+}
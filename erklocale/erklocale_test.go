@@ -0,0 +1,196 @@
+package erklocale_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+	"github.com/JosiahWitt/erk/erklocale"
+)
+
+type ErkNotFound struct {
+	erk.DefaultKind `erk:"store:not_found"`
+}
+
+type ErkLoad struct {
+	erk.DefaultKind `erk:"store:load"`
+}
+
+type ErkPlaceholder struct{ erk.DefaultKind }
+
+func (ErkPlaceholder) MessageFormatFor(erk.Kind) erk.MessageFormat {
+	return erk.MessageFormatPlaceholder
+}
+
+var (
+	errNotFound = erk.New(ErkNotFound{}, "item {{.key}} was not found")
+	errLoad     = erk.New(ErkLoad{}, "failed to load {{.count}} items: {{.err}}")
+)
+
+func newCatalog() *erklocale.Catalog {
+	catalog := erklocale.NewCatalog()
+	catalog.Add("fr", "store:not_found", "item {{.key}} was not found", "l'élément {{.key}} est introuvable")
+	catalog.Add("fr", "", "failed to load {{.count}} items: {{.err}}", "échec du chargement de {{number .count}} {{plural .count \"élément\" \"éléments\"}} : {{.err}}")
+	catalog.Add("fr-CA", "store:not_found", "item {{.key}} was not found", "l'item {{.key}} est introuvable")
+	catalog.Add("de", "store:not_found", "item {{.key}} was not found", "Element {{.key}} wurde nicht gefunden")
+	return catalog
+}
+
+func TestLocalizeMessage(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name                string
+		Fallbacks           []string
+		Locale              string
+		Kind                string
+		ExpectedTranslation string
+		ExpectedOK          bool
+	}{
+		{
+			Name:                "with exact locale",
+			Locale:              "fr-CA",
+			Kind:                "store:not_found",
+			ExpectedTranslation: "l'item {{.key}} est introuvable",
+			ExpectedOK:          true,
+		},
+		{
+			Name:                "with underscore locale",
+			Locale:              "fr_CA",
+			Kind:                "store:not_found",
+			ExpectedTranslation: "l'item {{.key}} est introuvable",
+			ExpectedOK:          true,
+		},
+		{
+			Name:                "with parent locale",
+			Locale:              "fr-BE",
+			Kind:                "store:not_found",
+			ExpectedTranslation: "l'élément {{.key}} est introuvable",
+			ExpectedOK:          true,
+		},
+		{
+			Name:       "with different kind",
+			Locale:     "fr",
+			Kind:       "other:not_found",
+			ExpectedOK: false,
+		},
+		{
+			Name:                "with fallback locale",
+			Fallbacks:           []string{"de-DE"},
+			Locale:              "es",
+			Kind:                "store:not_found",
+			ExpectedTranslation: "Element {{.key}} wurde nicht gefunden",
+			ExpectedOK:          true,
+		},
+		{
+			Name:       "without translation",
+			Locale:     "es",
+			Kind:       "store:not_found",
+			ExpectedOK: false,
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		catalog := newCatalog()
+		catalog.SetFallbacks(entry.Fallbacks...)
+
+		translation, ok := catalog.LocalizeMessage(entry.Locale, entry.Kind, "item {{.key}} was not found")
+		ensure(translation).Equals(entry.ExpectedTranslation)
+		ensure(ok).Equals(entry.ExpectedOK)
+	})
+}
+
+func TestRenderLocalized(t *testing.T) {
+	ensure := ensure.New(t)
+
+	erk.SetLocalizer(newCatalog())
+	defer erk.SetLocalizer(nil)
+
+	ensure.Run("with translation", func(ensure ensurepkg.Ensure) {
+		err := erk.WithParams(errNotFound, erk.Params{"key": "abc"})
+		ensure(erk.RenderLocalized(err, "fr")).Equals("l'élément abc est introuvable")
+		ensure(err.Error()).Equals("item abc was not found")
+	})
+
+	ensure.Run("with wrapped errors and template functions", func(ensure ensurepkg.Ensure) {
+		err := erk.WrapWith(errLoad, erk.WithParams(errNotFound, erk.Params{"key": "abc"}), erk.Params{"count": 1234})
+		ensure(erk.RenderLocalized(err, "fr")).Equals("échec du chargement de 1\u202f234 éléments : l'élément abc est introuvable")
+		ensure(erk.RenderLocalized(err, "de")).Equals("failed to load 1234 items: Element abc wurde nicht gefunden")
+	})
+
+	ensure.Run("falls back when the translation fails to render", func(ensure ensurepkg.Ensure) {
+		catalog := erklocale.NewCatalog()
+		catalog.Add("fr", "", "item {{.key}} was not found", "l'élément {{.missing}} est introuvable")
+		erk.SetLocalizer(catalog)
+		defer erk.SetLocalizer(newCatalog())
+
+		err := erk.WithParams(errNotFound, erk.Params{"key": "abc"})
+		ensure(erk.RenderLocalized(err, "fr")).Equals("item abc was not found")
+	})
+
+	ensure.Run("with placeholder message format", func(ensure ensurepkg.Ensure) {
+		catalog := erklocale.NewCatalog()
+		catalog.Add("fr", "", "table {tableName} does not exist", "la table {tableName} n'existe pas")
+		catalog.Add("fr", "", "table {name} is locked", "la table {missing} est verrouillée")
+		erk.SetLocalizer(catalog)
+		defer erk.SetLocalizer(newCatalog())
+
+		err := erk.NewWith(ErkPlaceholder{}, "table {tableName} does not exist", erk.Params{"tableName": "users"})
+		ensure(erk.RenderLocalized(err, "fr")).Equals("la table users n'existe pas")
+
+		err = erk.NewWith(ErkPlaceholder{}, "table {name} is locked", erk.Params{"name": "users"})
+		ensure(erk.RenderLocalized(err, "fr")).Equals("table users is locked")
+	})
+
+	ensure.Run("with empty locale", func(ensure ensurepkg.Ensure) {
+		err := erk.WithParams(errNotFound, erk.Params{"key": "abc"})
+		ensure(erk.RenderLocalized(err, "")).Equals("item abc was not found")
+	})
+
+	ensure.Run("with non erk error", func(ensure ensurepkg.Ensure) {
+		ensure(erk.RenderLocalized(errors.New("my error"), "fr")).Equals("my error")
+	})
+
+	ensure.Run("with error group", func(ensure ensurepkg.Ensure) {
+		err := erg.NewAs(
+			erk.WithParams(errLoad, erk.Params{"count": 2, "err": "group"}),
+			erk.WithParams(errNotFound, erk.Params{"key": "a"}),
+			erk.WithParams(errNotFound, erk.Params{"key": "b"}),
+		)
+
+		ensure(erk.RenderLocalized(err, "fr")).Equals(
+			"échec du chargement de 2 éléments : group:\n" +
+				" - l'élément a est introuvable\n" +
+				" - l'élément b est introuvable",
+		)
+	})
+}
+
+func TestExportLocalized(t *testing.T) {
+	ensure := ensure.New(t)
+
+	erk.SetLocalizer(newCatalog())
+	defer erk.SetLocalizer(nil)
+
+	ensure.Run("with wrapped errors", func(ensure ensurepkg.Ensure) {
+		err := erk.WrapWith(errLoad, erk.WithParams(errNotFound, erk.Params{"key": "abc"}), erk.Params{"count": 1})
+
+		exported := erk.ExportLocalized(err, "fr").(*erk.ExportedError)
+		ensure(exported.Message).Equals("échec du chargement de 1 élément : l'élément abc est introuvable")
+		ensure(exported.ErrorStack[0].ErrorMessage()).Equals("l'élément abc est introuvable")
+		ensure(exported.Params).Equals(erk.Params{"count": 1})
+	})
+
+	ensure.Run("with error group", func(ensure ensurepkg.Ensure) {
+		err := erg.New(ErkLoad{}, "failed", erk.WithParams(errNotFound, erk.Params{"key": "a"}))
+
+		exported := erk.ExportLocalized(err, "de").(*erg.ExportedGroup)
+		ensure(exported.Message).Equals("failed")
+		ensure(exported.Errors[0].ErrorMessage()).Equals("Element a wurde nicht gefunden")
+	})
+}
@@ -0,0 +1,232 @@
+package erklocale
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format of numbers, dates, and plurals for a locale.
+type Format struct {
+	// Decimal separator, such as "." or ",".
+	Decimal string
+
+	// Group separator between every three digits, such as "," or ".".
+	Group string
+
+	// DateLayout used to format dates, using the layout syntax of the time package.
+	DateLayout string
+
+	// Plural returns the index of the plural form to use for the count.
+	// If nil, the first form is used for a count of 1, and the second form otherwise.
+	Plural PluralRule
+}
+
+// PluralRule returns the index of the plural form to use for the count.
+// The forms are passed to the plural template function in the order the rule expects.
+type PluralRule func(count int64) int
+
+// Built in plural rules, based on the Unicode CLDR plural rules for integers.
+//
+//nolint:gochecknoglobals // Read only
+var (
+	// PluralOneOther uses the first form for 1, and the second form otherwise, as in English or German.
+	PluralOneOther PluralRule = func(n int64) int {
+		if n == 1 {
+			return 0
+		}
+
+		return 1
+	}
+
+	// PluralZeroOneOther uses the first form for 0 and 1, and the second form otherwise, as in French or Portuguese.
+	PluralZeroOneOther PluralRule = func(n int64) int {
+		if n == 0 || n == 1 {
+			return 0
+		}
+
+		return 1
+	}
+
+	// PluralNone always uses the first form, as in Japanese or Chinese.
+	PluralNone PluralRule = func(int64) int {
+		return 0
+	}
+
+	// PluralEastSlavic uses the one, few, and many forms, as in Russian or Ukrainian.
+	PluralEastSlavic PluralRule = func(n int64) int {
+		mod10, mod100 := n%10, n%100
+
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return 0
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	// PluralPolish uses the one, few, and many forms, as in Polish.
+	PluralPolish PluralRule = func(n int64) int {
+		mod10, mod100 := n%10, n%100
+
+		switch {
+		case n == 1:
+			return 0
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	// PluralWestSlavic uses the one, few, and other forms, as in Czech or Slovak.
+	PluralWestSlavic PluralRule = func(n int64) int {
+		switch {
+		case n == 1:
+			return 0
+		case n >= 2 && n <= 4:
+			return 1
+		default:
+			return 2
+		}
+	}
+)
+
+// Built in formats, keyed by normalized locale.
+//
+//nolint:gochecknoglobals // Read only
+var builtInFormats = map[string]Format{
+	"en":    {Decimal: ".", Group: ",", DateLayout: "Jan 2, 2006", Plural: PluralOneOther},
+	"en-gb": {Decimal: ".", Group: ",", DateLayout: "2 Jan 2006", Plural: PluralOneOther},
+	"de":    {Decimal: ",", Group: ".", DateLayout: "02.01.2006", Plural: PluralOneOther},
+	"es":    {Decimal: ",", Group: ".", DateLayout: "02/01/2006", Plural: PluralOneOther},
+	"it":    {Decimal: ",", Group: ".", DateLayout: "02/01/2006", Plural: PluralOneOther},
+	"nl":    {Decimal: ",", Group: ".", DateLayout: "02-01-2006", Plural: PluralOneOther},
+	"fr":    {Decimal: ",", Group: "\u202f", DateLayout: "02/01/2006", Plural: PluralZeroOneOther},
+	"pt":    {Decimal: ",", Group: ".", DateLayout: "02/01/2006", Plural: PluralZeroOneOther},
+	"ru":    {Decimal: ",", Group: "\u00a0", DateLayout: "02.01.2006", Plural: PluralEastSlavic},
+	"uk":    {Decimal: ",", Group: "\u00a0", DateLayout: "02.01.2006", Plural: PluralEastSlavic},
+	"pl":    {Decimal: ",", Group: "\u00a0", DateLayout: "02.01.2006", Plural: PluralPolish},
+	"cs":    {Decimal: ",", Group: "\u00a0", DateLayout: "02.01.2006", Plural: PluralWestSlavic},
+	"sk":    {Decimal: ",", Group: "\u00a0", DateLayout: "02.01.2006", Plural: PluralWestSlavic},
+	"ja":    {Decimal: ".", Group: ",", DateLayout: "2006/01/02", Plural: PluralNone},
+	"zh":    {Decimal: ".", Group: ",", DateLayout: "2006/01/02", Plural: PluralNone},
+	"ko":    {Decimal: ".", Group: ",", DateLayout: "2006. 01. 02.", Plural: PluralNone},
+}
+
+// number formats integers and floats with the decimal and group separators.
+// Other values are formatted with fmt.Sprint.
+func (f Format) number(v interface{}) string {
+	var digits string
+
+	value := reflect.ValueOf(v)
+	switch value.Kind() { //nolint:exhaustive // Other kinds are handled by the default case
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		digits = strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		digits = strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		if math.IsInf(value.Float(), 0) || math.IsNaN(value.Float()) {
+			return fmt.Sprint(v)
+		}
+
+		digits = strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits())
+	default:
+		return fmt.Sprint(v)
+	}
+
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	integer, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		integer, fraction = digits[:i], digits[i+1:]
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(f.Group)
+		}
+
+		b.WriteRune(digit)
+	}
+
+	if fraction != "" {
+		b.WriteString(f.Decimal)
+		b.WriteString(fraction)
+	}
+
+	return b.String()
+}
+
+// date formats a time.Time or *time.Time with the date layout.
+// A nil *time.Time is formatted as an empty string, and other values are formatted with fmt.Sprint.
+func (f Format) date(v interface{}) string {
+	switch t := v.(type) {
+	case time.Time:
+		return t.Format(f.DateLayout)
+	case *time.Time:
+		if t == nil {
+			return ""
+		}
+
+		return t.Format(f.DateLayout)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// plural returns the form for the count using the plural rule.
+// If the rule selects a form that was not provided, the last form is used.
+// Counts that are not numbers use the last form.
+func (f Format) plural(count interface{}, forms ...string) string {
+	if len(forms) == 0 {
+		return ""
+	}
+
+	n, ok := toInt64(count)
+	if !ok {
+		return forms[len(forms)-1]
+	}
+
+	rule := f.Plural
+	if rule == nil {
+		rule = PluralOneOther
+	}
+
+	if n < 0 {
+		n = -n
+	}
+
+	index := rule(n)
+	if index < 0 || index >= len(forms) {
+		index = len(forms) - 1
+	}
+
+	return forms[index]
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	value := reflect.ValueOf(v)
+
+	switch value.Kind() { //nolint:exhaustive // Other kinds are not numbers
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(value.Uint()), true //nolint:gosec // Counts do not overflow
+	case reflect.Float32, reflect.Float64:
+		return int64(value.Float()), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,71 @@
+package erklocale_test
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk/erklocale"
+)
+
+func TestTemplateFuncsFor(t *testing.T) {
+	ensure := ensure.New(t)
+
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	var nilDate *time.Time
+
+	table := []struct {
+		Name     string
+		Locale   string
+		Template string
+		Value    interface{}
+		Expected string
+	}{
+		{Name: "number with en", Locale: "en", Template: "{{number .}}", Value: 1234567.25, Expected: "1,234,567.25"},
+		{Name: "number with de", Locale: "de-DE", Template: "{{number .}}", Value: 1234567.25, Expected: "1.234.567,25"},
+		{Name: "number with negative", Locale: "en", Template: "{{number .}}", Value: -1234, Expected: "-1,234"},
+		{Name: "number with small", Locale: "en", Template: "{{number .}}", Value: uint8(12), Expected: "12"},
+		{Name: "number with non number", Locale: "en", Template: "{{number .}}", Value: "abc", Expected: "abc"},
+		{Name: "number with nil", Locale: "en", Template: "{{number .}}", Value: nil, Expected: "<nil>"},
+		{Name: "number with unknown locale", Locale: "xx", Template: "{{number .}}", Value: 1234, Expected: "1,234"},
+		{Name: "date with en", Locale: "en-US", Template: "{{date .}}", Value: date, Expected: "Mar 5, 2024"},
+		{Name: "date with en-GB", Locale: "en-GB", Template: "{{date .}}", Value: &date, Expected: "5 Mar 2024"},
+		{Name: "date with de", Locale: "de", Template: "{{date .}}", Value: date, Expected: "05.03.2024"},
+		{Name: "date with nil", Locale: "de", Template: "{{date .}}", Value: nilDate, Expected: ""},
+		{Name: "plural with en one", Locale: "en", Template: `{{plural . "item" "items"}}`, Value: 1, Expected: "item"},
+		{Name: "plural with en other", Locale: "en", Template: `{{plural . "item" "items"}}`, Value: 0, Expected: "items"},
+		{Name: "plural with fr zero", Locale: "fr", Template: `{{plural . "élément" "éléments"}}`, Value: 0, Expected: "élément"},
+		{Name: "plural with ru few", Locale: "ru", Template: `{{plural . "файл" "файла" "файлов"}}`, Value: 22, Expected: "файла"},
+		{Name: "plural with ru many", Locale: "ru", Template: `{{plural . "файл" "файла" "файлов"}}`, Value: 12, Expected: "файлов"},
+		{Name: "plural with ru one", Locale: "ru", Template: `{{plural . "файл" "файла" "файлов"}}`, Value: 21, Expected: "файл"},
+		{Name: "plural with missing form", Locale: "ru", Template: `{{plural . "файл" "файла"}}`, Value: 5, Expected: "файла"},
+		{Name: "plural with ja", Locale: "ja", Template: `{{plural . "件"}}`, Value: 5, Expected: "件"},
+		{Name: "plural with non number", Locale: "en", Template: `{{plural . "item" "items"}}`, Value: nil, Expected: "items"},
+		{Name: "plural without forms", Locale: "en", Template: `{{plural .}}`, Value: 1, Expected: ""},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		tmpl, err := template.New("").Funcs(erklocale.NewCatalog().TemplateFuncsFor(entry.Locale)).Parse(entry.Template)
+		ensure(err).IsNotError()
+
+		var b bytes.Buffer
+		ensure(tmpl.Execute(&b, entry.Value)).IsNotError()
+		ensure(b.String()).Equals(entry.Expected)
+	})
+}
+
+func TestSetFormat(t *testing.T) {
+	ensure := ensure.New(t)
+
+	catalog := erklocale.NewCatalog()
+	catalog.SetFormat("en", erklocale.Format{Decimal: ".", Group: "'", Plural: erklocale.PluralNone})
+
+	funcs := catalog.TemplateFuncsFor("en-US")
+	ensure(funcs["number"].(func(interface{}) string)(12345)).Equals("12'345")
+	ensure(funcs["plural"].(func(interface{}, ...string) string)(2, "a", "b")).Equals("a")
+}
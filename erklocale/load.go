@@ -0,0 +1,245 @@
+package erklocale
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/JosiahWitt/erk"
+)
+
+// ErkLoad is the kind of errors returned when loading translations.
+type ErkLoad struct{ erk.DefaultKind }
+
+// Errors returned when loading translations.
+var (
+	ErrOpen              = erk.New(ErkLoad{}, "failed to open {{.path}}: {{.err}}")
+	ErrInvalidJSON       = erk.New(ErkLoad{}, "invalid JSON translations: {{.err}}")
+	ErrInvalidPO         = erk.New(ErkLoad{}, "invalid PO translations on line {{.line}}: {{.reason}}")
+	ErrMissingLocale     = erk.New(ErkLoad{}, "the locale of the translations is not declared")
+	ErrUnsupportedFormat = erk.New(ErkLoad{}, "unsupported translations file {{.path}}, expected a .json or .po file")
+)
+
+// JSONFile is the format of JSON translation files.
+//
+// Example:
+//
+//	{
+//	  "locale": "fr",
+//	  "messages": [
+//	    {
+//	      "kind": "store:not_found",
+//	      "message": "item {{.key}} was not found",
+//	      "translation": "l'élément {{.key}} est introuvable"
+//	    }
+//	  ]
+//	}
+type JSONFile struct {
+	Locale   string        `json:"locale"`
+	Messages []JSONMessage `json:"messages"`
+}
+
+// JSONMessage is a translation in a JSON translation file.
+// If Kind is empty, the translation applies to every kind with the message.
+type JSONMessage struct {
+	Kind        string `json:"kind,omitempty"`
+	Message     string `json:"message"`
+	Translation string `json:"translation"`
+}
+
+// LoadFile loads translations from a .json or .po file.
+// The locale of PO files defaults to the file name without the extension, if the header does not declare a Language.
+func (c *Catalog) LoadFile(path string) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".json" && ext != ".po" {
+		return erk.WithParams(ErrUnsupportedFormat, erk.Params{"path": path})
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return erk.WrapWith(ErrOpen, err, erk.Params{"path": path})
+	}
+	defer f.Close()
+
+	if ext == ".json" {
+		return c.LoadJSON(f)
+	}
+
+	return c.LoadPO(f, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+}
+
+// LoadJSON loads translations from the JSONFile format.
+func (c *Catalog) LoadJSON(r io.Reader) error {
+	var file JSONFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return erk.WrapAs(ErrInvalidJSON, err)
+	}
+
+	if file.Locale == "" {
+		return ErrMissingLocale
+	}
+
+	for _, message := range file.Messages {
+		if message.Translation != "" {
+			c.Add(file.Locale, message.Kind, message.Message, message.Translation)
+		}
+	}
+
+	return nil
+}
+
+// poEntry is a single entry in a PO file.
+type poEntry struct {
+	context     string
+	id          string
+	translation string
+	fuzzy       bool
+	plural      bool
+}
+
+// LoadPO loads translations from a gettext PO file.
+//
+// The msgctxt is the kind string, the msgid is the raw message, and the msgstr is the translation.
+// The locale is read from the Language header, and defaults to the provided locale.
+// Fuzzy entries, untranslated entries, and entries with plural forms are skipped, since plurals are handled by the plural template function.
+func (c *Catalog) LoadPO(r io.Reader, locale string) error {
+	entries, err := parsePO(r)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.id == "" && entry.context == "" {
+			if language := poHeader(entry.translation, "Language"); language != "" {
+				locale = language
+			}
+		}
+	}
+
+	if locale == "" {
+		return ErrMissingLocale
+	}
+
+	for _, entry := range entries {
+		if entry.id != "" && entry.translation != "" && !entry.fuzzy && !entry.plural {
+			c.Add(locale, entry.context, entry.id, entry.translation)
+		}
+	}
+
+	return nil
+}
+
+//nolint:cyclop,funlen // Parsing is clearest as a single state machine
+func parsePO(r io.Reader) ([]*poEntry, error) {
+	entries := []*poEntry{}
+	entry := &poEntry{}
+	started, sawMsgstr := false, false
+	var field *string
+
+	finish := func() {
+		if started {
+			entries = append(entries, entry)
+		}
+
+		entry = &poEntry{}
+		started, sawMsgstr = false, false
+		field = nil
+	}
+
+	invalid := func(line int, reason string) error {
+		return erk.WithParams(ErrInvalidPO, erk.Params{"line": line, "reason": reason})
+	}
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Comments or keywords after a msgstr start a new entry, even without a blank line
+		if sawMsgstr && !strings.HasPrefix(line, `"`) && !strings.HasPrefix(line, "msgstr") {
+			finish()
+		}
+
+		switch {
+		case line == "":
+			finish()
+			continue
+		case strings.HasPrefix(line, "#,"):
+			entry.fuzzy = entry.fuzzy || strings.Contains(line, "fuzzy")
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, `"`):
+			if field == nil {
+				return nil, invalid(lineNumber, "string without a keyword")
+			}
+
+			value, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, invalid(lineNumber, "invalid string "+line)
+			}
+
+			*field += value
+			continue
+		}
+
+		keyword, rest := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			keyword, rest = line[:i], strings.TrimSpace(line[i+1:])
+		}
+
+		value, err := strconv.Unquote(rest)
+		if err != nil {
+			return nil, invalid(lineNumber, "invalid string "+rest)
+		}
+
+		started = true
+
+		switch {
+		case keyword == "msgctxt":
+			field = &entry.context
+		case keyword == "msgid":
+			field = &entry.id
+		case keyword == "msgid_plural":
+			entry.plural = true
+			field = new(string)
+		case keyword == "msgstr":
+			sawMsgstr = true
+			field = &entry.translation
+		case strings.HasPrefix(keyword, "msgstr["):
+			sawMsgstr = true
+			entry.plural = true
+			field = new(string)
+		default:
+			return nil, invalid(lineNumber, "unknown keyword "+keyword)
+		}
+
+		*field = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, erk.WrapWith(ErrInvalidPO, err, erk.Params{"line": 0, "reason": err.Error()})
+	}
+
+	finish()
+	return entries, nil
+}
+
+// poHeader returns the value of the header field in the PO header entry.
+func poHeader(header, name string) string {
+	for _, line := range strings.Split(header, "\n") {
+		key, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			key, value = line[:i], line[i+1:]
+		}
+
+		if strings.EqualFold(strings.TrimSpace(key), name) {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
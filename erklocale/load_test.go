@@ -0,0 +1,99 @@
+package erklocale_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk/erklocale"
+)
+
+func TestLoadFile(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with PO file", func(ensure ensurepkg.Ensure) {
+		catalog := erklocale.NewCatalog()
+		ensure(catalog.LoadFile("testdata/fr.po")).IsNotError()
+		ensure(catalog.Locales()).Equals([]string{"fr"})
+
+		translation, ok := catalog.LocalizeMessage("fr", "store:not_found", "item {{.key}} was not found")
+		ensure(ok).IsTrue()
+		ensure(translation).Equals("l'élément {{.key}} est introuvable")
+
+		translation, ok = catalog.LocalizeMessage("fr", "other:kind", "failed to load {{.count}} items")
+		ensure(ok).IsTrue()
+		ensure(translation).Equals(`échec du chargement de {{number .count}} {{plural .count "élément" "éléments"}}`)
+
+		for _, message := range []string{"fuzzy message", "untranslated", "one item", ""} {
+			_, ok := catalog.LocalizeMessage("fr", "", message)
+			ensure(ok).IsFalse()
+		}
+	})
+
+	ensure.Run("with JSON file", func(ensure ensurepkg.Ensure) {
+		catalog := erklocale.NewCatalog()
+		ensure(catalog.LoadFile("testdata/de.json")).IsNotError()
+
+		translation, ok := catalog.LocalizeMessage("de-AT", "store:not_found", "item {{.key}} was not found")
+		ensure(ok).IsTrue()
+		ensure(translation).Equals("Element {{.key}} wurde nicht gefunden")
+
+		_, ok = catalog.LocalizeMessage("de", "", "untranslated")
+		ensure(ok).IsFalse()
+	})
+
+	ensure.Run("with missing file", func(ensure ensurepkg.Ensure) {
+		err := erklocale.NewCatalog().LoadFile("testdata/missing.po")
+		ensure(err).MatchesAllErrors(erklocale.ErrOpen)
+	})
+
+	ensure.Run("with unsupported file", func(ensure ensurepkg.Ensure) {
+		err := erklocale.NewCatalog().LoadFile("testdata/fr.yaml")
+		ensure(err).MatchesAllErrors(erklocale.ErrUnsupportedFormat)
+	})
+}
+
+func TestLoadPO(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("uses the Language header over the provided locale", func(ensure ensurepkg.Ensure) {
+		catalog := erklocale.NewCatalog()
+		err := catalog.LoadPO(strings.NewReader("msgid \"\"\nmsgstr \"Language: pt_BR\\n\"\nmsgid \"hello\"\nmsgstr \"olá\"\n"), "es")
+		ensure(err).IsNotError()
+		ensure(catalog.Locales()).Equals([]string{"pt-br"})
+	})
+
+	ensure.Run("without locale", func(ensure ensurepkg.Ensure) {
+		err := erklocale.NewCatalog().LoadPO(strings.NewReader("msgid \"hello\"\nmsgstr \"olá\"\n"), "")
+		ensure(err).MatchesAllErrors(erklocale.ErrMissingLocale)
+	})
+
+	table := []struct {
+		Name string
+		PO   string
+	}{
+		{Name: "with unknown keyword", PO: "msgid \"hello\"\nmsgother \"x\"\n"},
+		{Name: "with invalid string", PO: "msgid hello\n"},
+		{Name: "with string without a keyword", PO: "\"hello\"\n"},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		err := erklocale.NewCatalog().LoadPO(strings.NewReader(table[i].PO), "fr")
+		ensure(err).MatchesAllErrors(erklocale.ErrInvalidPO)
+	})
+}
+
+func TestLoadJSON(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with invalid JSON", func(ensure ensurepkg.Ensure) {
+		err := erklocale.NewCatalog().LoadJSON(strings.NewReader("{"))
+		ensure(err).MatchesAllErrors(erklocale.ErrInvalidJSON)
+	})
+
+	ensure.Run("without locale", func(ensure ensurepkg.Ensure) {
+		err := erklocale.NewCatalog().LoadJSON(strings.NewReader(`{"messages":[]}`))
+		ensure(err).MatchesAllErrors(erklocale.ErrMissingLocale)
+	})
+}
@@ -0,0 +1,16 @@
+//go:build go1.18
+// +build go1.18
+
+package erkreport
+
+import "runtime/debug"
+
+func buildRevision(info *debug.BuildInfo) string {
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+
+	return ""
+}
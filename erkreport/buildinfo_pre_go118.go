@@ -0,0 +1,10 @@
+//go:build !go1.18
+// +build !go1.18
+
+package erkreport
+
+import "runtime/debug"
+
+func buildRevision(*debug.BuildInfo) string {
+	return ""
+}
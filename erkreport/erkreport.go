@@ -0,0 +1,107 @@
+// Package erkreport reports erk errors to pluggable sinks.
+//
+// Errors are exported using erk.Export, and enriched with a fingerprint, timestamp, host, and build info.
+// Reports are batched asynchronously by a Reporter, and written to a Sink.
+//
+// Example:
+//
+//	sink, err := erkreport.NewFileSink("errors.jsonl", erkreport.FileSinkOptions{MaxSize: 10 << 20})
+//	if err != nil {
+//	  ...
+//	}
+//
+//	reporter := erkreport.New(sink, erkreport.Options{})
+//	defer reporter.Close(context.Background())
+//
+//	...
+//
+//	reporter.Report(ctx, err)
+package erkreport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+)
+
+// Report of an error, enriched with details about where and when it occurred.
+type Report struct {
+	// Fingerprint identifies similar errors. See Fingerprint.
+	Fingerprint string `json:"fingerprint"`
+
+	Timestamp time.Time `json:"timestamp"`
+	Host      string    `json:"host,omitempty"`
+	Build     BuildInfo `json:"build"`
+
+	// Error is the exported error, usually an *erk.ExportedError or *erg.ExportedGroup.
+	Error erk.ExportedErkable `json:"error"`
+}
+
+// BuildInfo describing the binary that reported the error.
+type BuildInfo struct {
+	Path      string `json:"path,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Revision  string `json:"revision,omitempty"`
+	GoVersion string `json:"goVersion,omitempty"`
+}
+
+// Fingerprint of an error, which is the same for errors with the same kind and raw message template.
+// Params are ignored, which matches how errors.Is compares erk errors.
+// Errors that are not erk errors are classified first (see erk.Classify).
+//
+// It is a hash of the erg.KeyByFingerprint key, so errors are grouped the same way as when deduplicating groups.
+func Fingerprint(err error) string {
+	sum := sha256.Sum256([]byte(erg.KeyByFingerprint(erk.ToErk(err))))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewReport for the error, using the provided enrichment.
+// Zero values in the options are filled in using the same defaults as the Reporter.
+func NewReport(err error, options Options) *Report {
+	options = options.withDefaults()
+
+	return &Report{
+		Fingerprint: Fingerprint(err),
+		Timestamp:   options.Now(),
+		Host:        options.Host,
+		Build:       *options.Build,
+		Error:       erk.Export(err),
+	}
+}
+
+//nolint:gochecknoglobals // Computed once, since it cannot change
+var defaultBuildInfo struct {
+	once sync.Once
+	info BuildInfo
+}
+
+// DefaultBuildInfo reads the build info embedded in the running binary.
+func DefaultBuildInfo() BuildInfo {
+	defaultBuildInfo.once.Do(func() {
+		defaultBuildInfo.info.GoVersion = runtime.Version()
+
+		if info, ok := debug.ReadBuildInfo(); ok {
+			defaultBuildInfo.info.Path = info.Main.Path
+			defaultBuildInfo.info.Version = info.Main.Version
+			defaultBuildInfo.info.Revision = buildRevision(info)
+		}
+	})
+
+	return defaultBuildInfo.info
+}
+
+func defaultHost() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+
+	return host
+}
@@ -0,0 +1,89 @@
+package erkreport_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+	"github.com/JosiahWitt/erk/erkreport"
+)
+
+type ErkExample struct{ erk.DefaultKind }
+
+var (
+	errExample  = erk.New(ErkExample{}, "my message {{.a}}")
+	errExample2 = erk.New(ErkExample{}, "my other message")
+)
+
+var errExampleHello = erk.WithParams(errExample, erk.Params{"a": "hello"})
+
+var now = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestFingerprint(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with same kind and message but different params", func(ensure ensurepkg.Ensure) {
+		fingerprint1 := erkreport.Fingerprint(erk.WithParams(errExample, erk.Params{"a": "hello"}))
+		fingerprint2 := erkreport.Fingerprint(erk.WithParams(errExample, erk.Params{"a": "world"}))
+		ensure(fingerprint1).Equals(fingerprint2)
+		ensure(len(fingerprint1)).Equals(64)
+	})
+
+	ensure.Run("with different messages", func(ensure ensurepkg.Ensure) {
+		ensure(erkreport.Fingerprint(errExampleHello) == erkreport.Fingerprint(errExample2)).IsFalse()
+	})
+
+	ensure.Run("with classified standard library errors", func(ensure ensurepkg.Ensure) {
+		ensure(erkreport.Fingerprint(erk.ErrEOF)).Equals(erkreport.Fingerprint(erk.ErrEOF))
+		ensure(erkreport.Fingerprint(errors.New("a")) == erkreport.Fingerprint(errors.New("b"))).IsFalse()
+	})
+
+	ensure.Run("matches the erg fingerprint key", func(ensure ensurepkg.Ensure) {
+		sum := sha256.Sum256([]byte(erg.KeyByFingerprint(errExampleHello)))
+		ensure(erkreport.Fingerprint(errExampleHello)).Equals(hex.EncodeToString(sum[:]))
+	})
+}
+
+func TestNewReport(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with options", func(ensure ensurepkg.Ensure) {
+		err := erk.WithParams(errExample, erk.Params{"a": "hello"})
+		build := erkreport.BuildInfo{Version: "v1.2.3", Revision: "abc"}
+
+		report := erkreport.NewReport(err, erkreport.Options{
+			Host:  "my-host",
+			Build: &build,
+			Now:   func() time.Time { return now },
+		})
+
+		ensure(report.Fingerprint).Equals(erkreport.Fingerprint(err))
+		ensure(report.Timestamp).Equals(now)
+		ensure(report.Host).Equals("my-host")
+		ensure(report.Build).Equals(build)
+		ensure(report.Error.ErrorMessage()).Equals("my message hello")
+		ensure(report.Error.ErrorParams()).Equals(erk.Params{"a": "hello"})
+	})
+
+	ensure.Run("with defaults", func(ensure ensurepkg.Ensure) {
+		report := erkreport.NewReport(errExampleHello, erkreport.Options{})
+		ensure(report.Build).Equals(erkreport.DefaultBuildInfo())
+		ensure(report.Build.GoVersion == "").IsFalse()
+		ensure(report.Timestamp.IsZero()).IsFalse()
+	})
+
+	ensure.Run("with group", func(ensure ensurepkg.Ensure) {
+		groupErr := erg.NewAs(errExampleHello)
+		groupErr = erg.Append(groupErr, errExample2)
+
+		report := erkreport.NewReport(groupErr, erkreport.Options{Now: func() time.Time { return now }})
+		_, isGroup := report.Error.(*erg.ExportedGroup)
+		ensure(isGroup).IsTrue()
+	})
+}
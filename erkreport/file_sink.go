@@ -0,0 +1,197 @@
+package erkreport
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/JosiahWitt/erk"
+)
+
+// Default file sink option values, used when the option field is zero.
+const (
+	DefaultMaxSize    = 10 << 20
+	DefaultMaxBackups = 3
+)
+
+// ErkFileSink is the kind of errors returned by the FileSink.
+type ErkFileSink struct{ erk.DefaultKind }
+
+// Errors returned by the FileSink.
+var (
+	ErrFileSinkOpen   = erk.New(ErkFileSink{}, "failed to open the report file {{.path}}: {{.err}}")
+	ErrFileSinkWrite  = erk.New(ErkFileSink{}, "failed to write to the report file {{.path}}: {{.err}}")
+	ErrFileSinkRotate = erk.New(ErkFileSink{}, "failed to rotate the report file {{.path}}: {{.err}}")
+)
+
+// FileSinkOptions for a FileSink.
+// Zero fields use the default values.
+type FileSinkOptions struct {
+	// MaxSize in bytes of the file before it is rotated.
+	// A file can exceed MaxSize only if a single report is larger than MaxSize.
+	MaxSize int64
+
+	// MaxBackups is the number of rotated files to keep.
+	// Rotated files are named by appending .1, .2, etc. to the path, where .1 is the most recent.
+	MaxBackups int
+}
+
+func (o FileSinkOptions) withDefaults() FileSinkOptions {
+	if o.MaxSize <= 0 {
+		o.MaxSize = DefaultMaxSize
+	}
+
+	if o.MaxBackups <= 0 {
+		o.MaxBackups = DefaultMaxBackups
+	}
+
+	return o
+}
+
+// FileSink writes each report as a line of JSON, and rotates the file when it exceeds the maximum size.
+type FileSink struct {
+	path    string
+	options FileSinkOptions
+
+	mu   sync.Mutex
+	file *os.File // Nil if the file could not be reopened after a failed rotation
+	size int64
+}
+
+// FileSink implements Sink.
+var _ Sink = &FileSink{}
+
+// NewFileSink opens or creates the file at the path, appending to it if it exists.
+func NewFileSink(path string, options FileSinkOptions) (*FileSink, error) {
+	s := &FileSink{path: path, options: options.withDefaults()}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Write the reports as JSON lines.
+//
+// If an error cannot be marshalled to JSON, for example because a param cannot be marshalled,
+// the report is written with only the error's kind and message.
+func (s *FileSink) Write(_ context.Context, reports []*Report) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	for _, report := range reports {
+		line := marshalReportLine(report)
+
+		if s.size > 0 && s.size+int64(len(line)) > s.options.MaxSize {
+			if err := s.rotate(); err != nil {
+				return err
+			}
+		}
+
+		n, err := s.file.Write(line)
+		s.size += int64(n)
+
+		if err != nil {
+			return erk.WrapWith(ErrFileSinkWrite, err, erk.Params{"path": s.path})
+		}
+	}
+
+	return nil
+}
+
+// Close the file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	if err := s.file.Close(); err != nil {
+		return erk.WrapWith(ErrFileSinkWrite, err, erk.Params{"path": s.path})
+	}
+
+	return nil
+}
+
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec // Reports are not secret
+	if err != nil {
+		return erk.WrapWith(ErrFileSinkOpen, err, erk.Params{"path": s.path})
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return erk.WrapWith(ErrFileSinkOpen, err, erk.Params{"path": s.path})
+	}
+
+	s.file = file
+	s.size = info.Size()
+
+	return nil
+}
+
+// rotate the file, which is closed first, since open files cannot be renamed on some platforms.
+// If rotating fails, the file is reopened, so later writes can succeed.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return s.reopenAfter(erk.WrapWith(ErrFileSinkRotate, err, erk.Params{"path": s.path}))
+	}
+
+	for i := s.options.MaxBackups - 1; i >= 1; i-- {
+		err := os.Rename(s.backupPath(i), s.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return s.reopenAfter(erk.WrapWith(ErrFileSinkRotate, err, erk.Params{"path": s.path}))
+		}
+	}
+
+	if err := os.Rename(s.path, s.backupPath(1)); err != nil {
+		return s.reopenAfter(erk.WrapWith(ErrFileSinkRotate, err, erk.Params{"path": s.path}))
+	}
+
+	return s.reopenAfter(nil)
+}
+
+// reopenAfter reopens the file after it was closed, returning err if it is not nil.
+// If the file cannot be reopened, it is opened again by the next write.
+func (s *FileSink) reopenAfter(err error) error {
+	if openErr := s.open(); openErr != nil {
+		s.file = nil
+
+		if err == nil {
+			return openErr
+		}
+	}
+
+	return err
+}
+
+func (s *FileSink) backupPath(i int) string {
+	return s.path + "." + strconv.Itoa(i)
+}
+
+func marshalReportLine(report *Report) []byte {
+	line, err := json.Marshal(report)
+	if err != nil {
+		fallback := *report
+		fallback.Error = &erk.BaseExport{
+			Kind:    report.Error.ErrorKind(),
+			Message: report.Error.ErrorMessage(),
+			Params:  nil,
+		}
+
+		line, _ = json.Marshal(&fallback) //nolint:errchkjson // The fallback only contains strings
+	}
+
+	return append(line, '\n')
+}
@@ -0,0 +1,146 @@
+package erkreport_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erkreport"
+)
+
+func TestFileSink(t *testing.T) {
+	ensure := ensure.New(t)
+
+	newReport := func(err error) *erkreport.Report {
+		build := erkreport.BuildInfo{Version: "v1.2.3"}
+		return erkreport.NewReport(err, erkreport.Options{Host: "my-host", Build: &build, Now: func() time.Time { return now }})
+	}
+
+	ensure.Run("writes JSON lines", func(ensure ensurepkg.Ensure) {
+		path := filepath.Join(t.TempDir(), "errors.jsonl")
+		sink, err := erkreport.NewFileSink(path, erkreport.FileSinkOptions{})
+		ensure(err).IsNotError()
+
+		ensure(sink.Write(context.Background(), []*erkreport.Report{newReport(errExampleHello), newReport(errExample2)})).IsNotError()
+		ensure(sink.Close()).IsNotError()
+
+		lines := readLines(ensure, path)
+		ensure(len(lines)).Equals(2)
+		ensure(lines[0]["fingerprint"]).Equals(erkreport.Fingerprint(errExampleHello))
+		ensure(lines[0]["timestamp"]).Equals("2020-01-02T03:04:05Z")
+		ensure(lines[0]["host"]).Equals("my-host")
+		ensure(lines[0]["build"]).Equals(map[string]interface{}{"version": "v1.2.3"})
+		ensure(lines[0]["error"].(map[string]interface{})["message"]).Equals("my message hello")
+		ensure(lines[1]["error"].(map[string]interface{})["message"]).Equals("my other message")
+	})
+
+	ensure.Run("appends to existing file", func(ensure ensurepkg.Ensure) {
+		path := filepath.Join(t.TempDir(), "errors.jsonl")
+
+		for i := 0; i < 2; i++ {
+			sink, err := erkreport.NewFileSink(path, erkreport.FileSinkOptions{})
+			ensure(err).IsNotError()
+			ensure(sink.Write(context.Background(), []*erkreport.Report{newReport(errExampleHello)})).IsNotError()
+			ensure(sink.Close()).IsNotError()
+		}
+
+		ensure(len(readLines(ensure, path))).Equals(2)
+	})
+
+	ensure.Run("writes errors that cannot be marshalled without params", func(ensure ensurepkg.Ensure) {
+		path := filepath.Join(t.TempDir(), "errors.jsonl")
+		sink, err := erkreport.NewFileSink(path, erkreport.FileSinkOptions{})
+		ensure(err).IsNotError()
+
+		ensure(sink.Write(context.Background(), []*erkreport.Report{
+			newReport(erk.WithParams(errExample, erk.Params{"a": "hello", "b": make(chan struct{})})),
+		})).IsNotError()
+		ensure(sink.Close()).IsNotError()
+
+		lines := readLines(ensure, path)
+		ensure(len(lines)).Equals(1)
+		ensure(lines[0]["error"].(map[string]interface{})["kind"]).Equals(erk.GetKindString(errExample))
+		ensure(lines[0]["error"].(map[string]interface{})["params"]).IsNil()
+	})
+
+	ensure.Run("rotates files", func(ensure ensurepkg.Ensure) {
+		path := filepath.Join(t.TempDir(), "errors.jsonl")
+
+		lineSize := int64(len(mustMarshal(ensure, newReport(errExampleHello)))) + 1
+		sink, err := erkreport.NewFileSink(path, erkreport.FileSinkOptions{MaxSize: 2 * lineSize, MaxBackups: 2})
+		ensure(err).IsNotError()
+
+		for i := 0; i < 7; i++ {
+			ensure(sink.Write(context.Background(), []*erkreport.Report{newReport(errExampleHello)})).IsNotError()
+		}
+		ensure(sink.Close()).IsNotError()
+
+		ensure(len(readLines(ensure, path))).Equals(1)
+		ensure(len(readLines(ensure, path+".1"))).Equals(2)
+		ensure(len(readLines(ensure, path+".2"))).Equals(2)
+
+		_, err = os.Stat(path + ".3")
+		ensure(os.IsNotExist(err)).IsTrue()
+	})
+
+	ensure.Run("recovers from failed rotation", func(ensure ensurepkg.Ensure) {
+		path := filepath.Join(t.TempDir(), "errors.jsonl")
+
+		lineSize := int64(len(mustMarshal(ensure, newReport(errExampleHello)))) + 1
+		sink, err := erkreport.NewFileSink(path, erkreport.FileSinkOptions{MaxSize: lineSize, MaxBackups: 1})
+		ensure(err).IsNotError()
+		ensure(sink.Write(context.Background(), []*erkreport.Report{newReport(errExampleHello)})).IsNotError()
+
+		// A non-empty directory at the backup path prevents renaming the file
+		ensure(os.MkdirAll(filepath.Join(path+".1", "blocker"), 0o755)).IsNotError()
+		ensure(sink.Write(context.Background(), []*erkreport.Report{newReport(errExampleHello)})).MatchesAllErrors(erkreport.ErrFileSinkRotate)
+
+		ensure(os.RemoveAll(path + ".1")).IsNotError()
+		ensure(sink.Write(context.Background(), []*erkreport.Report{newReport(errExampleHello)})).IsNotError()
+		ensure(sink.Close()).IsNotError()
+
+		ensure(len(readLines(ensure, path))).Equals(1)
+		ensure(len(readLines(ensure, path+".1"))).Equals(1)
+	})
+
+	ensure.Run("with invalid path", func(ensure ensurepkg.Ensure) {
+		sink, err := erkreport.NewFileSink(filepath.Join(t.TempDir(), "missing", "errors.jsonl"), erkreport.FileSinkOptions{})
+		ensure(err).MatchesAllErrors(erkreport.ErrFileSinkOpen)
+		ensure(sink).IsNil()
+	})
+}
+
+func readLines(ensure ensurepkg.Ensure, path string) []map[string]interface{} {
+	ensure.T().Helper()
+
+	file, err := os.Open(path)
+	ensure(err).IsNotError()
+	defer file.Close()
+
+	lines := []map[string]interface{}{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := map[string]interface{}{}
+		ensure(json.Unmarshal(scanner.Bytes(), &line)).IsNotError()
+		lines = append(lines, line)
+	}
+	ensure(scanner.Err()).IsNotError()
+
+	return lines
+}
+
+func mustMarshal(ensure ensurepkg.Ensure, report *erkreport.Report) []byte {
+	ensure.T().Helper()
+
+	data, err := json.Marshal(report)
+	ensure(err).IsNotError()
+
+	return data
+}
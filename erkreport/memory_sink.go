@@ -0,0 +1,67 @@
+package erkreport
+
+import (
+	"context"
+	"sync"
+)
+
+// MemorySink stores reports in memory.
+// It is useful for testing.
+type MemorySink struct {
+	mu      sync.Mutex
+	reports []*Report
+	batches int
+	closed  bool
+}
+
+// MemorySink implements Sink.
+var _ Sink = &MemorySink{}
+
+// NewMemorySink creates an empty MemorySink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Write appends the reports.
+func (s *MemorySink) Write(_ context.Context, reports []*Report) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reports = append(s.reports, reports...)
+	s.batches++
+
+	return nil
+}
+
+// Close marks the sink as closed.
+func (s *MemorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	return nil
+}
+
+// Reports returns a copy of the written reports, in the order they were written.
+func (s *MemorySink) Reports() []*Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Report{}, s.reports...)
+}
+
+// Batches returns the number of times Write was called.
+func (s *MemorySink) Batches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.batches
+}
+
+// Closed reports if Close was called.
+func (s *MemorySink) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
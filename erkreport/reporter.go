@@ -0,0 +1,323 @@
+package erkreport
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JosiahWitt/erk"
+)
+
+// Default option values, used when the option field is zero.
+const (
+	DefaultBatchSize     = 100
+	DefaultFlushInterval = time.Second
+	DefaultQueueSize     = 1000
+)
+
+type (
+	// ErkQueueFull is the kind of errors returned when a report is dropped because the queue is full.
+	ErkQueueFull struct{ erk.DefaultKind }
+
+	// ErkClosed is the kind of errors returned when using a closed reporter.
+	ErkClosed struct{ erk.DefaultKind }
+
+	// ErkSinkWrite is the kind of errors returned when a sink fails to write reports.
+	ErkSinkWrite struct{ erk.DefaultKind }
+)
+
+// Errors returned by the Reporter.
+var (
+	ErrQueueFull = erk.New(ErkQueueFull{}, "report queue is full, so the report was dropped")
+	ErrClosed    = erk.New(ErkClosed{}, "reporter is closed")
+	ErrSinkWrite = erk.New(ErkSinkWrite{}, "failed to write {{.count}} reports: {{.err}}")
+	ErrSinkClose = erk.New(ErkSinkWrite{}, "failed to close the sink: {{.err}}")
+)
+
+// Sink writes batches of reports.
+// The Reporter calls Write from a single goroutine, and calls Close once after the final Write.
+type Sink interface {
+	Write(ctx context.Context, reports []*Report) error
+	Close() error
+}
+
+// Options for a Reporter.
+// Zero fields use the default values.
+type Options struct {
+	// BatchSize is the maximum number of reports written to the sink at once.
+	BatchSize int
+
+	// FlushInterval is the maximum time a report waits before it is written to the sink.
+	FlushInterval time.Duration
+
+	// QueueSize is the number of reports that can wait to be batched.
+	QueueSize int
+
+	// Block causes Report to wait for room in the queue when it is full, instead of dropping the report.
+	Block bool
+
+	// Host reported with each error. Defaults to os.Hostname.
+	Host string
+
+	// Build info reported with each error. Defaults to DefaultBuildInfo.
+	Build *BuildInfo
+
+	// Now returns the timestamp of each report. Defaults to time.Now.
+	Now func() time.Time
+
+	// OnError is called with errors from the sink that cannot be returned, since they occurred in the background.
+	// Defaults to ignoring the errors.
+	OnError func(err error)
+}
+
+func (o Options) withDefaults() Options {
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultBatchSize
+	}
+
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = DefaultFlushInterval
+	}
+
+	if o.QueueSize <= 0 {
+		o.QueueSize = DefaultQueueSize
+	}
+
+	if o.Host == "" {
+		o.Host = defaultHost()
+	}
+
+	if o.Build == nil {
+		build := DefaultBuildInfo()
+		o.Build = &build
+	}
+
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+
+	if o.OnError == nil {
+		o.OnError = func(error) {}
+	}
+
+	return o
+}
+
+// Reporter batches reports asynchronously, and writes them to a sink.
+// It is safe for concurrent use.
+type Reporter struct {
+	sink    Sink
+	options Options
+
+	queue   chan *Report
+	flushes chan chan error
+	closing chan struct{} // Closed first when closing, to wake Report calls waiting for room in the queue
+	done    chan struct{} // Closed after no more reports can be queued
+	stopped chan struct{}
+
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	closeErr  error
+
+	dropped uint64
+}
+
+// New Reporter that writes to the sink.
+// Close must be called to flush the remaining reports and stop the background goroutine.
+func New(sink Sink, options Options) *Reporter {
+	options = options.withDefaults()
+
+	r := &Reporter{
+		sink:    sink,
+		options: options,
+
+		queue:   make(chan *Report, options.QueueSize),
+		flushes: make(chan chan error),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go r.run()
+
+	return r
+}
+
+// Report the error. Nil errors are ignored.
+//
+// If the queue is full, ErrQueueFull is returned and the report is dropped, unless the Block option is set.
+// When blocking, the context's error is returned if it is done before there is room in the queue.
+// ErrClosed is returned if the reporter is closed, including while waiting for room in the queue.
+func (r *Reporter) Report(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	report := NewReport(err, r.options)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return ErrClosed
+	}
+
+	if !r.options.Block {
+		select {
+		case r.queue <- report:
+			return nil
+		default:
+			atomic.AddUint64(&r.dropped, 1)
+			return ErrQueueFull
+		}
+	}
+
+	select {
+	case r.queue <- report:
+		return nil
+	case <-r.closing:
+		atomic.AddUint64(&r.dropped, 1)
+		return ErrClosed
+	case <-ctx.Done():
+		atomic.AddUint64(&r.dropped, 1)
+		return erk.FromContext(ctx)
+	}
+}
+
+// Dropped returns the number of reports that were dropped because the queue was full.
+// When the Block option is set, it is the number of reports dropped because the context was done,
+// or the reporter was closed, while waiting for room in the queue.
+func (r *Reporter) Dropped() int {
+	return int(atomic.LoadUint64(&r.dropped))
+}
+
+// Flush writes all queued reports to the sink, and waits for the writes to finish.
+// The first error returned by the sink is returned.
+func (r *Reporter) Flush(ctx context.Context) error {
+	r.mu.RLock()
+	closed := r.closed
+	r.mu.RUnlock()
+
+	if closed {
+		return ErrClosed
+	}
+
+	result := make(chan error, 1)
+
+	select {
+	case r.flushes <- result:
+	case <-r.stopped:
+		return ErrClosed
+	case <-ctx.Done():
+		return erk.FromContext(ctx)
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return erk.FromContext(ctx)
+	}
+}
+
+// Close stops accepting reports, writes all queued reports to the sink, and closes the sink.
+// If the context is done before the reports are written, its error is returned, but the reports continue to be written in the background.
+//
+// Calling Close more than once returns the result of the first call.
+func (r *Reporter) Close(ctx context.Context) error {
+	r.closeOnce.Do(func() {
+		// Report holds the read lock while waiting for room in the queue, so wake it before taking the lock
+		close(r.closing)
+
+		r.mu.Lock()
+		r.closed = true
+		r.mu.Unlock()
+
+		close(r.done)
+	})
+
+	select {
+	case <-r.stopped:
+		return r.closeErr
+	case <-ctx.Done():
+		return erk.FromContext(ctx)
+	}
+}
+
+func (r *Reporter) run() {
+	defer close(r.stopped)
+
+	ticker := time.NewTicker(r.options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := []*Report{}
+
+	for {
+		select {
+		case report := <-r.queue:
+			batch = append(batch, report)
+			if len(batch) >= r.options.BatchSize {
+				r.reportError(r.write(batch))
+				batch = []*Report{}
+			}
+
+		case <-ticker.C:
+			r.reportError(r.write(batch))
+			batch = []*Report{}
+
+		case result := <-r.flushes:
+			result <- r.writeAll(batch)
+			batch = []*Report{}
+
+		case <-r.done:
+			err := r.writeAll(batch)
+			if closeErr := r.sink.Close(); closeErr != nil && err == nil {
+				err = erk.WrapAs(ErrSinkClose, closeErr)
+			}
+
+			r.closeErr = err
+			return
+		}
+	}
+}
+
+// writeAll writes the batch and everything remaining in the queue, returning the first error.
+func (r *Reporter) writeAll(batch []*Report) error {
+	var firstErr error
+
+	for {
+		for len(batch) < r.options.BatchSize && len(r.queue) > 0 {
+			batch = append(batch, <-r.queue)
+		}
+
+		if len(batch) == 0 {
+			return firstErr
+		}
+
+		if err := r.write(batch); err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		batch = []*Report{}
+	}
+}
+
+func (r *Reporter) write(batch []*Report) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := r.sink.Write(context.Background(), batch); err != nil {
+		return erk.WrapWith(ErrSinkWrite, err, erk.Params{"count": len(batch)})
+	}
+
+	return nil
+}
+
+func (r *Reporter) reportError(err error) {
+	if err != nil {
+		r.options.OnError(err)
+	}
+}
@@ -0,0 +1,223 @@
+package erkreport_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erkreport"
+)
+
+// blockingSink blocks each write until it is released.
+type blockingSink struct {
+	erkreport.MemorySink
+	release chan struct{}
+}
+
+func (s *blockingSink) Write(ctx context.Context, reports []*erkreport.Report) error {
+	<-s.release
+	return s.MemorySink.Write(ctx, reports)
+}
+
+type failingSink struct {
+	err error
+}
+
+func (s *failingSink) Write(context.Context, []*erkreport.Report) error { return s.err }
+func (s *failingSink) Close() error                                     { return nil }
+
+func TestReporter(t *testing.T) {
+	ensure := ensure.New(t)
+
+	options := erkreport.Options{
+		FlushInterval: time.Hour,
+		Host:          "my-host",
+		Now:           func() time.Time { return now },
+	}
+
+	ensure.Run("reports and flushes errors", func(ensure ensurepkg.Ensure) {
+		sink := erkreport.NewMemorySink()
+		reporter := erkreport.New(sink, options)
+
+		ensure(reporter.Report(context.Background(), erk.WithParams(errExample, erk.Params{"a": "hello"}))).IsNotError()
+		ensure(reporter.Report(context.Background(), errExample2)).IsNotError()
+		ensure(reporter.Report(context.Background(), nil)).IsNotError()
+		ensure(reporter.Flush(context.Background())).IsNotError()
+
+		reports := sink.Reports()
+		ensure(len(reports)).Equals(2)
+		ensure(reports[0].Error.ErrorMessage()).Equals("my message hello")
+		ensure(reports[0].Host).Equals("my-host")
+		ensure(reports[0].Timestamp).Equals(now)
+		ensure(reports[1].Fingerprint).Equals(erkreport.Fingerprint(errExample2))
+
+		ensure(reporter.Close(context.Background())).IsNotError()
+		ensure(sink.Closed()).IsTrue()
+	})
+
+	ensure.Run("writes in batches", func(ensure ensurepkg.Ensure) {
+		sink := erkreport.NewMemorySink()
+		batchOptions := options
+		batchOptions.BatchSize = 2
+		reporter := erkreport.New(sink, batchOptions)
+
+		for i := 0; i < 5; i++ {
+			ensure(reporter.Report(context.Background(), errExampleHello)).IsNotError()
+		}
+
+		ensure(reporter.Close(context.Background())).IsNotError()
+		ensure(len(sink.Reports())).Equals(5)
+		ensure(sink.Batches()).Equals(3)
+	})
+
+	ensure.Run("flushes on the interval", func(ensure ensurepkg.Ensure) {
+		sink := erkreport.NewMemorySink()
+		intervalOptions := options
+		intervalOptions.FlushInterval = time.Millisecond
+		reporter := erkreport.New(sink, intervalOptions)
+		defer reporter.Close(context.Background())
+
+		ensure(reporter.Report(context.Background(), errExampleHello)).IsNotError()
+
+		for i := 0; i < 1000 && len(sink.Reports()) == 0; i++ {
+			time.Sleep(time.Millisecond)
+		}
+
+		ensure(len(sink.Reports())).Equals(1)
+	})
+
+	ensure.Run("drops reports when the queue is full", func(ensure ensurepkg.Ensure) {
+		sink := &blockingSink{release: make(chan struct{})}
+		dropOptions := options
+		dropOptions.BatchSize = 1
+		dropOptions.QueueSize = 1
+		reporter := erkreport.New(sink, dropOptions)
+
+		// The first report is picked up by the background goroutine, which blocks writing it.
+		// The second report fills the queue, and the rest are dropped.
+		var err error
+		for i := 0; i < 10 && err == nil; i++ {
+			err = reporter.Report(context.Background(), errExampleHello)
+			time.Sleep(time.Millisecond)
+		}
+
+		ensure(err).MatchesAllErrors(erkreport.ErrQueueFull)
+		ensure(reporter.Dropped()).Equals(1)
+
+		close(sink.release)
+		ensure(reporter.Close(context.Background())).IsNotError()
+		ensure(len(sink.Reports())).Equals(2)
+	})
+
+	ensure.Run("blocks when the queue is full", func(ensure ensurepkg.Ensure) {
+		sink := &blockingSink{release: make(chan struct{})}
+		blockOptions := options
+		blockOptions.BatchSize = 1
+		blockOptions.QueueSize = 1
+		blockOptions.Block = true
+		reporter := erkreport.New(sink, blockOptions)
+
+		ensure(reporter.Report(context.Background(), errExampleHello)).IsNotError()
+		ensure(reporter.Report(context.Background(), errExampleHello)).IsNotError()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		var blockedErr error
+		go func() {
+			defer wg.Done()
+			blockedErr = reporter.Report(ctx, errExampleHello)
+		}()
+		wg.Wait()
+
+		ensure(blockedErr).MatchesAllErrors(erk.ErrContextDeadlineExceeded)
+		ensure(reporter.Dropped()).Equals(1)
+
+		close(sink.release)
+		ensure(reporter.Close(context.Background())).IsNotError()
+		ensure(len(sink.Reports())).Equals(2)
+	})
+
+	ensure.Run("closes while reports are blocked", func(ensure ensurepkg.Ensure) {
+		sink := &blockingSink{release: make(chan struct{})}
+		blockOptions := options
+		blockOptions.BatchSize = 1
+		blockOptions.QueueSize = 1
+		blockOptions.Block = true
+		reporter := erkreport.New(sink, blockOptions)
+
+		ensure(reporter.Report(context.Background(), errExampleHello)).IsNotError()
+		ensure(reporter.Report(context.Background(), errExampleHello)).IsNotError()
+
+		blockedErrs := make(chan error, 1)
+		go func() { blockedErrs <- reporter.Report(context.Background(), errExampleHello) }()
+		time.Sleep(10 * time.Millisecond) // Wait for the report to block
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		ensure(reporter.Close(ctx)).MatchesAllErrors(erk.ErrContextDeadlineExceeded)
+		ensure(<-blockedErrs).MatchesAllErrors(erkreport.ErrClosed)
+		ensure(reporter.Dropped()).Equals(1)
+
+		close(sink.release)
+		ensure(reporter.Close(context.Background())).IsNotError()
+		ensure(len(sink.Reports())).Equals(2)
+	})
+
+	ensure.Run("returns sink errors when flushing", func(ensure ensurepkg.Ensure) {
+		sinkErr := errors.New("sink failed")
+		reporter := erkreport.New(&failingSink{err: sinkErr}, options)
+
+		ensure(reporter.Report(context.Background(), errExampleHello)).IsNotError()
+		ensure(reporter.Flush(context.Background())).MatchesAllErrors(erkreport.ErrSinkWrite, sinkErr)
+
+		ensure(reporter.Report(context.Background(), errExampleHello)).IsNotError()
+		ensure(reporter.Close(context.Background())).MatchesAllErrors(erkreport.ErrSinkWrite, sinkErr)
+	})
+
+	ensure.Run("passes background sink errors to OnError", func(ensure ensurepkg.Ensure) {
+		sinkErr := errors.New("sink failed")
+		backgroundErrs := make(chan error, 1)
+
+		errOptions := options
+		errOptions.BatchSize = 1
+		errOptions.OnError = func(err error) { backgroundErrs <- err }
+		reporter := erkreport.New(&failingSink{err: sinkErr}, errOptions)
+		defer reporter.Close(context.Background())
+
+		ensure(reporter.Report(context.Background(), errExampleHello)).IsNotError()
+		ensure(<-backgroundErrs).MatchesAllErrors(erkreport.ErrSinkWrite, sinkErr)
+	})
+
+	ensure.Run("rejects reports after close", func(ensure ensurepkg.Ensure) {
+		reporter := erkreport.New(erkreport.NewMemorySink(), options)
+		ensure(reporter.Close(context.Background())).IsNotError()
+		ensure(reporter.Close(context.Background())).IsNotError()
+
+		ensure(reporter.Report(context.Background(), errExampleHello)).MatchesAllErrors(erkreport.ErrClosed)
+		ensure(reporter.Flush(context.Background())).MatchesAllErrors(erkreport.ErrClosed)
+	})
+
+	ensure.Run("returns the context error when close times out", func(ensure ensurepkg.Ensure) {
+		sink := &blockingSink{release: make(chan struct{})}
+		reporter := erkreport.New(sink, options)
+		ensure(reporter.Report(context.Background(), errExampleHello)).IsNotError()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		ensure(reporter.Close(ctx)).MatchesAllErrors(erk.ErrContextCanceled)
+
+		close(sink.release)
+		ensure(reporter.Close(context.Background())).IsNotError()
+		ensure(len(sink.Reports())).Equals(1)
+	})
+}
@@ -0,0 +1,75 @@
+package erkretry
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock used to wait between attempts.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// RealClock uses the time package.
+type RealClock struct{}
+
+// RealClock implements Clock.
+var _ Clock = RealClock{}
+
+// Now returns the current time.
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse.
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// FakeClock records each wait, and returns immediately without sleeping.
+// It is useful for testing.
+type FakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+// FakeClock implements Clock.
+var _ Clock = &FakeClock{}
+
+// NewFakeClock starting at the provided time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After records the wait, advances the fake time, and returns a channel that is ready immediately.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// Waits returns a copy of each duration passed to After.
+func (c *FakeClock) Waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	waitsCopy := make([]time.Duration, len(c.waits))
+	copy(waitsCopy, c.waits)
+	return waitsCopy
+}
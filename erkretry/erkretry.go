@@ -0,0 +1,150 @@
+// Package erkretry allows error kinds to declare if they are retryable, and retries functions based on those declarations.
+//
+// To use this, embed RetryableKind in error kinds that can be retried.
+// Kinds can also override RetryHintsFor to limit the number of attempts.
+//
+// Example:
+//
+//	type ErkThrottled struct {
+//	  erk.DefaultKind
+//	  erkretry.RetryableKind
+//	}
+//
+//	var ErrThrottled = erk.New(ErkThrottled{}, "request was throttled")
+//
+//	...
+//
+//	err := erkretry.Retry(ctx, erkretry.Policy{MaxAttempts: 5}, func(ctx context.Context) error {
+//	  return client.Call(ctx)
+//	})
+package erkretry
+
+import (
+	"errors"
+	"time"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+)
+
+// RetryAfterParam is the param key that can contain a hint for how long to wait before retrying.
+// The value can be a time.Duration or a time.Time.
+const RetryAfterParam = "retryAfter"
+
+// Hints declared by a kind for retrying errors.
+type Hints struct {
+	// Retryable reports if errors with the kind can be retried.
+	Retryable bool
+
+	// MaxAttempts limits the number of attempts for errors with the kind.
+	// If it is 0, the policy's MaxAttempts is used.
+	MaxAttempts int
+}
+
+// Retrier kinds declare if they can be retried.
+type Retrier interface {
+	RetryHintsFor(kind erk.Kind) Hints
+}
+
+// RetryableKind can be embedded in error kinds that can be retried.
+type RetryableKind struct{}
+
+// RetryableKind implements Retrier.
+var _ Retrier = RetryableKind{}
+
+// RetryHintsFor the provided kind.
+func (RetryableKind) RetryHintsFor(erk.Kind) Hints {
+	return Hints{Retryable: true}
+}
+
+// HintsFor the provided error.
+//
+// The hints are declared by the kind of the first error in the chain of wrapped errors that implements Retrier.
+// If an error group is found in the chain first, the group is retryable only if every error in the group is retryable,
+// and the smallest MaxAttempts is used. This allows an error wrapping a group to override the hints of the group.
+//
+// If no hints are declared, the returned hints are not retryable.
+func HintsFor(err error) Hints {
+	if err == nil {
+		return Hints{}
+	}
+
+	for currentErr := err; currentErr != nil; currentErr = errors.Unwrap(currentErr) {
+		if g, ok := currentErr.(erg.Groupable); ok {
+			return hintsForGroup(g)
+		}
+
+		kindable, ok := currentErr.(erk.Kindable)
+		if !ok {
+			continue
+		}
+
+		kind := kindable.Kind()
+		if retrier, ok := kind.(Retrier); ok {
+			return retrier.RetryHintsFor(kind)
+		}
+	}
+
+	return Hints{}
+}
+
+func hintsForGroup(g erg.Groupable) Hints {
+	errs := g.Errors()
+	if len(errs) == 0 {
+		return Hints{}
+	}
+
+	groupHints := Hints{Retryable: true}
+	for _, err := range errs {
+		hints := HintsFor(err)
+		if !hints.Retryable {
+			return Hints{}
+		}
+
+		if hints.MaxAttempts > 0 && (groupHints.MaxAttempts == 0 || hints.MaxAttempts < groupHints.MaxAttempts) {
+			groupHints.MaxAttempts = hints.MaxAttempts
+		}
+	}
+
+	return groupHints
+}
+
+// IsRetryable reports if the error can be retried.
+// See HintsFor for details.
+func IsRetryable(err error) bool {
+	return HintsFor(err).Retryable
+}
+
+// RetryAfter returns how long to wait before retrying, using the RetryAfterParam.
+// For error groups, the longest wait of any error in the group is returned.
+//
+// The second return value is false if no valid RetryAfterParam is present.
+func RetryAfter(err error, now time.Time) (time.Duration, bool) {
+	var g erg.Groupable
+	if errors.As(err, &g) {
+		return retryAfterForGroup(g, now)
+	}
+
+	switch retryAfter := erk.GetParams(err)[RetryAfterParam].(type) {
+	case time.Duration:
+		return retryAfter, true
+	case time.Time:
+		return retryAfter.Sub(now), true
+	default:
+		return 0, false
+	}
+}
+
+func retryAfterForGroup(g erg.Groupable, now time.Time) (time.Duration, bool) {
+	var maxRetryAfter time.Duration
+	found := false
+
+	for _, err := range g.Errors() {
+		if retryAfter, ok := RetryAfter(err, now); ok && (!found || retryAfter > maxRetryAfter) {
+			maxRetryAfter = retryAfter
+			found = true
+		}
+	}
+
+	return maxRetryAfter, found
+}
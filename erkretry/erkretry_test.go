@@ -0,0 +1,148 @@
+package erkretry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+	"github.com/JosiahWitt/erk/erkretry"
+)
+
+type (
+	ErkPermanent struct{ erk.DefaultKind }
+	ErkRetryable struct {
+		erk.DefaultKind
+		erkretry.RetryableKind
+	}
+	ErkLimited struct{ erk.DefaultKind }
+)
+
+func (ErkLimited) RetryHintsFor(erk.Kind) erkretry.Hints {
+	return erkretry.Hints{Retryable: true, MaxAttempts: 2}
+}
+
+var (
+	errPermanent = erk.New(ErkPermanent{}, "permanent")
+	errRetryable = erk.New(ErkRetryable{}, "retryable")
+	errLimited   = erk.New(ErkLimited{}, "limited")
+)
+
+func TestHintsFor(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name     string
+		Err      error
+		Expected erkretry.Hints
+	}{
+		{Name: "nil error", Err: nil, Expected: erkretry.Hints{}},
+		{Name: "non erk error", Err: errors.New("plain"), Expected: erkretry.Hints{}},
+		{Name: "kind without hints", Err: errPermanent, Expected: erkretry.Hints{}},
+		{Name: "retryable kind", Err: errRetryable, Expected: erkretry.Hints{Retryable: true}},
+		{Name: "kind with max attempts", Err: errLimited, Expected: erkretry.Hints{Retryable: true, MaxAttempts: 2}},
+		{
+			Name:     "kind without hints wrapping retryable error",
+			Err:      erk.WrapAs(errPermanent, errRetryable),
+			Expected: erkretry.Hints{Retryable: true},
+		},
+		{
+			Name:     "non erk error wrapping retryable error",
+			Err:      wrappedError{err: errLimited},
+			Expected: erkretry.Hints{Retryable: true, MaxAttempts: 2},
+		},
+		{
+			Name:     "group with only retryable errors",
+			Err:      erg.NewAs(errPermanent, errRetryable, errLimited),
+			Expected: erkretry.Hints{Retryable: true, MaxAttempts: 2},
+		},
+		{
+			Name:     "group with a permanent error",
+			Err:      erg.NewAs(errPermanent, errRetryable, errPermanent),
+			Expected: erkretry.Hints{},
+		},
+		{
+			Name:     "empty group",
+			Err:      erg.NewAs(errRetryable),
+			Expected: erkretry.Hints{},
+		},
+		{
+			Name:     "kind with hints wrapping group with a permanent error",
+			Err:      erk.WrapAs(errLimited, erg.NewAs(errPermanent, errPermanent)),
+			Expected: erkretry.Hints{Retryable: true, MaxAttempts: 2},
+		},
+		{
+			Name:     "kind without hints wrapping group with only retryable errors",
+			Err:      erk.WrapAs(errPermanent, erg.NewAs(errPermanent, errRetryable)),
+			Expected: erkretry.Hints{Retryable: true},
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+		ensure(erkretry.HintsFor(entry.Err)).Equals(entry.Expected)
+		ensure(erkretry.IsRetryable(entry.Err)).Equals(entry.Expected.Retryable)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	ensure := ensure.New(t)
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	table := []struct {
+		Name          string
+		Err           error
+		ExpectedDelay time.Duration
+		ExpectedOK    bool
+	}{
+		{Name: "without param", Err: errRetryable},
+		{Name: "with invalid param", Err: erk.WithParam(errRetryable, erkretry.RetryAfterParam, "soon")},
+		{
+			Name:          "with duration",
+			Err:           erk.WithParam(errRetryable, erkretry.RetryAfterParam, time.Minute),
+			ExpectedDelay: time.Minute,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "with time",
+			Err:           erk.WithParam(errRetryable, erkretry.RetryAfterParam, now.Add(time.Hour)),
+			ExpectedDelay: time.Hour,
+			ExpectedOK:    true,
+		},
+		{
+			Name: "with group",
+			Err: erg.NewAs(errPermanent,
+				errRetryable,
+				erk.WithParam(errRetryable, erkretry.RetryAfterParam, time.Second),
+				erk.WithParam(errRetryable, erkretry.RetryAfterParam, time.Minute),
+			),
+			ExpectedDelay: time.Minute,
+			ExpectedOK:    true,
+		},
+		{Name: "with group without params", Err: erg.NewAs(errPermanent, errRetryable)},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		delay, ok := erkretry.RetryAfter(entry.Err, now)
+		ensure(delay).Equals(entry.ExpectedDelay)
+		ensure(ok).Equals(entry.ExpectedOK)
+	})
+}
+
+type wrappedError struct {
+	err error
+}
+
+func (w wrappedError) Error() string {
+	return "wrapped: " + w.err.Error()
+}
+
+func (w wrappedError) Unwrap() error {
+	return w.err
+}
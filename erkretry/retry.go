@@ -0,0 +1,162 @@
+package erkretry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+)
+
+// Default policy values, used when the policy field is zero.
+const (
+	DefaultMaxAttempts  = 3
+	DefaultInitialDelay = 100 * time.Millisecond
+	DefaultMaxDelay     = 30 * time.Second
+	DefaultMultiplier   = 2
+)
+
+// ErkRetriesFailed is the kind of the error group returned when all attempts fail.
+type ErkRetriesFailed struct{ erk.DefaultKind }
+
+// ErrRetriesFailed is the header of the error group returned when all attempts fail.
+// The group contains the error from each attempt.
+var ErrRetriesFailed = erk.New(ErkRetriesFailed{}, "failed after {{.attempts}} attempts")
+
+// Policy for retrying a function.
+// Zero fields use the default values.
+type Policy struct {
+	// MaxAttempts is the maximum number of times the function is called, including the first call.
+	MaxAttempts int
+
+	// InitialDelay is the delay before the second attempt.
+	InitialDelay time.Duration
+
+	// MaxDelay caps the delay between attempts, excluding retry after hints.
+	MaxDelay time.Duration
+
+	// Multiplier increases the delay after each attempt.
+	Multiplier float64
+
+	// Jitter randomly reduces each delay by up to the provided fraction, between 0 and 1.
+	// For example, a Jitter of 0.5 results in a delay between 50% and 100% of the computed delay.
+	Jitter float64
+
+	// Clock used to wait between attempts. Defaults to RealClock.
+	Clock Clock
+
+	// Rand returns a random number in [0, 1) used for jitter. Defaults to rand.Float64.
+	Rand func() float64
+}
+
+// Retry calling fn until it succeeds, returns an error that is not retryable, or the attempts are exhausted.
+//
+// The number of attempts is limited by the policy and the MaxAttempts hint of the returned error's kind.
+// The delay between attempts grows exponentially, but waits at least as long as the RetryAfterParam of the returned error.
+// If the context is done while waiting, its error is added to the returned errors.
+//
+// If fn was only called once, its error is returned directly.
+// Otherwise, an error group with the ErrRetriesFailed header contains the error from each attempt.
+func Retry(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	policy = policy.WithDefaults()
+
+	var errs []error
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, err)
+
+		hints := HintsFor(err)
+		if !hints.Retryable || attempt >= policy.MaxAttemptsFor(hints) {
+			return buildRetryError(errs, attempt)
+		}
+
+		if ctxErr := wait(ctx, policy.Clock, policy.delayFor(attempt, err)); ctxErr != nil {
+			return buildRetryError(append(errs, ctxErr), attempt)
+		}
+	}
+}
+
+func wait(ctx context.Context, clock Clock, delay time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clock.After(delay):
+		return nil
+	}
+}
+
+func buildRetryError(errs []error, attempts int) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return erg.NewAs(erk.WithParam(ErrRetriesFailed, "attempts", attempts), errs...)
+}
+
+// WithDefaults returns a copy of the policy, replacing zero fields with the default values used by Retry.
+func (p Policy) WithDefaults() Policy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = DefaultInitialDelay
+	}
+
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultMaxDelay
+	}
+
+	if p.Multiplier <= 0 {
+		p.Multiplier = DefaultMultiplier
+	}
+
+	if p.Clock == nil {
+		p.Clock = RealClock{}
+	}
+
+	if p.Rand == nil {
+		p.Rand = rand.Float64 //nolint:gosec // Jitter does not need to be cryptographically secure
+	}
+
+	return p
+}
+
+// MaxAttemptsFor returns the maximum number of attempts for errors with the hints, including the first attempt.
+// The hint's MaxAttempts is used if it is smaller than the policy's MaxAttempts.
+//
+// Call WithDefaults first, since a zero MaxAttempts is not replaced with the default.
+func (p Policy) MaxAttemptsFor(hints Hints) int {
+	if hints.MaxAttempts > 0 && hints.MaxAttempts < p.MaxAttempts {
+		return hints.MaxAttempts
+	}
+
+	return p.MaxAttempts
+}
+
+func (p Policy) delayFor(attempt int, err error) time.Duration {
+	delay := float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(attempt-1))
+	if delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		delay -= delay * math.Min(p.Jitter, 1) * p.Rand()
+	}
+
+	if retryAfter, ok := RetryAfter(err, p.Clock.Now()); ok && retryAfter > time.Duration(delay) {
+		return retryAfter
+	}
+
+	return time.Duration(delay)
+}
@@ -0,0 +1,152 @@
+package erkretry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+	"github.com/JosiahWitt/erk/erkretry"
+)
+
+func TestRetry(t *testing.T) {
+	ensure := ensure.New(t)
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// returnErrors returns a function that returns each of the errors in order, and counts the calls.
+	returnErrors := func(calls *int, errs ...error) func(context.Context) error {
+		return func(context.Context) error {
+			*calls++
+			if *calls > len(errs) {
+				return nil
+			}
+
+			return errs[*calls-1]
+		}
+	}
+
+	ensure.Run("when first attempt succeeds", func(ensure ensurepkg.Ensure) {
+		clock := erkretry.NewFakeClock(now)
+		calls := 0
+
+		err := erkretry.Retry(context.Background(), erkretry.Policy{Clock: clock}, returnErrors(&calls))
+		ensure(err).IsNotError()
+		ensure(calls).Equals(1)
+		ensure(clock.Waits()).IsEmpty()
+	})
+
+	ensure.Run("when a retry succeeds", func(ensure ensurepkg.Ensure) {
+		clock := erkretry.NewFakeClock(now)
+		calls := 0
+
+		err := erkretry.Retry(context.Background(), erkretry.Policy{Clock: clock}, returnErrors(&calls, errRetryable, errRetryable))
+		ensure(err).IsNotError()
+		ensure(calls).Equals(3)
+		ensure(clock.Waits()).Equals([]time.Duration{100 * time.Millisecond, 200 * time.Millisecond})
+	})
+
+	ensure.Run("when error is not retryable", func(ensure ensurepkg.Ensure) {
+		clock := erkretry.NewFakeClock(now)
+		calls := 0
+
+		err := erkretry.Retry(context.Background(), erkretry.Policy{Clock: clock}, returnErrors(&calls, errPermanent))
+		ensure(err).Equals(errPermanent)
+		ensure(calls).Equals(1)
+		ensure(clock.Waits()).IsEmpty()
+	})
+
+	ensure.Run("when a later error is not retryable", func(ensure ensurepkg.Ensure) {
+		clock := erkretry.NewFakeClock(now)
+		calls := 0
+
+		err := erkretry.Retry(context.Background(), erkretry.Policy{Clock: clock}, returnErrors(&calls, errRetryable, errPermanent))
+		ensure(errors.Is(err, erkretry.ErrRetriesFailed)).IsTrue()
+		ensure(errors.Is(err, errPermanent)).IsTrue()
+		ensure(erg.GetErrors(err)).Equals([]error{errRetryable, errPermanent})
+		ensure(err.Error()).Equals("failed after 2 attempts:\n - retryable\n - permanent")
+		ensure(calls).Equals(2)
+	})
+
+	ensure.Run("when attempts are exhausted", func(ensure ensurepkg.Ensure) {
+		clock := erkretry.NewFakeClock(now)
+		calls := 0
+
+		policy := erkretry.Policy{
+			MaxAttempts:  4,
+			InitialDelay: time.Second,
+			MaxDelay:     3 * time.Second,
+			Multiplier:   2,
+			Clock:        clock,
+		}
+
+		err := erkretry.Retry(context.Background(), policy, returnErrors(&calls, errRetryable, errRetryable, errRetryable, errRetryable))
+		ensure(erg.GetErrors(err)).Equals([]error{errRetryable, errRetryable, errRetryable, errRetryable})
+		ensure(erk.GetParams(err)).Equals(erk.Params{"attempts": 4})
+		ensure(calls).Equals(4)
+		ensure(clock.Waits()).Equals([]time.Duration{time.Second, 2 * time.Second, 3 * time.Second})
+	})
+
+	ensure.Run("when kind limits max attempts", func(ensure ensurepkg.Ensure) {
+		clock := erkretry.NewFakeClock(now)
+		calls := 0
+
+		err := erkretry.Retry(context.Background(), erkretry.Policy{MaxAttempts: 5, Clock: clock}, returnErrors(&calls, errLimited, errLimited, errLimited))
+		ensure(erg.GetErrors(err)).Equals([]error{errLimited, errLimited})
+		ensure(calls).Equals(2)
+	})
+
+	ensure.Run("with jitter", func(ensure ensurepkg.Ensure) {
+		clock := erkretry.NewFakeClock(now)
+		calls := 0
+
+		policy := erkretry.Policy{
+			InitialDelay: time.Second,
+			Jitter:       0.5,
+			Clock:        clock,
+			Rand:         func() float64 { return 0.5 },
+		}
+
+		err := erkretry.Retry(context.Background(), policy, returnErrors(&calls, errRetryable, errRetryable))
+		ensure(err).IsNotError()
+		ensure(clock.Waits()).Equals([]time.Duration{750 * time.Millisecond, 1500 * time.Millisecond})
+	})
+
+	ensure.Run("with retry after param", func(ensure ensurepkg.Ensure) {
+		clock := erkretry.NewFakeClock(now)
+		calls := 0
+
+		err := erkretry.Retry(context.Background(), erkretry.Policy{Clock: clock}, returnErrors(&calls,
+			erk.WithParam(errRetryable, erkretry.RetryAfterParam, time.Minute),
+			erk.WithParam(errRetryable, erkretry.RetryAfterParam, now.Add(time.Minute)), // Fake clock has advanced a minute
+		))
+		ensure(err).IsNotError()
+		ensure(clock.Waits()).Equals([]time.Duration{time.Minute, 200 * time.Millisecond})
+	})
+
+	ensure.Run("when context is canceled", func(ensure ensurepkg.Ensure) {
+		clock := erkretry.NewFakeClock(now)
+		calls := 0
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := erkretry.Retry(ctx, erkretry.Policy{Clock: clock}, returnErrors(&calls, errRetryable))
+		ensure(erg.GetErrors(err)).Equals([]error{errRetryable, context.Canceled})
+		ensure(err.Error()).Equals("failed after 1 attempts:\n - retryable\n - context canceled")
+		ensure(calls).Equals(1)
+		ensure(clock.Waits()).IsEmpty()
+	})
+
+	ensure.Run("with real clock", func(ensure ensurepkg.Ensure) {
+		calls := 0
+
+		err := erkretry.Retry(context.Background(), erkretry.Policy{InitialDelay: time.Nanosecond}, returnErrors(&calls, errRetryable))
+		ensure(err).IsNotError()
+		ensure(calls).Equals(2)
+	})
+}
@@ -0,0 +1,82 @@
+package erkstrict
+
+import "regexp"
+
+// Check is an optional strict mode check of error params.
+// Checks only run in strict mode, and are disabled by default.
+type Check string
+
+// Optional strict mode checks.
+const (
+	// CheckUnusedParams reports params that are set, but not referenced by the message template.
+	// The wrapped error param is allowed to be unused, since it is also used to unwrap the error.
+	// Errors with erk's built in kinds, and errors with literal messages, are not checked.
+	CheckUnusedParams Check = "unused-params"
+
+	// CheckReservedErrParam reports values of the wrapped error param ("err") that are not errors.
+	CheckReservedErrParam Check = "reserved-err-param"
+
+	// CheckJSONParams reports param values that cannot be marshalled to JSON, which would prevent exporting the error as JSON.
+	CheckJSONParams Check = "json-params"
+
+	// CheckParamNames reports param keys that do not match the param name pattern.
+	// See SetParamNamePattern.
+	CheckParamNames Check = "param-names"
+)
+
+// DefaultParamNamePattern requires param keys to be lowerCamelCase, such as "userID".
+//
+//nolint:gochecknoglobals // Read only
+var DefaultParamNamePattern = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+// AllChecks returns every optional check.
+func AllChecks() []Check {
+	return []Check{CheckUnusedParams, CheckReservedErrParam, CheckJSONParams, CheckParamNames}
+}
+
+// EnableChecks in the default configuration, so they run in strict mode.
+// Package configurations are not changed.
+func EnableChecks(checks ...Check) {
+	state.Lock()
+	defer state.Unlock()
+
+	state.parseIfUnset()
+	state.defaults.Checks = appendChecks(state.defaults.Checks, checks...)
+}
+
+// DisableChecks in the default configuration, so they no longer run in strict mode.
+// Package configurations are not changed.
+func DisableChecks(checks ...Check) {
+	state.Lock()
+	defer state.Unlock()
+
+	state.parseIfUnset()
+	state.defaults.Checks = removeChecks(state.defaults.Checks, checks...)
+}
+
+// IsCheckEnabled reports if strict mode and the check are enabled by default.
+// See IsCheckEnabledFor.
+func IsCheckEnabled(check Check) bool {
+	return IsCheckEnabledFor("", check)
+}
+
+// SetParamNamePattern used by CheckParamNames.
+// A nil pattern resets it to DefaultParamNamePattern.
+func SetParamNamePattern(pattern *regexp.Regexp) {
+	if pattern == nil {
+		pattern = DefaultParamNamePattern
+	}
+
+	state.Lock()
+	defer state.Unlock()
+
+	state.paramNamePattern = pattern
+}
+
+// IsValidParamName reports if the param key matches the param name pattern.
+func IsValidParamName(name string) bool {
+	state.RLock()
+	defer state.RUnlock()
+
+	return state.paramNamePattern.MatchString(name)
+}
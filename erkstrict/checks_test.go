@@ -0,0 +1,95 @@
+package erkstrict_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk/erkstrict"
+)
+
+func TestIsCheckEnabled(t *testing.T) {
+	ensure := ensure.New(t)
+
+	defer erkstrict.UnsetStrictMode()
+	defer erkstrict.DisableChecks(erkstrict.AllChecks()...)
+
+	ensure.Run("when disabled by default", func(ensure ensurepkg.Ensure) {
+		erkstrict.SetStrictMode(true)
+
+		for _, check := range erkstrict.AllChecks() {
+			ensure(erkstrict.IsCheckEnabled(check)).IsFalse()
+		}
+	})
+
+	ensure.Run("when enabled in strict mode", func(ensure ensurepkg.Ensure) {
+		erkstrict.SetStrictMode(true)
+		erkstrict.EnableChecks(erkstrict.CheckUnusedParams, erkstrict.CheckParamNames)
+		defer erkstrict.DisableChecks(erkstrict.AllChecks()...)
+
+		ensure(erkstrict.IsCheckEnabled(erkstrict.CheckUnusedParams)).IsTrue()
+		ensure(erkstrict.IsCheckEnabled(erkstrict.CheckParamNames)).IsTrue()
+		ensure(erkstrict.IsCheckEnabled(erkstrict.CheckJSONParams)).IsFalse()
+	})
+
+	ensure.Run("when enabled outside strict mode", func(ensure ensurepkg.Ensure) {
+		erkstrict.SetStrictMode(false)
+		erkstrict.EnableChecks(erkstrict.AllChecks()...)
+		defer erkstrict.DisableChecks(erkstrict.AllChecks()...)
+
+		for _, check := range erkstrict.AllChecks() {
+			ensure(erkstrict.IsCheckEnabled(check)).IsFalse()
+		}
+	})
+
+	ensure.Run("when disabled after being enabled", func(ensure ensurepkg.Ensure) {
+		erkstrict.SetStrictMode(true)
+		erkstrict.EnableChecks(erkstrict.AllChecks()...)
+		erkstrict.DisableChecks(erkstrict.CheckJSONParams)
+		defer erkstrict.DisableChecks(erkstrict.AllChecks()...)
+
+		ensure(erkstrict.IsCheckEnabled(erkstrict.CheckJSONParams)).IsFalse()
+		ensure(erkstrict.IsCheckEnabled(erkstrict.CheckReservedErrParam)).IsTrue()
+	})
+}
+
+func TestIsValidParamName(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with default pattern", func(ensure ensurepkg.Ensure) {
+		table := []struct {
+			Name     string
+			Param    string
+			Expected bool
+		}{
+			{Name: "lower", Param: "key", Expected: true},
+			{Name: "lower camel case", Param: "userID", Expected: true},
+			{Name: "with digits", Param: "value2", Expected: true},
+			{Name: "upper camel case", Param: "UserID", Expected: false},
+			{Name: "snake case", Param: "user_id", Expected: false},
+			{Name: "kebab case", Param: "user-id", Expected: false},
+			{Name: "empty", Param: "", Expected: false},
+		}
+
+		ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+			entry := table[i]
+			ensure(erkstrict.IsValidParamName(entry.Param)).Equals(entry.Expected)
+		})
+	})
+
+	ensure.Run("with custom pattern", func(ensure ensurepkg.Ensure) {
+		erkstrict.SetParamNamePattern(regexp.MustCompile(`^[a-z][a-z_]*$`))
+		defer erkstrict.SetParamNamePattern(nil)
+
+		ensure(erkstrict.IsValidParamName("user_id")).IsTrue()
+		ensure(erkstrict.IsValidParamName("userID")).IsFalse()
+	})
+
+	ensure.Run("after resetting pattern", func(ensure ensurepkg.Ensure) {
+		erkstrict.SetParamNamePattern(regexp.MustCompile(`^x$`))
+		erkstrict.SetParamNamePattern(nil)
+
+		ensure(erkstrict.IsValidParamName("userID")).IsTrue()
+	})
+}
@@ -0,0 +1,347 @@
+package erkstrict
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Level of strict mode, which determines how violations are handled.
+type Level int
+
+// Strict mode levels.
+const (
+	// LevelOff disables strict mode, so invalid templates render as well as possible.
+	LevelOff Level = iota
+
+	// LevelWarn reports violations to the handler, or writes them to stderr, and then renders as if strict mode was off.
+	LevelWarn
+
+	// LevelPanic reports violations to the handler, or panics.
+	LevelPanic
+)
+
+// String returns the name of the level, as used in the ERK_STRICT_MODE environment variable.
+func (l Level) String() string {
+	switch l {
+	case LevelOff:
+		return "off"
+	case LevelWarn:
+		return "warn"
+	case LevelPanic:
+		return "panic"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+}
+
+// Violation of strict mode.
+type Violation struct {
+	// Check that found the violation, or empty if it was found by the always enabled template checks.
+	Check Check
+
+	// PackagePath of the error's kind, which was used to select the configuration.
+	PackagePath string
+
+	// Message describing the violation.
+	Message string
+
+	// Err that caused the violation, such as the error being created or rendered.
+	Err error
+}
+
+// ViolationError is the panic value of violations reported at LevelPanic.
+// It unwraps to the error that caused the violation, so errors.Is works with the recovered value.
+type ViolationError struct {
+	Violation Violation
+}
+
+// Error returns the message describing the violation.
+func (e *ViolationError) Error() string {
+	return e.Violation.Message
+}
+
+// Unwrap returns the error that caused the violation.
+func (e *ViolationError) Unwrap() error {
+	return e.Violation.Err
+}
+
+// ViolationHandler is called for each violation, instead of panicking or writing to stderr.
+type ViolationHandler func(level Level, v Violation)
+
+// Config of strict mode.
+type Config struct {
+	// Level of strict mode.
+	Level Level
+
+	// Checks that are enabled, in addition to the always enabled template checks.
+	// Checks only run if the level is not LevelOff.
+	Checks []Check
+
+	// Handler for violations.
+	// If nil, violations panic at LevelPanic, and are written to stderr at LevelWarn.
+	Handler ViolationHandler
+}
+
+// IsCheckEnabled reports if the level is not LevelOff, and the check is enabled.
+func (c Config) IsCheckEnabled(check Check) bool {
+	if c.Level == LevelOff {
+		return false
+	}
+
+	for _, enabled := range c.Checks {
+		if enabled == check {
+			return true
+		}
+	}
+
+	return false
+}
+
+// packageConfig applies to packages with the path prefix.
+type packageConfig struct {
+	prefix string
+	config Config
+}
+
+// matches reports if the package path is the prefix, or is nested under it.
+func (p packageConfig) matches(pkgPath string) bool {
+	prefix := strings.TrimSuffix(p.prefix, "/")
+	return pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/")
+}
+
+// Configure strict mode for every package without a package configuration.
+func Configure(config Config) {
+	state.Lock()
+	defer state.Unlock()
+
+	state.parseIfUnset()
+	state.defaults = cloneConfig(config)
+}
+
+// ConfigurePackage configures strict mode for packages with the path prefix, such as "github.com/acme".
+// Packages are matched by the path of their error's kind, and the longest matching prefix is used.
+//
+// This allows enabling strict mode for your own packages, but not for third party packages that use erk.
+func ConfigurePackage(prefix string, config Config) {
+	state.Lock()
+	defer state.Unlock()
+
+	state.parseIfUnset()
+	state.setPackage(prefix, cloneConfig(config))
+}
+
+// DefaultConfig returns the configuration for packages without a package configuration.
+func DefaultConfig() Config {
+	state.ensureParsed()
+
+	state.RLock()
+	defer state.RUnlock()
+
+	return cloneConfig(state.defaults)
+}
+
+// ConfigFor returns the configuration for the package path, using the longest matching package configuration.
+// An empty package path uses the default configuration.
+func ConfigFor(pkgPath string) Config {
+	state.ensureParsed()
+
+	state.RLock()
+	defer state.RUnlock()
+
+	return cloneConfig(state.configFor(pkgPath))
+}
+
+// IsStrictModeFor reports if strict mode is enabled for the package path.
+// Unlike ConfigFor, it does not allocate, so it can be called each time an error is rendered.
+func IsStrictModeFor(pkgPath string) bool {
+	state.ensureParsed()
+
+	state.RLock()
+	defer state.RUnlock()
+
+	return state.configFor(pkgPath).Level != LevelOff
+}
+
+// IsCheckEnabledFor reports if strict mode and the check are enabled for the package path.
+// Unlike ConfigFor, it does not allocate.
+func IsCheckEnabledFor(pkgPath string, check Check) bool {
+	state.ensureParsed()
+
+	state.RLock()
+	defer state.RUnlock()
+
+	return state.configFor(pkgPath).IsCheckEnabled(check)
+}
+
+// Report the violation using the configuration for its package path.
+//
+// If the configuration has a handler, it is called.
+// Otherwise, at LevelPanic it panics with a *ViolationError, and at LevelWarn it writes the message to stderr.
+// Nothing happens at LevelOff.
+func Report(v Violation) {
+	config := ConfigFor(v.PackagePath)
+
+	switch {
+	case config.Level == LevelOff:
+		return
+	case config.Handler != nil:
+		config.Handler(config.Level, v)
+	case config.Level == LevelPanic:
+		panic(&ViolationError{Violation: v})
+	default:
+		fmt.Fprintln(os.Stderr, "erk strict mode warning:", v.Message)
+	}
+}
+
+// ParseConfig parses the ERK_STRICT_MODE environment variable syntax.
+//
+// The value contains sections separated by semicolons.
+// Each section is a comma separated list of a level ("panic", "warn", or "off") and checks (see Check), or "all" to enable every check.
+// For compatibility, "true" is the same as "panic", and "false" is the same as "off".
+// If a section has checks but no level, the level is "panic".
+//
+// The section without a prefix configures the default, and sections prefixed by "path/prefix=" configure packages.
+// If there is no default section, strict mode is off by default.
+//
+// Example: "warn,unused-params;github.com/acme=panic,all".
+func ParseConfig(value string) (Config, map[string]Config, error) {
+	defaults := Config{Level: LevelOff}
+	packages := map[string]Config{}
+
+	for _, section := range strings.Split(value, ";") {
+		section = strings.TrimSpace(section)
+		if section == "" {
+			continue
+		}
+
+		prefix := ""
+		if i := strings.IndexByte(section, '='); i >= 0 {
+			prefix, section = strings.TrimSpace(section[:i]), section[i+1:]
+			if prefix == "" {
+				return Config{}, nil, fmt.Errorf("erkstrict: missing package prefix in %q", value) //nolint:err113 // erk cannot be imported
+			}
+		}
+
+		config, err := parseSection(section)
+		if err != nil {
+			return Config{}, nil, err
+		}
+
+		if prefix == "" {
+			defaults = config
+		} else {
+			packages[prefix] = config
+		}
+	}
+
+	return defaults, packages, nil
+}
+
+func parseSection(section string) (Config, error) {
+	config := Config{Level: LevelOff}
+	hasLevel := false
+
+	for _, token := range strings.Split(section, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+
+		switch token {
+		case "":
+			continue
+		case "panic", "true":
+			config.Level, hasLevel = LevelPanic, true
+		case "warn":
+			config.Level, hasLevel = LevelWarn, true
+		case "off", "false":
+			config.Level, hasLevel = LevelOff, true
+		case "all":
+			config.Checks = appendChecks(config.Checks, AllChecks()...)
+		default:
+			if !isKnownCheck(Check(token)) {
+				return Config{}, fmt.Errorf("erkstrict: unknown level or check %q", token) //nolint:err113 // erk cannot be imported
+			}
+
+			config.Checks = appendChecks(config.Checks, Check(token))
+		}
+	}
+
+	if !hasLevel && len(config.Checks) > 0 {
+		config.Level = LevelPanic
+	}
+
+	return config, nil
+}
+
+func (s *strictState) setPackage(prefix string, config Config) {
+	if prefix == "" {
+		s.defaults = config
+		return
+	}
+
+	for i := range s.packages {
+		if s.packages[i].prefix == prefix {
+			s.packages[i].config = config
+			return
+		}
+	}
+
+	s.packages = append(s.packages, packageConfig{prefix: prefix, config: config})
+
+	// Check the longest prefixes first
+	sort.SliceStable(s.packages, func(i, j int) bool {
+		return len(s.packages[i].prefix) > len(s.packages[j].prefix)
+	})
+}
+
+func (s *strictState) configFor(pkgPath string) Config {
+	if pkgPath != "" {
+		for _, p := range s.packages {
+			if p.matches(pkgPath) {
+				return p.config
+			}
+		}
+	}
+
+	return s.defaults
+}
+
+func cloneConfig(config Config) Config {
+	config.Checks = append([]Check(nil), config.Checks...)
+	return config
+}
+
+func appendChecks(checks []Check, toAdd ...Check) []Check {
+	for _, check := range toAdd {
+		if !containsCheck(checks, check) {
+			checks = append(checks, check)
+		}
+	}
+
+	return checks
+}
+
+func removeChecks(checks []Check, toRemove ...Check) []Check {
+	kept := make([]Check, 0, len(checks))
+	for _, check := range checks {
+		if !containsCheck(toRemove, check) {
+			kept = append(kept, check)
+		}
+	}
+
+	return kept
+}
+
+func containsCheck(checks []Check, check Check) bool {
+	for _, c := range checks {
+		if c == check {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isKnownCheck(check Check) bool {
+	return containsCheck(AllChecks(), check)
+}
@@ -0,0 +1,284 @@
+package erkstrict_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk/erkstrict"
+)
+
+func TestParseConfig(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name             string
+		Value            string
+		ExpectedDefaults erkstrict.Config
+		ExpectedPackages map[string]erkstrict.Config
+		ExpectedError    bool
+	}{
+		{
+			Name:             "empty",
+			Value:            "",
+			ExpectedDefaults: erkstrict.Config{Level: erkstrict.LevelOff},
+			ExpectedPackages: map[string]erkstrict.Config{},
+		},
+		{
+			Name:             "true",
+			Value:            "true",
+			ExpectedDefaults: erkstrict.Config{Level: erkstrict.LevelPanic},
+			ExpectedPackages: map[string]erkstrict.Config{},
+		},
+		{
+			Name:             "false",
+			Value:            "false",
+			ExpectedDefaults: erkstrict.Config{Level: erkstrict.LevelOff},
+			ExpectedPackages: map[string]erkstrict.Config{},
+		},
+		{
+			Name:  "level with checks",
+			Value: "warn, unused-params,JSON-PARAMS",
+			ExpectedDefaults: erkstrict.Config{
+				Level:  erkstrict.LevelWarn,
+				Checks: []erkstrict.Check{erkstrict.CheckUnusedParams, erkstrict.CheckJSONParams},
+			},
+			ExpectedPackages: map[string]erkstrict.Config{},
+		},
+		{
+			Name:  "checks without level",
+			Value: "param-names",
+			ExpectedDefaults: erkstrict.Config{
+				Level:  erkstrict.LevelPanic,
+				Checks: []erkstrict.Check{erkstrict.CheckParamNames},
+			},
+			ExpectedPackages: map[string]erkstrict.Config{},
+		},
+		{
+			Name:  "all checks",
+			Value: "panic,unused-params,all",
+			ExpectedDefaults: erkstrict.Config{
+				Level:  erkstrict.LevelPanic,
+				Checks: erkstrict.AllChecks(),
+			},
+			ExpectedPackages: map[string]erkstrict.Config{},
+		},
+		{
+			Name:             "package sections",
+			Value:            "off; github.com/acme=panic,all ;github.com/acme/legacy=warn",
+			ExpectedDefaults: erkstrict.Config{Level: erkstrict.LevelOff},
+			ExpectedPackages: map[string]erkstrict.Config{
+				"github.com/acme":        {Level: erkstrict.LevelPanic, Checks: erkstrict.AllChecks()},
+				"github.com/acme/legacy": {Level: erkstrict.LevelWarn},
+			},
+		},
+		{
+			Name:             "package sections without default",
+			Value:            "github.com/acme=panic",
+			ExpectedDefaults: erkstrict.Config{Level: erkstrict.LevelOff},
+			ExpectedPackages: map[string]erkstrict.Config{
+				"github.com/acme": {Level: erkstrict.LevelPanic},
+			},
+		},
+		{
+			Name:          "unknown token",
+			Value:         "panic,something",
+			ExpectedError: true,
+		},
+		{
+			Name:          "missing prefix",
+			Value:         "=panic",
+			ExpectedError: true,
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		defaults, packages, err := erkstrict.ParseConfig(entry.Value)
+		ensure(err != nil).Equals(entry.ExpectedError)
+		if entry.ExpectedError {
+			return
+		}
+
+		ensure(defaults).Equals(entry.ExpectedDefaults)
+		ensure(packages).Equals(entry.ExpectedPackages)
+	})
+}
+
+func TestLevelString(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure(erkstrict.LevelOff.String()).Equals("off")
+	ensure(erkstrict.LevelWarn.String()).Equals("warn")
+	ensure(erkstrict.LevelPanic.String()).Equals("panic")
+	ensure(erkstrict.Level(10).String()).Equals("Level(10)")
+}
+
+func TestConfigFor(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("from environment", func(ensure ensurepkg.Ensure) {
+		defer erkstrict.UnsetStrictMode()
+
+		withErkStrictEnv("warn;github.com/acme=panic,unused-params;github.com/acme/legacy=off", func() {
+			erkstrict.UnsetStrictMode()
+
+			ensure(erkstrict.IsStrictMode()).IsTrue()
+			ensure(erkstrict.DefaultConfig().Level).Equals(erkstrict.LevelWarn)
+			ensure(erkstrict.ConfigFor("").Level).Equals(erkstrict.LevelWarn)
+			ensure(erkstrict.ConfigFor("github.com/other").Level).Equals(erkstrict.LevelWarn)
+			ensure(erkstrict.ConfigFor("github.com/acmecorp").Level).Equals(erkstrict.LevelWarn)
+			ensure(erkstrict.ConfigFor("github.com/acme").Level).Equals(erkstrict.LevelPanic)
+			ensure(erkstrict.ConfigFor("github.com/acme/store").Level).Equals(erkstrict.LevelPanic)
+			ensure(erkstrict.IsCheckEnabledFor("github.com/acme/store", erkstrict.CheckUnusedParams)).IsTrue()
+			ensure(erkstrict.IsCheckEnabledFor("github.com/other", erkstrict.CheckUnusedParams)).IsFalse()
+			ensure(erkstrict.IsStrictModeFor("github.com/acme/legacy/store")).IsFalse()
+		})
+	})
+
+	ensure.Run("from invalid environment", func(ensure ensurepkg.Ensure) {
+		defer erkstrict.UnsetStrictMode()
+
+		withErkStrictEnv("panic,unknown", func() {
+			erkstrict.UnsetStrictMode()
+
+			ensure(erkstrict.IsStrictMode()).IsFalse()
+		})
+	})
+
+	ensure.Run("programmatically", func(ensure ensurepkg.Ensure) {
+		defer erkstrict.UnsetStrictMode()
+
+		erkstrict.Configure(erkstrict.Config{Level: erkstrict.LevelOff})
+		erkstrict.ConfigurePackage("github.com/acme", erkstrict.Config{Level: erkstrict.LevelWarn})
+		erkstrict.ConfigurePackage("github.com/acme/store/", erkstrict.Config{Level: erkstrict.LevelPanic})
+
+		ensure(erkstrict.IsStrictMode()).IsFalse()
+		ensure(erkstrict.ConfigFor("github.com/acme/api").Level).Equals(erkstrict.LevelWarn)
+		ensure(erkstrict.ConfigFor("github.com/acme/store").Level).Equals(erkstrict.LevelPanic) // The trailing slash is ignored
+		ensure(erkstrict.ConfigFor("github.com/acme/storefront").Level).Equals(erkstrict.LevelWarn)
+		ensure(erkstrict.ConfigFor("github.com/acme/store/sql").Level).Equals(erkstrict.LevelPanic)
+
+		erkstrict.ConfigurePackage("github.com/acme", erkstrict.Config{Level: erkstrict.LevelOff})
+		ensure(erkstrict.ConfigFor("github.com/acme/api").Level).Equals(erkstrict.LevelOff)
+	})
+
+	ensure.Run("without allocating when checking the package", func(ensure ensurepkg.Ensure) {
+		defer erkstrict.UnsetStrictMode()
+
+		erkstrict.Configure(erkstrict.Config{Level: erkstrict.LevelOff, Checks: erkstrict.AllChecks()})
+		erkstrict.ConfigurePackage("github.com/acme", erkstrict.Config{Level: erkstrict.LevelPanic, Checks: erkstrict.AllChecks()})
+
+		allocs := testing.AllocsPerRun(10, func() {
+			_ = erkstrict.IsStrictModeFor("github.com/acme/api")
+			_ = erkstrict.IsCheckEnabledFor("github.com/acme/api", erkstrict.CheckJSONParams)
+		})
+		ensure(allocs).Equals(float64(0))
+	})
+
+	ensure.Run("after unsetting strict mode", func(ensure ensurepkg.Ensure) {
+		defer erkstrict.UnsetStrictMode()
+
+		withErkStrictEnv("false", func() {
+			erkstrict.ConfigurePackage("github.com/acme", erkstrict.Config{Level: erkstrict.LevelPanic})
+			erkstrict.UnsetStrictMode()
+
+			ensure(erkstrict.IsStrictModeFor("github.com/acme")).IsFalse()
+		})
+	})
+
+	ensure.Run("when setting strict mode keeps checks", func(ensure ensurepkg.Ensure) {
+		defer erkstrict.UnsetStrictMode()
+
+		erkstrict.Configure(erkstrict.Config{Level: erkstrict.LevelWarn, Checks: []erkstrict.Check{erkstrict.CheckJSONParams}})
+		erkstrict.SetStrictMode(true)
+
+		ensure(erkstrict.DefaultConfig()).Equals(erkstrict.Config{
+			Level:  erkstrict.LevelPanic,
+			Checks: []erkstrict.Check{erkstrict.CheckJSONParams},
+		})
+	})
+}
+
+func TestReport(t *testing.T) {
+	ensure := ensure.New(t)
+
+	errCause := errors.New("cause")
+	violation := erkstrict.Violation{
+		Check:       erkstrict.CheckUnusedParams,
+		PackagePath: "github.com/acme/store",
+		Message:     "my violation",
+		Err:         errCause,
+	}
+
+	ensure.Run("when off", func(ensure ensurepkg.Ensure) {
+		defer erkstrict.UnsetStrictMode()
+
+		erkstrict.Configure(erkstrict.Config{Level: erkstrict.LevelPanic})
+		erkstrict.ConfigurePackage("github.com/acme", erkstrict.Config{Level: erkstrict.LevelOff})
+
+		erkstrict.Report(violation) // Does not panic
+	})
+
+	ensure.Run("when panic", func(ensure ensurepkg.Ensure) {
+		defer erkstrict.UnsetStrictMode()
+
+		erkstrict.Configure(erkstrict.Config{Level: erkstrict.LevelOff})
+		erkstrict.ConfigurePackage("github.com/acme", erkstrict.Config{Level: erkstrict.LevelPanic})
+
+		defer func() {
+			violationErr, ok := recover().(*erkstrict.ViolationError)
+			ensure(ok).IsTrue()
+			ensure(violationErr.Violation).Equals(violation)
+			ensure(violationErr.Error()).Equals("my violation")
+			ensure(errors.Is(violationErr, errCause)).IsTrue()
+		}()
+
+		erkstrict.Report(violation)
+		ensure.Failf("Expected panic, so this line should not be reached")
+	})
+
+	ensure.Run("when warn", func(ensure ensurepkg.Ensure) {
+		defer erkstrict.UnsetStrictMode()
+
+		erkstrict.Configure(erkstrict.Config{Level: erkstrict.LevelWarn})
+
+		erkstrict.Report(violation) // Writes to stderr
+	})
+
+	ensure.Run("with handler", func(ensure ensurepkg.Ensure) {
+		defer erkstrict.UnsetStrictMode()
+
+		var gotLevel erkstrict.Level
+		var got erkstrict.Violation
+		erkstrict.ConfigurePackage("github.com/acme", erkstrict.Config{
+			Level: erkstrict.LevelPanic,
+			Handler: func(level erkstrict.Level, v erkstrict.Violation) {
+				gotLevel, got = level, v
+			},
+		})
+
+		erkstrict.Report(violation) // Calls the handler instead of panicking
+		ensure(gotLevel).Equals(erkstrict.LevelPanic)
+		ensure(got).Equals(violation)
+	})
+
+	ensure.Run("with handler that panics", func(ensure ensurepkg.Ensure) {
+		defer erkstrict.UnsetStrictMode()
+
+		errViolation := errors.New("violation")
+		erkstrict.Configure(erkstrict.Config{
+			Level:   erkstrict.LevelWarn,
+			Handler: func(erkstrict.Level, erkstrict.Violation) { panic(errViolation) },
+		})
+
+		defer func() {
+			ensure(recover()).Equals(errViolation)
+		}()
+
+		erkstrict.Report(violation)
+		ensure.Failf("Expected panic, so this line should not be reached")
+	})
+}
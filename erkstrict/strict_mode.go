@@ -1,49 +1,115 @@
 // Package erkstrict controls if erk is running in strict mode.
+//
+// Strict mode is configured with a Config, which sets the Level, the optional checks, and the violation handler.
+// It can be configured using the ERK_STRICT_MODE environment variable (see ParseConfig), or programmatically using Configure.
+// Packages can be configured separately using ConfigurePackage.
 package erkstrict
 
 import (
+	"fmt"
 	"os"
+	"regexp"
 	"strings"
+	"sync"
 )
 
+// EnvVar is the environment variable used to configure strict mode.
+// See ParseConfig for the syntax.
+const EnvVar = "ERK_STRICT_MODE"
+
+type strictState struct {
+	sync.RWMutex
+	isSet            bool
+	defaults         Config
+	packages         []packageConfig // Sorted by longest prefix first
+	paramNamePattern *regexp.Regexp
+}
+
 //nolint:gochecknoglobals // Only used internally
-var (
-	isStrictModeSet bool
-	isStrictMode    bool
-)
+var state = &strictState{paramNamePattern: DefaultParamNamePattern}
 
-// IsStrictMode reports if erk is running in strict mode.
+// IsStrictMode reports if erk is running in strict mode by default, which is true unless the default level is LevelOff.
+// Packages can override the default using ConfigurePackage. See IsStrictModeFor.
 //
 // On the first run or after UnsetStrictMode is called, it reparses the strict mode.
-// If the ERK_STRICT_MODE environment variable is set, it uses that value ("true" to enable, "false" to disable).
+// If the ERK_STRICT_MODE environment variable is set, it is parsed using ParseConfig.
 // Otherwise, it checks if it is running under tests by looking for a -test.* flag, which is automatically added by `go test`.
 func IsStrictMode() bool {
-	if isStrictModeSet {
-		return isStrictMode
-	}
-
-	SetStrictMode(parseStrictMode())
-	return isStrictMode
+	return IsStrictModeFor("")
 }
 
-// UnsetStrictMode returns strict mode to the pristine state.
+// UnsetStrictMode returns strict mode to the pristine state, including the configured levels, checks, and handlers.
 // It will check again for the ERK_STRICT_MODE environment variable and -test.* flag.
 func UnsetStrictMode() {
-	isStrictModeSet = false
+	state.Lock()
+	defer state.Unlock()
+
+	state.isSet = false
+	state.defaults = Config{}
+	state.packages = nil
 }
 
 // SetStrictMode to the provided state.
+// Enabling strict mode sets the default level to LevelPanic, and disabling it sets the default level to LevelOff.
+// The enabled checks and the handler are kept.
 func SetStrictMode(enabled bool) {
-	isStrictMode = enabled
-	isStrictModeSet = true
+	state.Lock()
+	defer state.Unlock()
+
+	state.parseIfUnset()
+	state.defaults.Level = LevelOff
+	if enabled {
+		state.defaults.Level = LevelPanic
+	}
+}
+
+// ensureParsed parses the environment, unless strict mode has already been set.
+func (s *strictState) ensureParsed() {
+	s.RLock()
+	isSet := s.isSet
+	s.RUnlock()
+
+	if !isSet {
+		s.Lock()
+		s.parseIfUnset()
+		s.Unlock()
+	}
 }
 
-func parseStrictMode() bool {
-	strict, isSet := os.LookupEnv("ERK_STRICT_MODE")
-	if isSet {
-		return strict == "true"
+// parseIfUnset parses the environment, unless strict mode has already been set.
+// The lock must be held.
+func (s *strictState) parseIfUnset() {
+	if s.isSet {
+		return
+	}
+
+	s.isSet = true
+	s.defaults = Config{Level: LevelOff}
+	s.packages = nil
+
+	value, isSet := os.LookupEnv(EnvVar)
+	if !isSet {
+		if isRunningTests() {
+			s.defaults.Level = LevelPanic
+		}
+
+		return
+	}
+
+	defaults, packages, err := ParseConfig(value)
+	if err != nil {
+		// Don't panic, since the environment could be set for a different version of erk
+		fmt.Fprintf(os.Stderr, "erk strict mode warning: invalid %s, so strict mode is off: %v\n", EnvVar, err)
+		return
 	}
 
+	s.defaults = defaults
+	for prefix, config := range packages {
+		s.setPackage(prefix, config)
+	}
+}
+
+func isRunningTests() bool {
 	// Check the args for -test.* flags
 	for _, arg := range os.Args {
 		if strings.HasPrefix(arg, "-test.") {
@@ -0,0 +1,219 @@
+// Package erkwarning allows error kinds to declare a severity, such as a warning or a critical error.
+//
+// To use this, embed one of the severity kinds in your error kinds.
+// Kinds without a declared severity are treated as SeverityError.
+//
+// Example:
+//
+//	type ErkCacheMiss struct {
+//	  erk.DefaultKind
+//	  erkwarning.WarningKind
+//	}
+//
+//	...
+//
+//	// Return errors, and log warnings
+//	errs, warnings := erkwarning.Split(groupErr)
+//	if warnings != nil {
+//	  log.Println(warnings)
+//	}
+//	if errs != nil {
+//	  return errs
+//	}
+package erkwarning
+
+import (
+	"errors"
+
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+)
+
+// Severity of an error kind.
+// Higher severities are more severe.
+type Severity int
+
+// Severities, in order from least to most severe.
+const (
+	SeverityDebug Severity = iota + 1
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+// Severitier kinds declare their severity.
+//
+// To include the severity when exporting the error, the kind should also implement SeverityStringFor(erk.Kind) string.
+// The severity kinds in this package implement both methods.
+type Severitier interface {
+	SeverityFor(kind erk.Kind) Severity
+}
+
+// String returns the lowercase name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "debug"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+type (
+	// DebugKind can be embedded in error kinds with debug severity.
+	DebugKind struct{}
+
+	// InfoKind can be embedded in error kinds with info severity.
+	InfoKind struct{}
+
+	// WarningKind can be embedded in error kinds with warning severity.
+	WarningKind struct{}
+
+	// ErrorKind can be embedded in error kinds with error severity.
+	// This is the default for kinds without a declared severity.
+	ErrorKind struct{}
+
+	// CriticalKind can be embedded in error kinds with critical severity.
+	CriticalKind struct{}
+)
+
+// The severity kinds implement Severitier.
+var (
+	_ Severitier = DebugKind{}
+	_ Severitier = InfoKind{}
+	_ Severitier = WarningKind{}
+	_ Severitier = ErrorKind{}
+	_ Severitier = CriticalKind{}
+)
+
+// SeverityFor the provided kind.
+func (DebugKind) SeverityFor(erk.Kind) Severity { return SeverityDebug }
+
+// SeverityStringFor the provided kind.
+func (DebugKind) SeverityStringFor(erk.Kind) string { return SeverityDebug.String() }
+
+// IsWarning returns true, since the severity is lower than SeverityError.
+func (DebugKind) IsWarning() bool { return true }
+
+// SeverityFor the provided kind.
+func (InfoKind) SeverityFor(erk.Kind) Severity { return SeverityInfo }
+
+// SeverityStringFor the provided kind.
+func (InfoKind) SeverityStringFor(erk.Kind) string { return SeverityInfo.String() }
+
+// IsWarning returns true, since the severity is lower than SeverityError.
+func (InfoKind) IsWarning() bool { return true }
+
+// SeverityFor the provided kind.
+func (WarningKind) SeverityFor(erk.Kind) Severity { return SeverityWarning }
+
+// SeverityStringFor the provided kind.
+func (WarningKind) SeverityStringFor(erk.Kind) string { return SeverityWarning.String() }
+
+// IsWarning returns true, since the kind is a warning.
+func (WarningKind) IsWarning() bool { return true }
+
+// SeverityFor the provided kind.
+func (ErrorKind) SeverityFor(erk.Kind) Severity { return SeverityError }
+
+// SeverityStringFor the provided kind.
+func (ErrorKind) SeverityStringFor(erk.Kind) string { return SeverityError.String() }
+
+// SeverityFor the provided kind.
+func (CriticalKind) SeverityFor(erk.Kind) Severity { return SeverityCritical }
+
+// SeverityStringFor the provided kind.
+func (CriticalKind) SeverityStringFor(erk.Kind) string { return SeverityCritical.String() }
+
+// KindSeverity returns the severity declared by the kind.
+// If the kind does not implement Severitier, SeverityError is returned.
+func KindSeverity(kind erk.Kind) Severity {
+	if severitier, ok := kind.(Severitier); ok {
+		return severitier.SeverityFor(kind)
+	}
+
+	return SeverityError
+}
+
+// MaxSeverity returns the highest severity in the error tree.
+//
+// For error groups, this is the highest severity of the errors in the group.
+// The group header is only considered if its kind implements Severitier.
+// For other errors, this is the severity of the error's kind.
+//
+// If err is nil, 0 is returned.
+func MaxSeverity(err error) Severity {
+	if err == nil {
+		return 0
+	}
+
+	var g erg.Groupable
+	if !errors.As(err, &g) {
+		return KindSeverity(erk.GetKind(err))
+	}
+
+	maxSeverity := Severity(0)
+	if severitier, ok := erk.GetKind(g.Header()).(Severitier); ok {
+		maxSeverity = severitier.SeverityFor(erk.GetKind(g.Header()))
+	}
+
+	for _, groupErr := range g.Errors() {
+		if severity := MaxSeverity(groupErr); severity > maxSeverity {
+			maxSeverity = severity
+		}
+	}
+
+	if maxSeverity == 0 {
+		return SeverityError
+	}
+
+	return maxSeverity
+}
+
+// IsWarning reports if the error tree has a severity lower than SeverityError.
+// If err is nil, false is returned.
+func IsWarning(err error) bool {
+	return err != nil && MaxSeverity(err) < SeverityError
+}
+
+// Split an error into errors that should be returned, and warnings that should be logged.
+//
+// Errors in a group are split into two groups with the same header.
+// Otherwise, the error is returned as errs if its severity is at least SeverityError, or as warnings if it is lower.
+// Errors wrapping a group are not split, so the kind and params of the wrapping error are kept.
+//
+// Empty groups are returned as nil, so each result can be directly checked against nil.
+func Split(err error) (errs error, warnings error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	g, ok := err.(*erg.Group)
+	if !ok {
+		if IsWarning(err) {
+			return nil, err
+		}
+
+		return err, nil
+	}
+
+	warnings, errs = g.Partition(IsWarning)
+	return nilIfEmpty(errs), nilIfEmpty(warnings)
+}
+
+func nilIfEmpty(groupErr error) error {
+	if !erg.Any(groupErr) {
+		return nil
+	}
+
+	return groupErr
+}
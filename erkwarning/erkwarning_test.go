@@ -0,0 +1,259 @@
+package erkwarning_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
+	"github.com/JosiahWitt/erk/erkwarning"
+)
+
+type (
+	ErkDefault struct{ erk.DefaultKind }
+	ErkDebug   struct {
+		erk.DefaultKind
+		erkwarning.DebugKind
+	}
+	ErkInfo struct {
+		erk.DefaultKind
+		erkwarning.InfoKind
+	}
+	ErkWarning struct {
+		erk.DefaultKind
+		erkwarning.WarningKind
+	}
+	ErkError struct {
+		erk.DefaultKind
+		erkwarning.ErrorKind
+	}
+	ErkCritical struct {
+		erk.DefaultKind
+		erkwarning.CriticalKind
+	}
+	ErkPtrWarn struct {
+		erk.DefaultPtrKind
+		erkwarning.WarningKind
+	}
+)
+
+var (
+	errDefault  = erk.New(ErkDefault{}, "default")
+	errDebug    = erk.New(ErkDebug{}, "debug")
+	errInfo     = erk.New(ErkInfo{}, "info")
+	errWarning  = erk.New(ErkWarning{}, "warning")
+	errError    = erk.New(ErkError{}, "error")
+	errCritical = erk.New(ErkCritical{}, "critical")
+)
+
+func TestSeverityString(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name     string
+		Severity erkwarning.Severity
+		Expected string
+	}{
+		{Name: "debug", Severity: erkwarning.SeverityDebug, Expected: "debug"},
+		{Name: "info", Severity: erkwarning.SeverityInfo, Expected: "info"},
+		{Name: "warning", Severity: erkwarning.SeverityWarning, Expected: "warning"},
+		{Name: "error", Severity: erkwarning.SeverityError, Expected: "error"},
+		{Name: "critical", Severity: erkwarning.SeverityCritical, Expected: "critical"},
+		{Name: "unknown", Severity: 0, Expected: "unknown"},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+		ensure(entry.Severity.String()).Equals(entry.Expected)
+	})
+}
+
+func TestKindSeverity(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name     string
+		Kind     erk.Kind
+		Expected erkwarning.Severity
+	}{
+		{Name: "without severity", Kind: ErkDefault{}, Expected: erkwarning.SeverityError},
+		{Name: "with nil kind", Kind: nil, Expected: erkwarning.SeverityError},
+		{Name: "debug", Kind: ErkDebug{}, Expected: erkwarning.SeverityDebug},
+		{Name: "info", Kind: ErkInfo{}, Expected: erkwarning.SeverityInfo},
+		{Name: "warning", Kind: ErkWarning{}, Expected: erkwarning.SeverityWarning},
+		{Name: "error", Kind: ErkError{}, Expected: erkwarning.SeverityError},
+		{Name: "critical", Kind: ErkCritical{}, Expected: erkwarning.SeverityCritical},
+		{Name: "pointer kind", Kind: &ErkPtrWarn{}, Expected: erkwarning.SeverityWarning},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+		ensure(erkwarning.KindSeverity(entry.Kind)).Equals(entry.Expected)
+	})
+}
+
+func TestMaxSeverity(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name     string
+		Err      error
+		Expected erkwarning.Severity
+	}{
+		{Name: "nil error", Err: nil, Expected: 0},
+		{Name: "non erk error", Err: errors.New("plain"), Expected: erkwarning.SeverityError},
+		{Name: "erk error", Err: errInfo, Expected: erkwarning.SeverityInfo},
+		{Name: "erk error wrapping a more severe error", Err: erk.WrapAs(errWarning, errCritical), Expected: erkwarning.SeverityWarning},
+		{
+			Name:     "group with default header and warnings",
+			Err:      erg.NewAs(errDefault, errDebug, errWarning, errInfo),
+			Expected: erkwarning.SeverityWarning,
+		},
+		{
+			Name:     "group with warning header and errors",
+			Err:      erg.NewAs(errWarning, errDebug, errError),
+			Expected: erkwarning.SeverityError,
+		},
+		{
+			Name:     "group with critical header and warnings",
+			Err:      erg.NewAs(errCritical, errDebug),
+			Expected: erkwarning.SeverityCritical,
+		},
+		{
+			Name:     "nested groups",
+			Err:      erg.NewAs(errDefault, errDebug, erg.NewAs(errDefault, errInfo, errCritical)),
+			Expected: erkwarning.SeverityCritical,
+		},
+		{
+			Name:     "empty group with default header",
+			Err:      erg.NewAs(errDefault),
+			Expected: erkwarning.SeverityError,
+		},
+		{
+			Name:     "empty group with info header",
+			Err:      erg.NewAs(errInfo),
+			Expected: erkwarning.SeverityInfo,
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+		ensure(erkwarning.MaxSeverity(entry.Err)).Equals(entry.Expected)
+	})
+}
+
+func TestIsWarning(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure(erkwarning.IsWarning(nil)).IsFalse()
+	ensure(erkwarning.IsWarning(errors.New("plain"))).IsFalse()
+	ensure(erkwarning.IsWarning(errError)).IsFalse()
+	ensure(erkwarning.IsWarning(errWarning)).IsTrue()
+	ensure(erkwarning.IsWarning(erg.NewAs(errDefault, errDebug, errWarning))).IsTrue()
+	ensure(erkwarning.IsWarning(erg.NewAs(errDefault, errDebug, errCritical))).IsFalse()
+}
+
+func TestIsWarningMethod(t *testing.T) {
+	ensure := ensure.New(t)
+
+	type warninger interface{ IsWarning() bool }
+
+	table := []struct {
+		Name      string
+		Kind      erk.Kind
+		IsWarning bool
+	}{
+		{Name: "debug", Kind: ErkDebug{}, IsWarning: true},
+		{Name: "info", Kind: ErkInfo{}, IsWarning: true},
+		{Name: "warning", Kind: ErkWarning{}, IsWarning: true},
+		{Name: "error", Kind: ErkError{}, IsWarning: false},
+		{Name: "critical", Kind: ErkCritical{}, IsWarning: false},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		w, ok := entry.Kind.(warninger)
+		ensure(ok && w.IsWarning()).Equals(entry.IsWarning)
+	})
+}
+
+func TestSplit(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with nil error", func(ensure ensurepkg.Ensure) {
+		errs, warnings := erkwarning.Split(nil)
+		ensure(errs).IsNil()
+		ensure(warnings).IsNil()
+	})
+
+	ensure.Run("with non group error", func(ensure ensurepkg.Ensure) {
+		errs, warnings := erkwarning.Split(errError)
+		ensure(errs).Equals(errError)
+		ensure(warnings).IsNil()
+	})
+
+	ensure.Run("with non group warning", func(ensure ensurepkg.Ensure) {
+		errs, warnings := erkwarning.Split(errWarning)
+		ensure(errs).IsNil()
+		ensure(warnings).Equals(errWarning)
+	})
+
+	ensure.Run("with mixed group", func(ensure ensurepkg.Ensure) {
+		nestedWarnings := erg.NewAs(errDefault, errInfo)
+		groupErr := erg.NewAs(errDefault, errWarning, errError, errors.New("plain"), nestedWarnings, errDebug)
+
+		errs, warnings := erkwarning.Split(groupErr)
+		ensure(erg.GetErrors(errs)).Equals([]error{errError, errors.New("plain")})
+		ensure(warnings.Error()).Equals("default:\n - warning\n - default:\n   - info\n - debug")
+		ensure(erk.GetKind(errs)).Equals(ErkDefault{})
+		ensure(erk.GetKind(warnings)).Equals(ErkDefault{})
+	})
+
+	ensure.Run("with only warnings", func(ensure ensurepkg.Ensure) {
+		groupErr := erg.NewAs(errDefault, errWarning, errInfo)
+
+		errs, warnings := erkwarning.Split(groupErr)
+		ensure(errs).IsNil()
+		ensure(erg.GetErrors(warnings)).Equals([]error{errWarning, errInfo})
+	})
+
+	ensure.Run("with error wrapping a mixed group", func(ensure ensurepkg.Ensure) {
+		wrapped := erk.WithParam(erk.WrapAs(errDefault, erg.NewAs(errDefault, errWarning, errError)), "a", "b")
+
+		errs, warnings := erkwarning.Split(wrapped)
+		ensure(errs).Equals(wrapped)
+		ensure(warnings).IsNil()
+		ensure(erk.GetKind(errs)).Equals(ErkDefault{})
+		ensure(erk.GetParams(errs)["a"]).Equals("b")
+	})
+
+	ensure.Run("with only errors", func(ensure ensurepkg.Ensure) {
+		groupErr := erg.NewAs(errDefault, errError)
+
+		errs, warnings := erkwarning.Split(groupErr)
+		ensure(erg.GetErrors(errs)).Equals([]error{errError})
+		ensure(warnings).IsNil()
+	})
+}
+
+func TestExportSeverity(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with severity", func(ensure ensurepkg.Ensure) {
+		exported := erk.Export(errWarning).(*erk.ExportedError)
+		ensure(exported.Severity).Equals("warning")
+
+		bytes, err := json.Marshal(errWarning)
+		ensure(err).IsNotError()
+		ensure(string(bytes)).Equals(`{"kind":"github.com/JosiahWitt/erk/erkwarning_test:ErkWarning","message":"warning","severity":"warning"}`)
+	})
+
+	ensure.Run("without severity", func(ensure ensurepkg.Ensure) {
+		exported := erk.Export(errDefault).(*erk.ExportedError)
+		ensure(exported.Severity).IsEmpty()
+	})
+}
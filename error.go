@@ -6,24 +6,27 @@ import (
 	"errors"
 	"fmt"
 	"text/template"
-
-	"github.com/JosiahWitt/erk/erkstrict"
 )
 
 // Error satisfies the Erkable interface.
 var (
 	_ Erkable         = &Error{}
 	_ ErrorIndentable = &Error{}
+	_ Metadatable     = &Error{}
 )
 
 // Error stores details about an error with kinds and a message template.
 type Error struct {
-	kind    Kind
-	message string
-	params  Params
+	kind     Kind
+	message  string
+	params   paramLayers
+	metadata Metadata
 
 	// Set when using ToErk to build a non-erk error
 	builtFromRegularError error
+
+	// Set when using NewLiteral, since the message is not a template
+	literal bool
 }
 
 // New creates an error with a kind and message.
@@ -36,14 +39,37 @@ func NewWith(kind Kind, message string, params Params) error {
 	e := &Error{
 		kind:    kind,
 		message: message,
-		params:  params,
+		params:  newParamLayers(params), // Copied, so changes to the params do not change the error
 	}
 
 	// If strict mode, ensure we can parse the template
-	if erkstrict.IsStrictMode() {
-		e.parseTemplate() //nolint:errcheck // Panics if there is an error
+	if e.isStrictMode() {
+		if messageFormat(kind) == MessageFormatPlaceholder {
+			e.parsePlaceholders() //nolint:errcheck // Reported if there is an error
+		} else {
+			e.parseTemplate(true) //nolint:errcheck // Reported if there is an error
+		}
+	}
+
+	e.checkStrictParams(params)
+
+	return e
+}
+
+// NewLiteral creates an error with a kind, a message that is not a template, and params.
+// The message is rendered unchanged, so the params do not need to be referenced by it.
+//
+// This is useful for messages that were already rendered, such as the message of an imported error.
+func NewLiteral(kind Kind, message string, params Params) error {
+	e := &Error{
+		kind:    kind,
+		message: message,
+		params:  newParamLayers(params),
+		literal: true,
 	}
 
+	e.checkStrictParams(params)
+
 	return e
 }
 
@@ -57,31 +83,49 @@ func (e *Error) Error() string {
 // The indentLevel represents the indentation of wrapped errors.
 // Thus, it should start with "  ".
 func (e *Error) IndentError(indentLevel string) string {
-	t, err := e.parseTemplate()
+	if e.literal {
+		return e.message
+	}
+
+	if messageFormat(e.kind) == MessageFormatPlaceholder {
+		return e.indentPlaceholderError(indentLevel)
+	}
+
+	isStrictMode := e.isStrictMode()
+	t, err := e.parseTemplate(isStrictMode)
 	if err != nil {
 		return e.message
 	}
 
-	if erkstrict.IsStrictMode() {
+	if isStrictMode {
 		t.Option("missingkey=error")
 	}
 
 	var filledMessage bytes.Buffer
-	err = t.Execute(&filledMessage, e.params.prep(indentLevel))
+	err = t.Execute(&filledMessage, e.params.flatten().prep(indentLevel))
 	if err != nil {
-		if erkstrict.IsStrictMode() {
-			panic(buildStrictPanicMessage(
-				fmt.Sprintf(
-					"Unable to execute error template:\n\tKind: %s\n\tTemplate: %s\n\tParams: %+v\n\tError: %v",
-					GetKindString(e),
-					e.message,
-					e.params,
-					err,
-				),
-			))
+		if !isStrictMode {
+			return e.message
 		}
 
-		return e.message
+		e.reportStrictViolation("", fmt.Sprintf(
+			"Unable to execute error template:\n\tKind: %s\n\tTemplate: %s\n\tParams: %+v\n\tError: %v",
+			GetKindString(e),
+			e.message,
+			e.params.flatten(),
+			err,
+		))
+
+		// The violation was only reported as a warning, so render as if strict mode was off
+		t, err = e.parseTemplate(false)
+		if err != nil {
+			return e.message
+		}
+
+		filledMessage.Reset()
+		if err := t.Execute(&filledMessage, e.params.flatten().prep(indentLevel)); err != nil {
+			return e.message
+		}
 	}
 
 	return filledMessage.String()
@@ -90,8 +134,8 @@ func (e *Error) IndentError(indentLevel string) string {
 // Is implements the Go 1.13+ Is interface for use with errors.Is.
 func (e *Error) Is(err error) bool {
 	// Allows validating the error when comparing errors during testing
-	if erkstrict.IsStrictMode() {
-		e.Error() //nolint:govet // Panics if there is an error
+	if e.isStrictMode() {
+		_ = e.Error() // Reports if there is an error
 	}
 
 	var e2 *Error
@@ -104,7 +148,7 @@ func (e *Error) Is(err error) bool {
 
 // Unwrap implements the Go 1.13+ Unwrap interface for use with errors.Unwrap.
 func (e *Error) Unwrap() error {
-	possibleError, ok := e.params[OriginalErrorParam]
+	possibleError, ok := e.params.get(OriginalErrorParam)
 	if ok {
 		originalError, ok := possibleError.(error)
 		if ok {
@@ -128,21 +172,40 @@ func (e *Error) WithParams(params Params) error {
 		return e
 	}
 
+	return e.withParamLayer(params.Clone())
+}
+
+// withParamLayer adds the params to a copy of the Error as a new layer.
+// The params are stored without being copied, so they must not be modified afterwards.
+func (e *Error) withParamLayer(params Params) *Error {
 	e2 := e.clone()
-	for key, value := range params {
-		if value == nil {
-			delete(e2.params, key)
-		} else {
-			e2.params[key] = value
-		}
-	}
+	e2.params = e.params.with(params) // Only stores the changes, since the layers are shared with the original
 
+	e2.checkStrictParams(params)
 	return e2
 }
 
 // Params returns a copy of the Error's Params.
 func (e *Error) Params() Params {
-	return e.params.Clone()
+	return e.params.flatten().Clone()
+}
+
+// WithMetadata adds metadata to a copy of the Error.
+//
+// A nil metadata value deletes the metadata key.
+func (e *Error) WithMetadata(metadata Metadata) error {
+	if len(metadata) == 0 {
+		return e
+	}
+
+	e2 := e.clone()
+	e2.metadata = e.metadata.merge(metadata)
+	return e2
+}
+
+// Metadata returns a copy of the Error's Metadata.
+func (e *Error) Metadata() Metadata {
+	return e.metadata.Clone()
 }
 
 // ExportRawMessage without executing the template.
@@ -153,9 +216,7 @@ func (e *Error) ExportRawMessage() string {
 // Export creates a visible copy of the Error that can be used outside the erk package.
 // A common use case is marshalling the error to JSON.
 func (e *Error) Export() ExportedErkable {
-	exported := e.buildExportedError()
-	exported.ErrorStack = e.buildErrorStack()
-	return exported
+	return e.ExportLocalized("")
 }
 
 // MarshalJSON by exporting the error and then marshalling.
@@ -165,27 +226,28 @@ func (e *Error) MarshalJSON() ([]byte, error) {
 
 func (e *Error) clone() *Error {
 	return &Error{
-		kind:    e.kind,
-		message: e.message,
-		params:  e.Params(),
+		kind:     e.kind,
+		message:  e.message,
+		params:   e.params,
+		metadata: e.metadata,
+		literal:  e.literal,
 	}
 }
 
-func (e *Error) parseTemplate() (*template.Template, error) {
-	t, err := template.New("").Funcs(templateFuncs(e.kind)).Parse(e.message)
+// parseTemplate with the template functions for the strict mode, reporting parse errors in strict mode.
+func (e *Error) parseTemplate(isStrictMode bool) (*template.Template, error) {
+	t, err := template.New("").Funcs(templateFuncs(e.kind, isStrictMode)).Parse(e.message)
 	if err != nil {
-		if erkstrict.IsStrictMode() {
-			panic(buildStrictPanicMessage(
-				fmt.Sprintf(
-					"Unable to parse error template:\n\tKind: %s\n\tTemplate: %s\n\tError: %v",
-					GetKindString(e),
-					e.message,
-					err,
-				),
+		if isStrictMode {
+			e.reportStrictViolation("", fmt.Sprintf(
+				"Unable to parse error template:\n\tKind: %s\n\tTemplate: %s\n\tError: %v",
+				GetKindString(e),
+				e.message,
+				err,
 			))
 		}
 
-		return nil, err //nolint:wrapcheck // Error only used in panic
+		return nil, err //nolint:wrapcheck // Error only used in strict mode violations
 	}
 
 	return t, nil
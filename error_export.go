@@ -10,6 +10,17 @@ type ExportedError struct {
 	Message string  `json:"message"`
 	Params  Params  `json:"params,omitempty"`
 
+	// Metadata attached using WithContext or WithMetadata.
+	Metadata Metadata `json:"metadata,omitempty"`
+
+	// Severity is set if the kind implements SeverityStringFor(Kind) string.
+	// See the erkwarning package.
+	Severity string `json:"severity,omitempty"`
+
+	// Code is set if the kind has a numeric code.
+	// See RegisterNumericCode.
+	Code *int `json:"code,omitempty"`
+
 	ErrorStack []ExportedErkable `json:"errorStack,omitempty"`
 }
 
@@ -34,7 +45,7 @@ func (e *ExportedError) ErrorParams() Params {
 	return e.Params
 }
 
-func (e *Error) buildExportedError() *ExportedError {
+func (e *Error) buildExportedError(locale string) *ExportedError {
 	// Remove the original error from the params, since it's in the error stack
 	params := GetParams(e)
 	delete(params, OriginalErrorParam)
@@ -42,12 +53,40 @@ func (e *Error) buildExportedError() *ExportedError {
 	return &ExportedError{
 		Kind:       e.buildExportedKind(),
 		Type:       e.buildExportedErrorType(),
-		Message:    e.Error(),
+		Message:    e.localizedMessage(locale),
 		Params:     params,
+		Metadata:   e.buildExportedMetadata(),
+		Severity:   e.buildExportedSeverity(),
+		Code:       e.buildExportedCode(),
 		ErrorStack: nil, // This is only set at the root level by e.Export()
 	}
 }
 
+func (e *Error) buildExportedMetadata() Metadata {
+	if len(e.metadata) == 0 {
+		return nil
+	}
+
+	return e.Metadata()
+}
+
+func (e *Error) buildExportedSeverity() string {
+	if severity, ok := e.kind.(interface{ SeverityStringFor(Kind) string }); ok {
+		return severity.SeverityStringFor(e.kind)
+	}
+
+	return ""
+}
+
+func (e *Error) buildExportedCode() *int {
+	code, ok := NumericCodeFor(e.kind)
+	if !ok {
+		return nil
+	}
+
+	return &code
+}
+
 func (e *Error) buildExportedKind() *string {
 	if e.kind == nil {
 		return nil
@@ -66,7 +105,7 @@ func (e *Error) buildExportedErrorType() *string {
 	return &typeStr
 }
 
-func (e *Error) buildErrorStack() []ExportedErkable {
+func (e *Error) buildErrorStack(locale string) []ExportedErkable {
 	errs := []ExportedErkable{}
 
 	for currentErr := errors.Unwrap(e); currentErr != nil; currentErr = errors.Unwrap(currentErr) {
@@ -76,19 +115,19 @@ func (e *Error) buildErrorStack() []ExportedErkable {
 			continue
 		}
 
-		exportedErkErr := buildErrorStackEntry(currentErr)
+		exportedErkErr := buildErrorStackEntry(currentErr, locale)
 		errs = append(errs, exportedErkErr)
 	}
 
 	return errs
 }
 
-func buildErrorStackEntry(currentErr error) ExportedErkable {
+func buildErrorStackEntry(currentErr error, locale string) ExportedErkable {
 	currentErkableErr := ToErk(currentErr)
 	currentErkErr, ok := currentErkableErr.(*Error)
 	if !ok {
-		return currentErkableErr.Export()
+		return ExportLocalized(currentErkableErr, locale)
 	}
 
-	return currentErkErr.buildExportedError()
+	return currentErkErr.buildExportedError(locale)
 }
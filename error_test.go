@@ -4,12 +4,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/JosiahWitt/ensure"
 	"github.com/JosiahWitt/ensure/ensurepkg"
 	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erg"
 	"github.com/JosiahWitt/erk/erkstrict"
 )
 
@@ -34,6 +38,27 @@ func TestNewWith(t *testing.T) {
 	testNew(t, erk.NewWith)
 }
 
+func TestNewLiteral(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("renders the message unchanged", func(ensure ensurepkg.Ensure) {
+		withStrictMode(true, func() {
+			err := erk.NewLiteral(ErkExample{}, "my {{.a}} message: {{}}", erk.Params{"b": "world"})
+			ensure(err.Error()).Equals("my {{.a}} message: {{}}")
+			ensure(erk.GetParams(err)).Equals(erk.Params{"b": "world"})
+			ensure(erk.GetKind(err)).Equals(ErkExample{})
+			ensure(erk.RenderLocalized(err, "es")).Equals("my {{.a}} message: {{}}")
+		})
+	})
+
+	ensure.Run("when adding params", func(ensure ensurepkg.Ensure) {
+		err := erk.NewLiteral(ErkExample{}, "my {{.a}} message", nil)
+		err = erk.WithParam(err, "a", "hello")
+		ensure(err.Error()).Equals("my {{.a}} message")
+		ensure(erk.GetParams(err)).Equals(erk.Params{"a": "hello"})
+	})
+}
+
 func testNew(t *testing.T, create func(kind erk.Kind, message string, params erk.Params) error) {
 	t.Helper()
 	ensure := ensure.New(t)
@@ -63,7 +88,7 @@ func testNew(t *testing.T, create func(kind erk.Kind, message string, params erk
 			ensure.Run("invalid template", func(ensure ensurepkg.Ensure) {
 				defer func() {
 					if res := recover(); res != nil {
-						str, ok := res.(string)
+						str, ok := strictViolationMessage(res)
 						ensure(ok).IsTrue()
 
 						isValid := regexp.MustCompile(templateInvalidRegexp).MatchString(str)
@@ -172,6 +197,69 @@ func TestError(t *testing.T) {
 	})
 }
 
+func TestErrorImmutable(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("when adding params to a copy", func(ensure ensurepkg.Ensure) {
+		err := erk.NewWith(ErkExample{}, "my {{.a}} message", erk.Params{"a": "x"})
+		ensure(err.Error()).Equals("my x message")
+
+		err2 := erk.WithParam(err, "a", "y")
+		ensure(err2.Error()).Equals("my y message")
+		ensure(err.Error()).Equals("my x message")
+	})
+
+	ensure.Run("when modifying the params after creating the error", func(ensure ensurepkg.Ensure) {
+		params := erk.Params{"a": "x"}
+		err := erk.NewWith(ErkExample{}, "my {{.a}} message", params)
+		ensure(err.Error()).Equals("my x message")
+
+		params["a"] = "y"
+		ensure(err.Error()).Equals("my x message")
+	})
+
+	ensure.Run("when rendering with different indent levels", func(ensure ensurepkg.Ensure) {
+		err := erk.WrapAs(erk.New(ErkExample{}, "outer:{{.err}}"), errors.New("inner\nx"))
+
+		indentable := err.(erk.ErrorIndentable) //nolint:forcetypeassert // Always an erk error
+		ensure(indentable.IndentError("  ")).Equals("outer:\n  inner\n  x")
+		ensure(indentable.IndentError("    ")).Equals("outer:\n    inner\n    x")
+		ensure(indentable.IndentError("  ")).Equals("outer:\n  inner\n  x")
+	})
+
+	ensure.Run("when comparing equal errors after rendering", func(ensure ensurepkg.Ensure) {
+		err1 := erk.NewWith(ErkExample{}, "my {{.a}} message", erk.Params{"a": "x"})
+		err2 := erk.NewWith(ErkExample{}, "my {{.a}} message", erk.Params{"a": "x"})
+		ensure(err1.Error()).Equals("my x message")
+
+		ensure(reflect.DeepEqual(err1, err2)).IsTrue()
+		ensure(err1).Equals(err2)
+	})
+
+	ensure.Run("when rendering concurrently", func(ensure ensurepkg.Ensure) {
+		err := erk.NewWith(ErkExample{}, "my {{.a}} message", erk.Params{"a": "x"})
+		indentable := err.(erk.ErrorIndentable) //nolint:forcetypeassert // Always an erk error
+
+		const goroutines = 20
+		results := make([]string, goroutines)
+
+		var wg sync.WaitGroup
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+				results[i] = indentable.IndentError(strings.Repeat(" ", i%3))
+			}(i)
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			ensure(result).Equals("my x message")
+		}
+	})
+}
+
 func TestErrorStrictMode(t *testing.T) {
 	ensure := ensure.New(t)
 
@@ -201,7 +289,7 @@ func TestErrorStrictMode(t *testing.T) {
 		ensure.Run("with invalid template", func(ensure ensurepkg.Ensure) {
 			defer func() {
 				if res := recover(); res != nil {
-					str, ok := res.(string)
+					str, ok := strictViolationMessage(res)
 					ensure(ok).IsTrue()
 
 					isValid := regexp.MustCompile(templateInvalidRegexp).MatchString(str)
@@ -212,14 +300,14 @@ func TestErrorStrictMode(t *testing.T) {
 			msg := "my message {{}}}"
 			err := erk.New(ErkExample{}, msg)
 
-			withStrictMode(true, func() { err.Error() }) //nolint:govet // Used to trigger panic
+			withStrictMode(true, func() { _ = err.Error() }) // Used to trigger panic
 			ensure.Failf("Expected panic, so this line should not be reached")
 		})
 
 		ensure.Run("with invalid param", func(ensure ensurepkg.Ensure) {
 			defer func() {
 				if res := recover(); res != nil {
-					str, ok := res.(string)
+					str, ok := strictViolationMessage(res)
 					ensure(ok).IsTrue()
 
 					isValid := regexp.MustCompile(templateInvalidParamErrorRegexp).MatchString(str)
@@ -230,14 +318,14 @@ func TestErrorStrictMode(t *testing.T) {
 			msg := "my message {{call .a}}"
 			err := erk.New(ErkExample{}, msg)
 			err = erk.WithParam(err, "a", func() { panic("just testing") })
-			withStrictMode(true, func() { err.Error() }) //nolint:govet // Used to trigger panic
+			withStrictMode(true, func() { _ = err.Error() }) // Used to trigger panic
 			ensure.Failf("Expected panic, so this line should not be reached")
 		})
 
 		ensure.Run("with missing params", func(ensure ensurepkg.Ensure) {
 			defer func() {
 				if res := recover(); res != nil {
-					str, ok := res.(string)
+					str, ok := strictViolationMessage(res)
 					ensure(ok).IsTrue()
 
 					isValid := regexp.MustCompile(templateMissingParamErrorRegexp).MatchString(str)
@@ -248,7 +336,7 @@ func TestErrorStrictMode(t *testing.T) {
 			msg := "my message: {{.a}}, {{.b}}!"
 			err := erk.New(ErkExample{}, msg)
 			err = erk.WithParam(err, "a", "hello")
-			withStrictMode(true, func() { err.Error() }) //nolint:govet // Used to trigger panic
+			withStrictMode(true, func() { _ = err.Error() }) // Used to trigger panic
 			ensure.Failf("Expected panic, so this line should not be reached")
 		})
 	})
@@ -655,6 +743,16 @@ func withStrictMode(enabled bool, fn func()) {
 	fn()
 }
 
+// strictViolationMessage returns the message of a recovered strict mode panic.
+func strictViolationMessage(res interface{}) (string, bool) {
+	violationErr, ok := res.(*erkstrict.ViolationError)
+	if !ok {
+		return "", false
+	}
+
+	return violationErr.Error(), true
+}
+
 type KindWithFieldWithNoClone struct {
 	Field string
 }
@@ -711,3 +809,42 @@ const (
 		"\\tError:.+missing value for command\\n\\n" +
 		disclosureRegexp
 )
+
+func BenchmarkError(b *testing.B) {
+	withStrictMode(false, func() {
+		inner := erk.NewWith(ErkExample{}, "item {{.key}} was not found in {{.table}}", erk.Params{"key": "abc", "table": "items"})
+		err := erk.WrapWith(erk.New(ErkExample{}, "failed to load {{.count}} items: {{.err}}"), inner, erk.Params{"count": 10})
+
+		b.Run("render", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = err.Error()
+			}
+		})
+
+		b.Run("export", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = erk.Export(err)
+			}
+		})
+
+		b.Run("marshal JSON", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := json.Marshal(err); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run("group render", func(b *testing.B) {
+			group := erg.New(ErkExample{}, "multiple errors", err, inner, errors.New("regular error"))
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = group.Error()
+			}
+		})
+	})
+}
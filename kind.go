@@ -68,7 +68,8 @@ func GetKind(err error) Kind {
 
 // GetKindString returns a string identifying the kind of the error.
 //
-// If the kind embeds erk.DefaultKind, this will be a string with the package and type of the error's kind.
+// If the kind embeds erk.DefaultKind, this will be a string with the package and type of the error's kind,
+// unless the kind has a stable code (see RegisterKindCode, KindCoder, and KindCodeTag).
 // This string can be overridden by implementing a KindStringFor method on a base kind, and embedding that in the error kind.
 //
 // erk.DefaultKind Example:
@@ -92,7 +93,14 @@ func cloneKind(kind Kind) Kind {
 }
 
 // KindStringFor the provided kind.
+//
+// If the kind has a stable code (see RegisterKindCode, KindCoder, and KindCodeTag), the code is returned.
+// Otherwise, a string with the package and type of the kind is returned.
 func (DefaultKind) KindStringFor(kind Kind) string {
+	if code := kindCode(kind); code != "" {
+		return code
+	}
+
 	return buildDefaultKindString(kind)
 }
 
@@ -106,6 +114,12 @@ func (DefaultKind) TemplateFuncsFor(Kind) template.FuncMap {
 	return funcMap
 }
 
+// MessageFormatFor the provided kind.
+// Override it to use MessageFormatPlaceholder.
+func (DefaultKind) MessageFormatFor(Kind) MessageFormat {
+	return MessageFormatTemplate
+}
+
 // CloneKind to a shallow copy.
 //
 // If the kind is not a pointer, it is directly returned (since it was passed by value).
@@ -136,6 +150,11 @@ func (*DefaultPtrKind) TemplateFuncsFor(kind Kind) template.FuncMap {
 	return DefaultKind{}.TemplateFuncsFor(kind)
 }
 
+// MessageFormatFor the provided kind.
+func (*DefaultPtrKind) MessageFormatFor(kind Kind) MessageFormat {
+	return DefaultKind{}.MessageFormatFor(kind)
+}
+
 // CloneKind to a shallow copy.
 func (*DefaultPtrKind) CloneKind(kind Kind) Kind {
 	return DefaultKind{}.CloneKind(kind)
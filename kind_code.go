@@ -0,0 +1,197 @@
+package erk
+
+import (
+	"reflect"
+	"sync"
+)
+
+// KindCodeTag is the struct tag key used to declare a kind's stable code.
+//
+// The tag must be on a field declared directly in the kind's struct, usually the embedded default kind.
+// Tags on fields of embedded structs are ignored, so a code declared on a shared default kind is not inherited by every kind.
+//
+// Example:
+//
+//	type ErkNotFound struct {
+//	  erk.DefaultKind `erk:"store:not_found"`
+//	}
+const KindCodeTag = "erk"
+
+// KindCoder kinds declare a stable code, which is used as the kind string instead of the package and type of the kind.
+// This allows moving or renaming the kind without changing the string clients see.
+//
+// The method should be declared directly on the kind, not on a shared default kind, since methods are promoted to every kind embedding it.
+type KindCoder interface {
+	KindCode() string
+}
+
+// ErkKindRegistry is the kind of errors returned when registering kinds.
+type ErkKindRegistry struct{ DefaultKind }
+
+// KindStringFor the provided kind.
+func (ErkKindRegistry) KindStringFor(Kind) string {
+	return "erk:kind_registry"
+}
+
+// Errors returned when registering kinds.
+var (
+	ErrKindCodeEmpty         = New(ErkKindRegistry{}, "kind {{.kind}} cannot be registered with an empty code")
+	ErrKindCodeDuplicate     = New(ErkKindRegistry{}, "kind code {{.code}} of {{.kind}} is already used by {{.existing}}")
+	ErrKindAlreadyRegistered = New(ErkKindRegistry{}, "kind {{.kind}} is already registered with code {{.existing}}, not {{.code}}")
+)
+
+//nolint:gochecknoglobals // Registered once, and read when kind strings are built
+var kindRegistry struct {
+	sync.RWMutex
+	codes map[reflect.Type]string // kind type, without pointers, to code
+	types map[string]reflect.Type // code or alias to registered kind type
+}
+
+// RegisterKind so LookupKind can resolve the kind from its kind string, or any of the aliases.
+// Aliases allow old kind strings to continue resolving after a kind's code or package changes.
+//
+// The kind string is determined by the kind's KindStringFor method, which includes codes declared with KindCodeTag or KindCoder.
+// An error is returned if the kind string or an alias is already used by a different kind.
+// Registering the same kind with the same kind string again adds the aliases.
+func RegisterKind(kind Kind, aliases ...string) error {
+	return registerKind(kind, kind.KindStringFor(kind), false, aliases)
+}
+
+// RegisterKindCode assigns a stable code to the kind, and registers it like RegisterKind.
+// This is useful for kinds that cannot be modified to declare a code, such as kinds from another module.
+//
+// The code is used by DefaultKind.KindStringFor and DefaultPtrKind.KindStringFor, and has priority over codes declared with KindCodeTag or KindCoder.
+// Kinds that override KindStringFor are not affected.
+func RegisterKindCode(kind Kind, code string, aliases ...string) error {
+	if code == "" {
+		return WithParams(ErrKindCodeEmpty, Params{"kind": kindTypeName(kind)})
+	}
+
+	return registerKind(kind, code, true, aliases)
+}
+
+// LookupKind returns a new zero value of the kind registered with the kind string or alias.
+// If the kind was registered as a pointer, a pointer to a new zero value is returned.
+// If no kind is registered with the kind string, nil and false are returned.
+func LookupKind(kindString string) (Kind, bool) {
+	kindRegistry.RLock()
+	kindType, ok := kindRegistry.types[kindString]
+	kindRegistry.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return newKind(kindType), true
+}
+
+// CanonicalKindString returns the current kind string of the kind registered with the kind string or alias.
+// If no kind is registered with the kind string, it is returned unchanged.
+func CanonicalKindString(kindString string) string {
+	kind, ok := LookupKind(kindString)
+	if !ok {
+		return kindString
+	}
+
+	return kind.KindStringFor(kind)
+}
+
+// ClearKindRegistry removes all registered kinds, codes, and aliases.
+// This is mostly useful in tests.
+func ClearKindRegistry() {
+	kindRegistry.Lock()
+	defer kindRegistry.Unlock()
+
+	kindRegistry.codes = nil
+	kindRegistry.types = nil
+}
+
+func registerKind(kind Kind, code string, assignCode bool, aliases []string) error {
+	kindType := reflect.TypeOf(kind)
+	baseType := baseKindType(kindType)
+
+	kindRegistry.Lock()
+	defer kindRegistry.Unlock()
+
+	if existing, ok := kindRegistry.codes[baseType]; ok && assignCode && existing != code {
+		return WithParams(ErrKindAlreadyRegistered, Params{"kind": kindTypeName(kind), "code": code, "existing": existing})
+	}
+
+	// Validate everything before modifying the registry, so a failed registration has no effect
+	for _, c := range append([]string{code}, aliases...) {
+		if existingType, ok := kindRegistry.types[c]; ok && baseKindType(existingType) != baseType {
+			return WithParams(ErrKindCodeDuplicate, Params{"code": c, "kind": kindTypeName(kind), "existing": existingType.String()})
+		}
+	}
+
+	if kindRegistry.types == nil {
+		kindRegistry.codes = map[reflect.Type]string{}
+		kindRegistry.types = map[string]reflect.Type{}
+	}
+
+	if assignCode {
+		kindRegistry.codes[baseType] = code
+	}
+
+	for _, c := range append([]string{code}, aliases...) {
+		kindRegistry.types[c] = kindType
+	}
+
+	return nil
+}
+
+// kindCode returns the stable code of the kind, or an empty string if it does not have one.
+// Registered codes have priority over codes declared with KindCoder, which have priority over codes declared with KindCodeTag.
+func kindCode(kind Kind) string {
+	kindRegistry.RLock()
+	code, ok := kindRegistry.codes[baseKindType(reflect.TypeOf(kind))]
+	kindRegistry.RUnlock()
+
+	if ok {
+		return code
+	}
+
+	if coder, ok := kind.(KindCoder); ok {
+		return coder.KindCode()
+	}
+
+	return kindCodeFromTag(kind)
+}
+
+func kindCodeFromTag(kind Kind) string {
+	t := baseKindType(reflect.TypeOf(kind))
+	if t.Kind() != reflect.Struct {
+		return ""
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if code := t.Field(i).Tag.Get(KindCodeTag); code != "" {
+			return code
+		}
+	}
+
+	return ""
+}
+
+// newKind returns a new zero value of the kind type.
+// If the kind type is a pointer, a pointer to a new zero value is returned.
+func newKind(kindType reflect.Type) Kind {
+	if kindType.Kind() == reflect.Ptr {
+		return reflect.New(kindType.Elem()).Interface().(Kind) //nolint:forcetypeassert // Only kinds are registered
+	}
+
+	return reflect.Zero(kindType).Interface().(Kind) //nolint:forcetypeassert // Only kinds are registered
+}
+
+// baseKindType strips pointers, so a kind and a pointer to the kind share a code.
+func baseKindType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func kindTypeName(kind Kind) string {
+	return reflect.TypeOf(kind).String()
+}
@@ -0,0 +1,164 @@
+package erk_test
+
+import (
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+)
+
+type (
+	ErkTagged struct {
+		erk.DefaultKind `erk:"example:tagged"`
+	}
+
+	ErkTaggedPtr struct {
+		erk.DefaultPtrKind `erk:"example:tagged_ptr"`
+	}
+
+	ErkCoded struct{ erk.DefaultKind }
+
+	// ErkInheritsTag embeds a kind with a tag, which should not be inherited.
+	ErkInheritsTag struct{ ErkTagged }
+)
+
+func (ErkCoded) KindCode() string { return "example:coded" }
+
+func TestKindCodes(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name               string
+		Kind               erk.Kind
+		ExpectedKindString string
+	}{
+		{
+			Name:               "with struct tag",
+			Kind:               ErkTagged{},
+			ExpectedKindString: "example:tagged",
+		},
+		{
+			Name:               "with struct tag on pointer kind",
+			Kind:               &ErkTaggedPtr{},
+			ExpectedKindString: "example:tagged_ptr",
+		},
+		{
+			Name:               "with KindCode method",
+			Kind:               ErkCoded{},
+			ExpectedKindString: "example:coded",
+		},
+		{
+			Name:               "with struct tag on embedded kind",
+			Kind:               ErkInheritsTag{},
+			ExpectedKindString: "github.com/JosiahWitt/erk_test:ErkInheritsTag",
+		},
+		{
+			Name:               "without code",
+			Kind:               ErkExample{},
+			ExpectedKindString: "github.com/JosiahWitt/erk_test:ErkExample",
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		err := erk.New(entry.Kind, "my message")
+		ensure(erk.GetKindString(err)).Equals(entry.ExpectedKindString)
+		ensure(*erk.Export(err).(*erk.ExportedError).Kind).Equals(entry.ExpectedKindString)
+	})
+}
+
+func TestRegisterKindCode(t *testing.T) {
+	ensure := ensure.New(t)
+	defer erk.ClearKindRegistry()
+
+	ensure.Run("uses the code for the kind and pointers to the kind", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+
+		ensure(erk.RegisterKindCode(ErkExample{}, "example:registered", "example:old")).IsNotError()
+		ensure(erk.GetKindString(erk.New(ErkExample{}, "my message"))).Equals("example:registered")
+		ensure(erk.GetKindString(erk.New(&ErkExample{}, "my message"))).Equals("example:registered")
+		ensure(erk.CanonicalKindString("example:old")).Equals("example:registered")
+	})
+
+	ensure.Run("has priority over declared codes", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+
+		ensure(erk.RegisterKindCode(ErkCoded{}, "example:registered")).IsNotError()
+		ensure(erk.GetKindString(erk.New(ErkCoded{}, "my message"))).Equals("example:registered")
+	})
+
+	ensure.Run("does not affect kinds overriding KindStringFor", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+
+		ensure(erk.RegisterKindCode(TestKindStringFor{}, "example:registered")).IsNotError()
+		ensure(erk.GetKindString(erk.New(TestKindStringFor{}, "my message"))).Equals("my_kind")
+	})
+
+	ensure.Run("with empty code", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+
+		ensure(erk.RegisterKindCode(ErkExample{}, "")).MatchesAllErrors(erk.ErrKindCodeEmpty)
+	})
+
+	ensure.Run("with kind registered with a different code", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+
+		ensure(erk.RegisterKindCode(ErkExample{}, "example:first")).IsNotError()
+		ensure(erk.RegisterKindCode(ErkExample{}, "example:first", "example:alias")).IsNotError()
+		ensure(erk.RegisterKindCode(ErkExample{}, "example:second")).MatchesAllErrors(erk.ErrKindAlreadyRegistered)
+		ensure(erk.GetKindString(erk.New(ErkExample{}, "my message"))).Equals("example:first")
+	})
+}
+
+func TestRegisterKind(t *testing.T) {
+	ensure := ensure.New(t)
+	defer erk.ClearKindRegistry()
+
+	ensure.Run("resolves the kind string and aliases", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+
+		ensure(erk.RegisterKind(ErkTagged{}, "github.com/JosiahWitt/erk_test:ErkTagged")).IsNotError()
+		ensure(erk.RegisterKind(&ErkTaggedPtr{})).IsNotError()
+
+		kind, ok := erk.LookupKind("example:tagged")
+		ensure(ok).IsTrue()
+		ensure(kind).Equals(ErkTagged{})
+
+		kind, ok = erk.LookupKind("github.com/JosiahWitt/erk_test:ErkTagged")
+		ensure(ok).IsTrue()
+		ensure(kind).Equals(ErkTagged{})
+
+		kind, ok = erk.LookupKind("example:tagged_ptr")
+		ensure(ok).IsTrue()
+		ensure(kind).Equals(&ErkTaggedPtr{})
+	})
+
+	ensure.Run("with unregistered kind string", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+
+		kind, ok := erk.LookupKind("example:missing")
+		ensure(ok).IsFalse()
+		ensure(kind).IsNil()
+		ensure(erk.CanonicalKindString("example:missing")).Equals("example:missing")
+	})
+
+	ensure.Run("with duplicate code", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+
+		ensure(erk.RegisterKind(ErkTagged{})).IsNotError()
+		ensure(erk.RegisterKindCode(ErkExample{}, "example:tagged")).MatchesAllErrors(erk.ErrKindCodeDuplicate)
+		ensure(erk.GetKindString(erk.New(ErkExample{}, "my message"))).Equals("github.com/JosiahWitt/erk_test:ErkExample")
+	})
+
+	ensure.Run("with duplicate alias", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearKindRegistry()
+
+		ensure(erk.RegisterKind(ErkTagged{}, "example:old")).IsNotError()
+		ensure(erk.RegisterKind(ErkCoded{}, "example:old")).MatchesAllErrors(erk.ErrKindCodeDuplicate)
+
+		_, ok := erk.LookupKind("example:coded")
+		ensure(ok).IsFalse()
+	})
+}
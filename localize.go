@@ -0,0 +1,151 @@
+package erk
+
+import (
+	"bytes"
+	"sync"
+	"text/template"
+)
+
+// Localizer translates error messages.
+// See the erklocale package for an implementation using translation catalogs.
+type Localizer interface {
+	// LocalizeMessage returns the message template for the locale, given the kind string and raw message of the error.
+	// It should return false if there is no translation, in which case the raw message is used.
+	LocalizeMessage(locale, kindString, rawMessage string) (string, bool)
+
+	// TemplateFuncsFor the locale, which are added to the kind's template functions when rendering localized messages.
+	TemplateFuncsFor(locale string) template.FuncMap
+}
+
+// Localizable errors that support rendering and exporting localized messages.
+type Localizable interface {
+	// RenderLocalized processes the localized message template, like Error.
+	RenderLocalized(locale string) string
+
+	// LocalizedIndentError processes the localized message template with the provided indentation, like IndentError.
+	LocalizedIndentError(locale, indentLevel string) string
+
+	// ExportLocalized creates a visible copy of the error with localized messages, like Export.
+	ExportLocalized(locale string) ExportedErkable
+}
+
+// Error satisfies the Localizable interface.
+var _ Localizable = &Error{}
+
+//nolint:gochecknoglobals // Set once, and read when errors are localized
+var localizer struct {
+	sync.RWMutex
+	current Localizer
+}
+
+// SetLocalizer used by RenderLocalized and ExportLocalized.
+// Setting a nil Localizer disables localization.
+func SetLocalizer(l Localizer) {
+	localizer.Lock()
+	defer localizer.Unlock()
+
+	localizer.current = l
+}
+
+func getLocalizer() Localizer {
+	localizer.RLock()
+	defer localizer.RUnlock()
+
+	return localizer.current
+}
+
+// RenderLocalized returns the error message in the locale, using the Localizer set with SetLocalizer.
+//
+// Messages without a translation for the locale, or with a translation that fails to render, fall back to the original message template.
+// Wrapped errors are also localized.
+// If the locale is empty, no Localizer is set, or err is not Localizable, err.Error() is returned.
+func RenderLocalized(err error, locale string) string {
+	if localizable, ok := err.(Localizable); ok && locale != "" {
+		return localizable.RenderLocalized(locale)
+	}
+
+	return err.Error()
+}
+
+// ExportLocalized is like Export, but the messages are in the locale.
+// See RenderLocalized.
+func ExportLocalized(err error, locale string) ExportedErkable {
+	erkErr := ToErk(err)
+	if localizable, ok := erkErr.(Localizable); ok && locale != "" {
+		return localizable.ExportLocalized(locale)
+	}
+
+	return erkErr.Export()
+}
+
+// RenderLocalized processes the localized message template with the provided params.
+// See erk.RenderLocalized.
+func (e *Error) RenderLocalized(locale string) string {
+	return e.LocalizedIndentError(locale, IndentSpaces)
+}
+
+// LocalizedIndentError processes the localized message template with the provided params and indentation.
+// See erk.RenderLocalized.
+func (e *Error) LocalizedIndentError(locale, indentLevel string) string {
+	l := getLocalizer()
+	if l == nil || locale == "" || e.literal {
+		return e.IndentError(indentLevel)
+	}
+
+	if message, ok := l.LocalizeMessage(locale, GetKindString(e), e.message); ok {
+		if localized, err := e.executeLocalized(l, locale, message, indentLevel); err == nil {
+			return localized
+		}
+	}
+
+	// Still use the locale's template functions and localize wrapped errors, even though the message is not translated
+	if localized, err := e.executeLocalized(l, locale, e.message, indentLevel); err == nil {
+		return localized
+	}
+
+	return e.IndentError(indentLevel)
+}
+
+// ExportLocalized creates a visible copy of the Error with localized messages.
+// See erk.ExportLocalized.
+func (e *Error) ExportLocalized(locale string) ExportedErkable {
+	exported := e.buildExportedError(locale)
+	exported.ErrorStack = e.buildErrorStack(locale)
+	return exported
+}
+
+func (e *Error) executeLocalized(l Localizer, locale, message, indentLevel string) (string, error) {
+	if messageFormat(e.kind) == MessageFormatPlaceholder {
+		parsed, err := parsePlaceholderMessage(message)
+		if err != nil {
+			return "", err
+		}
+
+		return parsed.render(e.params.flatten().prepLocalized(locale, indentLevel), true)
+	}
+
+	t, err := template.New("").
+		Funcs(templateFuncs(e.kind, e.isStrictMode())).
+		Funcs(l.TemplateFuncsFor(locale)).
+		Option("missingkey=error").
+		Parse(message)
+	if err != nil {
+		return "", err //nolint:wrapcheck // Only used to fall back
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, e.params.flatten().prepLocalized(locale, indentLevel)); err != nil {
+		return "", err //nolint:wrapcheck // Only used to fall back
+	}
+
+	return b.String(), nil
+}
+
+// localizedMessage returns the message in the locale, or the message if the locale is empty.
+func (e *Error) localizedMessage(locale string) string {
+	if locale == "" {
+		return e.Error()
+	}
+
+	return e.RenderLocalized(locale)
+}
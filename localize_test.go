@@ -0,0 +1,73 @@
+package erk_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+)
+
+type upperLocalizer struct{}
+
+func (upperLocalizer) LocalizeMessage(locale, kindString, rawMessage string) (string, bool) {
+	if locale != "upper" || kindString == "" {
+		return "", false
+	}
+
+	return strings.ToUpper(rawMessage[:1]) + rawMessage[1:] + " {{shout .key}}", true
+}
+
+func (upperLocalizer) TemplateFuncsFor(string) template.FuncMap {
+	return template.FuncMap{"shout": func(v interface{}) string { return strings.ToUpper(v.(string)) }}
+}
+
+func TestRenderLocalized(t *testing.T) {
+	ensure := ensure.New(t)
+
+	err := erk.WithParams(erk.New(ErkExample{}, "my message {{.key}}"), erk.Params{"key": "abc"})
+
+	ensure.Run("without localizer", func(ensure ensurepkg.Ensure) {
+		ensure(erk.RenderLocalized(err, "upper")).Equals("my message abc")
+	})
+
+	ensure.Run("with localizer", func(ensure ensurepkg.Ensure) {
+		erk.SetLocalizer(upperLocalizer{})
+		defer erk.SetLocalizer(nil)
+
+		ensure(erk.RenderLocalized(err, "upper")).Equals("My message abc ABC")
+		ensure(erk.RenderLocalized(err, "other")).Equals("my message abc")
+		ensure(err.Error()).Equals("my message abc")
+	})
+
+	ensure.Run("falls back to the original message", func(ensure ensurepkg.Ensure) {
+		erk.SetLocalizer(upperLocalizer{})
+		defer erk.SetLocalizer(nil)
+
+		noKey := erk.New(ErkExample{}, "my message")
+		ensure(erk.RenderLocalized(noKey, "upper")).Equals("my message")
+	})
+}
+
+func TestExportLocalized(t *testing.T) {
+	ensure := ensure.New(t)
+
+	erk.SetLocalizer(upperLocalizer{})
+	defer erk.SetLocalizer(nil)
+
+	ensure.Run("with erk error", func(ensure ensurepkg.Ensure) {
+		err := erk.WithParams(erk.New(ErkExample{}, "my message {{.key}}"), erk.Params{"key": "abc"})
+
+		exported := erk.ExportLocalized(err, "upper").(*erk.ExportedError)
+		ensure(exported.Message).Equals("My message abc ABC")
+		ensure(erk.Export(err).ErrorMessage()).Equals("my message abc")
+	})
+
+	ensure.Run("with regular error", func(ensure ensurepkg.Ensure) {
+		exported := erk.ExportLocalized(errors.New("my error"), "upper")
+		ensure(exported.ErrorMessage()).Equals("my error")
+	})
+}
@@ -0,0 +1,230 @@
+package erk
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MessageFormat of message templates.
+type MessageFormat int
+
+// Message formats.
+const (
+	// MessageFormatTemplate renders messages using text/template, such as "table {{.tableName}} does not exist".
+	// It is the default.
+	MessageFormatTemplate MessageFormat = iota
+
+	// MessageFormatPlaceholder renders messages with lightweight placeholders, such as "table {tableName} does not exist".
+	//
+	// Placeholders only reference params by name, so template functions, field access, and control structures are not supported.
+	// Use "{{" and "}}" to render literal braces.
+	// Missing params behave like MessageFormatTemplate: "<no value>" is rendered, or strict mode reports the missing param.
+	MessageFormatPlaceholder
+)
+
+// String returns the name of the message format.
+func (f MessageFormat) String() string {
+	switch f {
+	case MessageFormatTemplate:
+		return "template"
+	case MessageFormatPlaceholder:
+		return "placeholder"
+	default:
+		return fmt.Sprintf("MessageFormat(%d)", int(f))
+	}
+}
+
+// messageFormat of the kind, which can be set by overriding MessageFormatFor.
+func messageFormat(k Kind) MessageFormat {
+	if formatter, ok := k.(interface{ MessageFormatFor(Kind) MessageFormat }); ok {
+		return formatter.MessageFormatFor(k)
+	}
+
+	return MessageFormatTemplate
+}
+
+// placeholderNoValue is rendered for missing params, matching text/template.
+const placeholderNoValue = "<no value>"
+
+// placeholderMessage is a parsed placeholder message, which alternates between literal text and params.
+type placeholderMessage struct {
+	literals []string // One more literal than params
+	params   []string
+}
+
+// maxPlaceholderCacheSize limits the number of cached placeholder messages.
+// Messages are usually constants, so the limit is only reached if messages are built dynamically.
+const maxPlaceholderCacheSize = 1000
+
+// Parsed placeholder messages are cached, since messages are usually constants.
+// When the cache is full, it is cleared, so dynamically built messages cannot grow it without bound.
+//
+//nolint:gochecknoglobals // Cache
+var placeholderCache struct {
+	sync.RWMutex
+	messages map[string]*placeholderMessage
+}
+
+// parsePlaceholderMessage parses the message, using the cache if it was already parsed.
+func parsePlaceholderMessage(message string) (*placeholderMessage, error) {
+	placeholderCache.RLock()
+	cached, ok := placeholderCache.messages[message]
+	placeholderCache.RUnlock()
+
+	if ok {
+		return cached, nil
+	}
+
+	parsed, err := compilePlaceholderMessage(message)
+	if err != nil {
+		return nil, err
+	}
+
+	placeholderCache.Lock()
+	defer placeholderCache.Unlock()
+
+	if placeholderCache.messages == nil || len(placeholderCache.messages) >= maxPlaceholderCacheSize {
+		placeholderCache.messages = map[string]*placeholderMessage{}
+	}
+
+	placeholderCache.messages[message] = parsed
+	return parsed, nil
+}
+
+func compilePlaceholderMessage(message string) (*placeholderMessage, error) {
+	parsed := &placeholderMessage{}
+
+	var literal strings.Builder
+	for i := 0; i < len(message); i++ {
+		switch c := message[i]; {
+		case c == '{' && i+1 < len(message) && message[i+1] == '{':
+			literal.WriteByte('{')
+			i++
+		case c == '}' && i+1 < len(message) && message[i+1] == '}':
+			literal.WriteByte('}')
+			i++
+		case c == '}':
+			return nil, fmt.Errorf("unexpected } at offset %d, use }} for a literal brace", i) //nolint:err113 // Only used in strict mode violations
+		case c == '{':
+			end := strings.IndexAny(message[i+1:], "{}")
+			if end < 0 || message[i+1+end] != '}' {
+				return nil, fmt.Errorf("unclosed placeholder at offset %d, use {{ for a literal brace", i) //nolint:err113 // Only used in strict mode violations
+			}
+
+			name := strings.TrimSpace(message[i+1 : i+1+end])
+			if name == "" {
+				return nil, fmt.Errorf("empty placeholder at offset %d", i) //nolint:err113 // Only used in strict mode violations
+			}
+
+			parsed.literals = append(parsed.literals, literal.String())
+			parsed.params = append(parsed.params, name)
+			literal.Reset()
+			i += end + 1
+		default:
+			literal.WriteByte(c)
+		}
+	}
+
+	parsed.literals = append(parsed.literals, literal.String())
+	return parsed, nil
+}
+
+// render the message with the params.
+// If missingKeyError is true, missing params return an error, like the missingkey=error template option.
+func (m *placeholderMessage) render(params Params, missingKeyError bool) (string, error) {
+	var b bytes.Buffer
+	b.WriteString(m.literals[0])
+
+	for i, param := range m.params {
+		value, ok := params[param]
+		switch {
+		case ok:
+			fmt.Fprint(&b, value)
+		case missingKeyError:
+			return "", fmt.Errorf("map has no entry for key %q", param) //nolint:err113 // Only used in strict mode violations
+		default:
+			b.WriteString(placeholderNoValue)
+		}
+
+		b.WriteString(m.literals[i+1])
+	}
+
+	return b.String(), nil
+}
+
+// info returns the params referenced by the message.
+func (m *placeholderMessage) info() *TemplateInfo {
+	params := map[string]struct{}{}
+	for _, param := range m.params {
+		params[param] = struct{}{}
+	}
+
+	return &TemplateInfo{
+		Params:     sortedKeys(params),
+		ParamPaths: sortedKeys(params),
+		Funcs:      []string{},
+	}
+}
+
+// parsePlaceholders parses the Error's message as a placeholder message, reporting a strict mode violation if it is invalid.
+func (e *Error) parsePlaceholders() (*placeholderMessage, error) {
+	parsed, err := parsePlaceholderMessage(e.message)
+	if err != nil && e.isStrictMode() {
+		e.reportStrictViolation("", fmt.Sprintf(
+			"Unable to parse error placeholders:\n\tKind: %s\n\tTemplate: %s\n\tError: %v",
+			GetKindString(e),
+			e.message,
+			err,
+		))
+	}
+
+	return parsed, err
+}
+
+// indentPlaceholderError renders the Error's message as a placeholder message, like IndentError.
+func (e *Error) indentPlaceholderError(indentLevel string) string {
+	parsed, err := e.parsePlaceholders()
+	if err != nil {
+		return e.message
+	}
+
+	isStrictMode := e.isStrictMode()
+	params := e.params.flatten().prep(indentLevel)
+
+	rendered, err := parsed.render(params, isStrictMode)
+	if err != nil {
+		e.reportStrictViolation("", fmt.Sprintf(
+			"Unable to execute error placeholders:\n\tKind: %s\n\tTemplate: %s\n\tParams: %+v\n\tError: %v",
+			GetKindString(e),
+			e.message,
+			e.params.flatten(),
+			err,
+		))
+
+		// The violation was only reported as a warning, so render as if strict mode was off
+		rendered, _ = parsed.render(params, false)
+	}
+
+	return rendered
+}
+
+// parseMessageInfo returns the params referenced by the message, using the kind's message format.
+func parseMessageInfo(kind Kind, message string) (*TemplateInfo, error) {
+	return parseFormatInfo(messageFormat(kind), message)
+}
+
+// parseFormatInfo returns the params referenced by the message, using the message format.
+func parseFormatInfo(format MessageFormat, message string) (*TemplateInfo, error) {
+	if format == MessageFormatPlaceholder {
+		parsed, err := parsePlaceholderMessage(message)
+		if err != nil {
+			return nil, err
+		}
+
+		return parsed.info(), nil
+	}
+
+	return parseTemplateInfo(message)
+}
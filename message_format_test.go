@@ -0,0 +1,232 @@
+package erk_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erkstrict"
+)
+
+type ErkPlaceholder struct{ erk.DefaultKind }
+
+func (ErkPlaceholder) MessageFormatFor(erk.Kind) erk.MessageFormat {
+	return erk.MessageFormatPlaceholder
+}
+
+func TestMessageFormatString(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure(erk.MessageFormatTemplate.String()).Equals("template")
+	ensure(erk.MessageFormatPlaceholder.String()).Equals("placeholder")
+	ensure(erk.MessageFormat(10).String()).Equals("MessageFormat(10)")
+}
+
+func TestMessageFormatPlaceholder(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("when rendering", func(ensure ensurepkg.Ensure) {
+		table := []struct {
+			Name     string
+			Message  string
+			Params   erk.Params
+			Expected string
+		}{
+			{Name: "without placeholders", Message: "my message", Expected: "my message"},
+			{Name: "with empty message", Message: "", Expected: ""},
+			{
+				Name:     "with placeholders",
+				Message:  "table {tableName} does not exist in {db}",
+				Params:   erk.Params{"tableName": "users", "db": 12},
+				Expected: "table users does not exist in 12",
+			},
+			{
+				Name:     "with adjacent placeholders",
+				Message:  "{a}{b}",
+				Params:   erk.Params{"a": "x", "b": "y"},
+				Expected: "xy",
+			},
+			{
+				Name:     "with spaces in placeholder",
+				Message:  "value: { a }",
+				Params:   erk.Params{"a": "x"},
+				Expected: "value: x",
+			},
+			{
+				Name:     "with escaped braces",
+				Message:  "literal {{a}} and {{ and }} with {a}",
+				Params:   erk.Params{"a": "x"},
+				Expected: "literal {a} and { and } with x",
+			},
+			{
+				Name:     "with template syntax",
+				Message:  "{{.a}}",
+				Params:   erk.Params{"a": "x"},
+				Expected: "{.a}",
+			},
+			{
+				Name:     "with missing param",
+				Message:  "my {a} message {b}",
+				Params:   erk.Params{"b": "y"},
+				Expected: "my <no value> message y",
+			},
+			{
+				Name:     "with wrapped error",
+				Message:  "my message: {err}",
+				Params:   erk.Params{"err": errors.New("wrapped")},
+				Expected: "my message: wrapped",
+			},
+			{
+				Name:     "with unclosed placeholder",
+				Message:  "my {a message",
+				Params:   erk.Params{"a": "x"},
+				Expected: "my {a message",
+			},
+			{
+				Name:     "with unexpected closing brace",
+				Message:  "my a} message",
+				Params:   erk.Params{"a": "x"},
+				Expected: "my a} message",
+			},
+			{
+				Name:     "with empty placeholder",
+				Message:  "my {} message",
+				Expected: "my {} message",
+			},
+		}
+
+		ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+			entry := table[i]
+
+			err := erk.NewWith(ErkPlaceholder{}, entry.Message, entry.Params)
+			ensure(err.Error()).Equals(entry.Expected)
+		})
+	})
+
+	ensure.Run("when wrapping an erk error", func(ensure ensurepkg.Ensure) {
+		inner := erk.NewWith(ErkExample{}, "inner {{.a}}", erk.Params{"a": "x"})
+		err := erk.WrapWith(erk.New(ErkPlaceholder{}, "outer {b}: {err}"), inner, erk.Params{"b": "y"})
+		ensure(err.Error()).Equals("outer y: inner x")
+	})
+
+	ensure.Run("with more messages than are cached", func(ensure ensurepkg.Ensure) {
+		for i := 0; i < 2500; i++ {
+			err := erk.NewWith(ErkPlaceholder{}, "message "+strconv.Itoa(i)+": {a}", erk.Params{"a": i})
+			ensure(err.Error()).Equals("message " + strconv.Itoa(i) + ": " + strconv.Itoa(i))
+		}
+	})
+
+	ensure.Run("in strict mode", func(ensure ensurepkg.Ensure) {
+		ensure.Run("with valid message", func(ensure ensurepkg.Ensure) {
+			withStrictMode(true, func() {
+				err := erk.NewWith(ErkPlaceholder{}, "my {a} message", erk.Params{"a": "x"})
+				ensure(err.Error()).Equals("my x message")
+			})
+		})
+
+		ensure.Run("with missing param", func(ensure ensurepkg.Ensure) {
+			defer func() {
+				str, ok := strictViolationMessage(recover())
+				ensure(ok).IsTrue()
+				ensure(strings.Contains(str, "Unable to execute error placeholders:")).IsTrue()
+				ensure(strings.Contains(str, `map has no entry for key "a"`)).IsTrue()
+			}()
+
+			err := erk.New(ErkPlaceholder{}, "my {a} message")
+			withStrictMode(true, func() { _ = err.Error() }) // Used to trigger panic
+			ensure.Failf("Expected panic, so this line should not be reached")
+		})
+
+		ensure.Run("with invalid message", func(ensure ensurepkg.Ensure) {
+			defer func() {
+				str, ok := strictViolationMessage(recover())
+				ensure(ok).IsTrue()
+				ensure(strings.Contains(str, "Unable to parse error placeholders:")).IsTrue()
+				ensure(strings.Contains(str, "unclosed placeholder at offset 3")).IsTrue()
+			}()
+
+			withStrictMode(true, func() { _ = erk.New(ErkPlaceholder{}, "my {a message") })
+			ensure.Failf("Expected panic, so this line should not be reached")
+		})
+
+		ensure.Run("with warnings", func(ensure ensurepkg.Ensure) {
+			defer func() {
+				erkstrict.UnsetStrictMode()
+				erkstrict.SetStrictMode(false)
+			}()
+
+			count := 0
+			erkstrict.Configure(erkstrict.Config{
+				Level:   erkstrict.LevelWarn,
+				Handler: func(erkstrict.Level, erkstrict.Violation) { count++ },
+			})
+
+			err := erk.New(ErkPlaceholder{}, "my {a} message")
+			ensure(err.Error()).Equals("my <no value> message")
+			ensure(count).Equals(1)
+		})
+
+		ensure.Run("with unused params", func(ensure ensurepkg.Ensure) {
+			erkstrict.EnableChecks(erkstrict.CheckUnusedParams)
+			defer erkstrict.DisableChecks(erkstrict.AllChecks()...)
+
+			defer func() {
+				str, ok := strictViolationMessage(recover())
+				ensure(ok).IsTrue()
+				ensure(strings.Contains(str, `the "b" param is not referenced by the message template`)).IsTrue()
+			}()
+
+			withStrictMode(true, func() {
+				_ = erk.NewWith(ErkPlaceholder{}, "my {a} message", erk.Params{"a": 1, "b": 2})
+			})
+			ensure.Failf("Expected panic, so this line should not be reached")
+		})
+	})
+
+	ensure.Run("with template info", func(ensure ensurepkg.Ensure) {
+		err := erk.NewWith(ErkPlaceholder{}, "{b} and {a} and {b}", erk.Params{"a": 1, "c": 3})
+
+		info, infoErr := erk.GetTemplateInfo(err)
+		ensure(infoErr).IsNotError()
+		ensure(info).Equals(&erk.TemplateInfo{Params: []string{"a", "b"}, ParamPaths: []string{"a", "b"}, Funcs: []string{}})
+
+		validation, validateErr := erk.Validate(err)
+		ensure(validateErr).IsNotError()
+		ensure(validation).Equals(&erk.TemplateValidation{Missing: []string{"b"}, Extra: []string{"c"}})
+	})
+
+	ensure.Run("with invalid template info", func(ensure ensurepkg.Ensure) {
+		info, infoErr := erk.GetTemplateInfo(erk.New(ErkPlaceholder{}, "{a"))
+		ensure(infoErr).IsError(erk.ErrTemplateInvalid)
+		ensure(info).IsNil()
+	})
+
+	ensure.Run("with default kind", func(ensure ensurepkg.Ensure) {
+		ensure(erk.DefaultKind{}.MessageFormatFor(nil)).Equals(erk.MessageFormatTemplate)
+		ensure((&erk.DefaultPtrKind{}).MessageFormatFor(nil)).Equals(erk.MessageFormatTemplate)
+	})
+}
+
+func BenchmarkMessageFormat(b *testing.B) {
+	params := erk.Params{"tableName": "users"}
+
+	withStrictMode(false, func() {
+		b.Run("template", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = erk.NewWith(ErkExample{}, "table {{.tableName}} does not exist", params).Error()
+			}
+		})
+
+		b.Run("placeholder", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = erk.NewWith(ErkPlaceholder{}, "table {tableName} does not exist", params).Error()
+			}
+		})
+	})
+}
@@ -0,0 +1,243 @@
+package erk
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// ErkNumericCode is the kind of errors returned when registering numeric codes.
+type ErkNumericCode struct{ DefaultKind }
+
+// KindStringFor the provided kind.
+func (ErkNumericCode) KindStringFor(Kind) string {
+	return "erk:numeric_code"
+}
+
+// Errors returned when registering numeric codes.
+var (
+	ErrNumericCodeDuplicate         = New(ErkNumericCode{}, "numeric code {{.code}} of {{.kind}} is already used by {{.existing}}")
+	ErrNumericCodeAlreadyRegistered = New(ErkNumericCode{}, "kind {{.kind}} is already registered with numeric code {{.existing}}, not {{.code}}")
+	ErrNumericCodeReserved          = New(ErkNumericCode{}, "numeric code {{.code}} of {{.kind}} is reserved for package {{.package}}")
+	ErrNumericCodeOutOfRange        = New(ErkNumericCode{}, "numeric code {{.code}} of {{.kind}} is outside the range {{.min}} to {{.max}}")
+	ErrNumericCodeWrongPackage      = New(ErkNumericCode{}, "kind {{.kind}} cannot be registered in the range reserved for package {{.package}}")
+	ErrNumericCodeRangeInvalid      = New(ErkNumericCode{}, "numeric code range {{.min}} to {{.max}} for package {{.package}} is invalid")
+	ErrNumericCodeRangeOverlap      = New(ErkNumericCode{}, "numeric code range {{.min}} to {{.max}} for package {{.package}} overlaps the range for package {{.existing}}")
+	ErrNumericCodeRangeInUse        = New(ErkNumericCode{}, "numeric code range {{.min}} to {{.max}} for package {{.package}} contains code {{.code}} already used by {{.kind}}")
+)
+
+// NumericCodeRange is a range of numeric codes reserved for kinds declared in a package.
+// Create it using ReserveNumericCodes.
+type NumericCodeRange struct {
+	pkg string
+	min int
+	max int
+}
+
+//nolint:gochecknoglobals // Registered once, and read when errors are exported
+var numericCodes struct {
+	sync.RWMutex
+	codes  map[reflect.Type]int // kind type, without pointers, to code
+	kinds  map[int]reflect.Type // code to registered kind type
+	ranges []*NumericCodeRange
+}
+
+// RegisterNumericCode assigns a numeric code to the kind, which is included when the error is exported.
+// This is useful for consumers that cannot use kind strings, such as embedded devices or legacy systems.
+//
+// A kind and a pointer to the kind share the same code, so it works with kinds embedding DefaultKind or DefaultPtrKind.
+// An error is returned if the code is already used by a different kind, the kind already has a different code,
+// or the code is in a range reserved for a different package.
+// It is recommended to register codes in an init function, so collisions are detected on startup.
+func RegisterNumericCode(kind Kind, code int) error {
+	kindType := reflect.TypeOf(kind)
+
+	numericCodes.Lock()
+	defer numericCodes.Unlock()
+
+	for _, r := range numericCodes.ranges {
+		if r.contains(code) && r.pkg != baseKindType(kindType).PkgPath() {
+			return WithParams(ErrNumericCodeReserved, Params{"code": code, "kind": kindTypeName(kind), "package": r.pkg})
+		}
+	}
+
+	return registerNumericCode(kindType, code)
+}
+
+// ReserveNumericCodes reserves the inclusive range of numeric codes for kinds declared in the package.
+// Once reserved, only kinds declared in the package can use codes in the range.
+//
+// An error is returned if the range is empty, overlaps a range reserved for a different package,
+// or contains a code already registered by a kind declared in a different package.
+//
+// Example:
+//
+//	func init() {
+//	  codes, err := erk.ReserveNumericCodes("github.com/username/store", 1000, 1999)
+//	  if err != nil {
+//	    panic(err)
+//	  }
+//
+//	  if err := codes.Register(ErkNotFound{}, 1); err != nil { // Numeric code 1001
+//	    panic(err)
+//	  }
+//	}
+func ReserveNumericCodes(pkgPath string, minCode, maxCode int) (*NumericCodeRange, error) {
+	if minCode > maxCode {
+		return nil, WithParams(ErrNumericCodeRangeInvalid, Params{"package": pkgPath, "min": minCode, "max": maxCode})
+	}
+
+	numericCodes.Lock()
+	defer numericCodes.Unlock()
+
+	for _, r := range numericCodes.ranges {
+		if r.pkg == pkgPath && r.min == minCode && r.max == maxCode {
+			return r, nil
+		}
+
+		if minCode <= r.max && r.min <= maxCode {
+			return nil, WithParams(ErrNumericCodeRangeOverlap, Params{"package": pkgPath, "min": minCode, "max": maxCode, "existing": r.pkg})
+		}
+	}
+
+	r := &NumericCodeRange{pkg: pkgPath, min: minCode, max: maxCode}
+	if code, kindType, ok := r.firstForeignCode(); ok {
+		return nil, WithParams(ErrNumericCodeRangeInUse, Params{"package": pkgPath, "min": minCode, "max": maxCode, "code": code, "kind": kindType.String()})
+	}
+
+	numericCodes.ranges = append(numericCodes.ranges, r)
+	return r, nil
+}
+
+// Register the kind with the numeric code at the offset from the start of the range.
+// The kind must be declared in the range's package, and the code must be within the range.
+func (r *NumericCodeRange) Register(kind Kind, offset int) error {
+	kindType := reflect.TypeOf(kind)
+	code := r.min + offset
+
+	if baseKindType(kindType).PkgPath() != r.pkg {
+		return WithParams(ErrNumericCodeWrongPackage, Params{"kind": kindTypeName(kind), "package": r.pkg})
+	}
+
+	if !r.contains(code) {
+		return WithParams(ErrNumericCodeOutOfRange, Params{"code": code, "kind": kindTypeName(kind), "min": r.min, "max": r.max})
+	}
+
+	numericCodes.Lock()
+	defer numericCodes.Unlock()
+
+	return registerNumericCode(kindType, code)
+}
+
+// Package returns the path of the package the range is reserved for.
+func (r *NumericCodeRange) Package() string {
+	return r.pkg
+}
+
+// Min returns the first numeric code in the range.
+func (r *NumericCodeRange) Min() int {
+	return r.min
+}
+
+// Max returns the last numeric code in the range.
+func (r *NumericCodeRange) Max() int {
+	return r.max
+}
+
+func (r *NumericCodeRange) contains(code int) bool {
+	return r.min <= code && code <= r.max
+}
+
+// firstForeignCode returns the lowest code in the range registered by a kind declared in a different package.
+// It must be called while holding the numericCodes lock.
+func (r *NumericCodeRange) firstForeignCode() (int, reflect.Type, bool) {
+	var (
+		foreignCode int
+		foreignType reflect.Type
+	)
+
+	for code, kindType := range numericCodes.kinds {
+		if !r.contains(code) || baseKindType(kindType).PkgPath() == r.pkg {
+			continue
+		}
+
+		if foreignType == nil || code < foreignCode {
+			foreignCode, foreignType = code, kindType
+		}
+	}
+
+	return foreignCode, foreignType, foreignType != nil
+}
+
+// NumericCodeFor returns the numeric code registered for the kind.
+// If the kind does not have a numeric code, false is returned.
+func NumericCodeFor(kind Kind) (int, bool) {
+	if kind == nil {
+		return 0, false
+	}
+
+	numericCodes.RLock()
+	defer numericCodes.RUnlock()
+
+	code, ok := numericCodes.codes[baseKindType(reflect.TypeOf(kind))]
+	return code, ok
+}
+
+// GetNumericCode returns the numeric code registered for the error's kind.
+// If the error does not have a kind, or the kind does not have a numeric code, false is returned.
+func GetNumericCode(err error) (int, bool) {
+	var k Kindable
+	if !errors.As(err, &k) {
+		return 0, false
+	}
+
+	return NumericCodeFor(k.Kind())
+}
+
+// LookupNumericCode returns a new zero value of the kind registered with the numeric code.
+// If the kind was registered as a pointer, a pointer to a new zero value is returned.
+// If no kind is registered with the numeric code, nil and false are returned.
+func LookupNumericCode(code int) (Kind, bool) {
+	numericCodes.RLock()
+	kindType, ok := numericCodes.kinds[code]
+	numericCodes.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return newKind(kindType), true
+}
+
+// ClearNumericCodes removes all registered numeric codes and reserved ranges.
+// This is mostly useful in tests.
+func ClearNumericCodes() {
+	numericCodes.Lock()
+	defer numericCodes.Unlock()
+
+	numericCodes.codes = nil
+	numericCodes.kinds = nil
+	numericCodes.ranges = nil
+}
+
+// registerNumericCode must be called while holding the numericCodes lock.
+func registerNumericCode(kindType reflect.Type, code int) error {
+	baseType := baseKindType(kindType)
+
+	if existing, ok := numericCodes.codes[baseType]; ok && existing != code {
+		return WithParams(ErrNumericCodeAlreadyRegistered, Params{"kind": kindType.String(), "code": code, "existing": existing})
+	}
+
+	if existingType, ok := numericCodes.kinds[code]; ok && baseKindType(existingType) != baseType {
+		return WithParams(ErrNumericCodeDuplicate, Params{"code": code, "kind": kindType.String(), "existing": existingType.String()})
+	}
+
+	if numericCodes.codes == nil {
+		numericCodes.codes = map[reflect.Type]int{}
+		numericCodes.kinds = map[int]reflect.Type{}
+	}
+
+	numericCodes.codes[baseType] = code
+	numericCodes.kinds[code] = kindType
+	return nil
+}
@@ -0,0 +1,178 @@
+package erk_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+)
+
+const testPackagePath = "github.com/JosiahWitt/erk_test"
+
+type ErkNumericPtr struct{ erk.DefaultPtrKind }
+
+func TestRegisterNumericCode(t *testing.T) {
+	ensure := ensure.New(t)
+	defer erk.ClearNumericCodes()
+
+	ensure.Run("exports the code", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+		ensure(erk.RegisterNumericCode(ErkExample{}, 42)).IsNotError()
+
+		err := erk.New(ErkExample{}, "my message")
+		code, ok := erk.GetNumericCode(err)
+		ensure(ok).IsTrue()
+		ensure(code).Equals(42)
+
+		bytes, jsonErr := json.Marshal(err)
+		ensure(jsonErr).IsNotError()
+		ensure(string(bytes)).Equals(`{"kind":"github.com/JosiahWitt/erk_test:ErkExample","message":"my message","code":42}`)
+	})
+
+	ensure.Run("works with pointer kinds", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+		ensure(erk.RegisterNumericCode(&ErkNumericPtr{}, 7)).IsNotError()
+
+		code, ok := erk.GetNumericCode(erk.New(&ErkNumericPtr{}, "my message"))
+		ensure(ok).IsTrue()
+		ensure(code).Equals(7)
+
+		kind, ok := erk.LookupNumericCode(7)
+		ensure(ok).IsTrue()
+		ensure(kind).Equals(&ErkNumericPtr{})
+	})
+
+	ensure.Run("reverse lookup", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+		ensure(erk.RegisterNumericCode(ErkExample{}, 42)).IsNotError()
+
+		kind, ok := erk.LookupNumericCode(42)
+		ensure(ok).IsTrue()
+		ensure(kind).Equals(ErkExample{})
+
+		kind, ok = erk.LookupNumericCode(43)
+		ensure(ok).IsFalse()
+		ensure(kind).IsNil()
+	})
+
+	ensure.Run("without a code", func(ensure ensurepkg.Ensure) {
+		code, ok := erk.GetNumericCode(erk.New(ErkExample{}, "my message"))
+		ensure(ok).IsFalse()
+		ensure(code).Equals(0)
+		ensure(erk.Export(erk.New(ErkExample{}, "my message")).(*erk.ExportedError).Code).IsNil()
+	})
+
+	ensure.Run("with duplicate code", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+		ensure(erk.RegisterNumericCode(ErkExample{}, 42)).IsNotError()
+		ensure(erk.RegisterNumericCode(ErkExample{}, 42)).IsNotError()
+		ensure(erk.RegisterNumericCode(&ErkExample{}, 42)).IsNotError()
+		ensure(erk.RegisterNumericCode(ErkExample2{}, 42)).MatchesAllErrors(erk.ErrNumericCodeDuplicate)
+	})
+
+	ensure.Run("with kind already registered", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+		ensure(erk.RegisterNumericCode(ErkExample{}, 42)).IsNotError()
+		ensure(erk.RegisterNumericCode(ErkExample{}, 43)).MatchesAllErrors(erk.ErrNumericCodeAlreadyRegistered)
+	})
+
+	ensure.Run("with code reserved for another package", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+		_, err := erk.ReserveNumericCodes("example.com/other", 100, 199)
+		ensure(err).IsNotError()
+		ensure(erk.RegisterNumericCode(ErkExample{}, 150)).MatchesAllErrors(erk.ErrNumericCodeReserved)
+	})
+}
+
+func TestReserveNumericCodes(t *testing.T) {
+	ensure := ensure.New(t)
+	defer erk.ClearNumericCodes()
+
+	ensure.Run("registers codes at offsets", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+
+		codes, err := erk.ReserveNumericCodes(testPackagePath, 1000, 1999)
+		ensure(err).IsNotError()
+		ensure(codes.Package()).Equals(testPackagePath)
+		ensure(codes.Min()).Equals(1000)
+		ensure(codes.Max()).Equals(1999)
+		ensure(codes.Register(ErkExample{}, 1)).IsNotError()
+		ensure(codes.Register(&ErkNumericPtr{}, 2)).IsNotError()
+
+		code, _ := erk.NumericCodeFor(ErkExample{})
+		ensure(code).Equals(1001)
+		code, _ = erk.NumericCodeFor(&ErkNumericPtr{})
+		ensure(code).Equals(1002)
+
+		again, err := erk.ReserveNumericCodes(testPackagePath, 1000, 1999)
+		ensure(err).IsNotError()
+		ensure(again).Equals(codes)
+	})
+
+	ensure.Run("with code outside the range", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+
+		codes, err := erk.ReserveNumericCodes(testPackagePath, 1000, 1009)
+		ensure(err).IsNotError()
+		ensure(codes.Register(ErkExample{}, 10)).MatchesAllErrors(erk.ErrNumericCodeOutOfRange)
+	})
+
+	ensure.Run("with kind from another package", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+
+		codes, err := erk.ReserveNumericCodes("example.com/other", 1000, 1999)
+		ensure(err).IsNotError()
+		ensure(codes.Register(ErkExample{}, 1)).MatchesAllErrors(erk.ErrNumericCodeWrongPackage)
+	})
+
+	ensure.Run("with range that was not reserved", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+
+		codes := &erk.NumericCodeRange{}
+		ensure(codes.Register(ErkExample{}, 0)).MatchesAllErrors(erk.ErrNumericCodeWrongPackage)
+
+		_, ok := erk.NumericCodeFor(ErkExample{})
+		ensure(ok).IsFalse()
+	})
+
+	ensure.Run("with overlapping ranges", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+
+		_, err := erk.ReserveNumericCodes("example.com/other", 1000, 1999)
+		ensure(err).IsNotError()
+
+		codes, err := erk.ReserveNumericCodes(testPackagePath, 1500, 2499)
+		ensure(err).MatchesAllErrors(erk.ErrNumericCodeRangeOverlap)
+		ensure(codes).IsNil()
+	})
+
+	ensure.Run("with codes already registered by another package", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+		ensure(erk.RegisterNumericCode(ErkExample{}, 1500)).IsNotError()
+		ensure(erk.RegisterNumericCode(ErkExample2{}, 1200)).IsNotError()
+
+		codes, err := erk.ReserveNumericCodes("example.com/other", 1000, 1999)
+		ensure(err).MatchesAllErrors(erk.ErrNumericCodeRangeInUse)
+		ensure(err.Error()).Equals("numeric code range 1000 to 1999 for package example.com/other contains code 1200 already used by erk_test.ErkExample2")
+		ensure(codes).IsNil()
+	})
+
+	ensure.Run("with codes already registered by the same package", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+		ensure(erk.RegisterNumericCode(ErkExample{}, 1001)).IsNotError()
+
+		codes, err := erk.ReserveNumericCodes(testPackagePath, 1000, 1999)
+		ensure(err).IsNotError()
+		ensure(codes.Register(ErkExample{}, 1)).IsNotError()
+	})
+
+	ensure.Run("with invalid range", func(ensure ensurepkg.Ensure) {
+		defer erk.ClearNumericCodes()
+
+		codes, err := erk.ReserveNumericCodes(testPackagePath, 10, 9)
+		ensure(err).MatchesAllErrors(erk.ErrNumericCodeRangeInvalid)
+		ensure(codes).IsNil()
+	})
+}
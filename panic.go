@@ -0,0 +1,102 @@
+package erk
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ErkPanic is the kind of errors created from a recovered panic.
+type ErkPanic struct{ DefaultKind }
+
+// KindStringFor the provided kind.
+func (ErkPanic) KindStringFor(Kind) string {
+	return "erk:panic"
+}
+
+// ErrPanic is the error created from a recovered panic.
+//
+// The value param contains the panic value formatted as a string, so it can always be exported as JSON.
+// The stack trace at the time of the panic is stored in the PanicStackMetadata metadata, since it should not be rendered.
+// If the panic value is an error, it is also wrapped, so errors.Is and errors.Unwrap work with the original error.
+var ErrPanic = New(ErkPanic{}, "panic: {{.value}}")
+
+// PanicStackMetadata is the metadata key that contains the stack trace of a recovered panic.
+// See GetPanicStack.
+const PanicStackMetadata = "stack"
+
+// FromPanic creates an error from a recovered panic value.
+// It should be called from the deferred function that recovered the panic, so the stack trace includes where the panic occurred.
+//
+// If the value is already an error with the ErkPanic kind, such as when a recovered panic is panicked again, it is returned unchanged.
+// If the value is nil, nil is returned.
+//
+// Strict mode violations panic with an *erkstrict.ViolationError, which is wrapped like other errors.
+// It unwraps to the error that caused the violation, so errors.Is matches it.
+func FromPanic(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	valueErr, isErr := value.(error)
+	if isErr && IsKind(valueErr, ErkPanic{}) {
+		return valueErr
+	}
+
+	params := Params{"value": fmt.Sprint(value)}
+	if isErr {
+		params["value"] = valueErr.Error()
+		params[OriginalErrorParam] = valueErr
+	}
+
+	return WithMetadata(WithParams(ErrPanic, params), Metadata{PanicStackMetadata: string(debug.Stack())})
+}
+
+// Recover from a panic, and set the error to an ErrPanic error.
+// It must be directly deferred.
+// If there is no panic, the error is not modified.
+//
+// Example:
+//
+//	func doSomething() (err error) {
+//	  defer erk.Recover(&err)
+//
+//	  ...
+//	}
+func Recover(errPtr *error) {
+	if panicErr := FromPanic(recover()); panicErr != nil {
+		*errPtr = panicErr
+	}
+}
+
+// Go runs the function in a new goroutine, and sends the returned error or recovered panic on the returned channel.
+// The channel receives a single error, which is nil if the function succeeded, and is then closed.
+func Go(fn func() error) <-chan error {
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+
+		var err error
+		func() {
+			defer Recover(&err)
+			err = fn()
+		}()
+
+		errs <- err
+	}()
+
+	return errs
+}
+
+// GetPanicStack returns the stack trace of the panic that created the error.
+// If err is not an ErrPanic error, an empty string is returned.
+func GetPanicStack(err error) string {
+	var e Erkable
+	if !errors.As(err, &e) || !IsKind(e, ErkPanic{}) {
+		return ""
+	}
+
+	stack, _ := GetMetadata(e)[PanicStackMetadata].(string)
+	return stack
+}
@@ -0,0 +1,173 @@
+package erk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erkstrict"
+)
+
+func TestFromPanic(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with nil value", func(ensure ensurepkg.Ensure) {
+		ensure(erk.FromPanic(nil)).IsNil()
+	})
+
+	ensure.Run("with non error value", func(ensure ensurepkg.Ensure) {
+		err := erk.FromPanic("something bad")
+		ensure(err.Error()).Equals("panic: something bad")
+		ensure(erk.IsKind(err, erk.ErkPanic{})).IsTrue()
+		ensure(errors.Is(err, erk.ErrPanic)).IsTrue()
+		ensure(erk.GetParams(err)["value"]).Equals("something bad")
+		ensure(errors.Unwrap(err)).IsNil()
+		ensure(strings.Contains(erk.GetPanicStack(err), "runtime/debug.Stack")).IsTrue()
+	})
+
+	ensure.Run("with non JSON value", func(ensure ensurepkg.Ensure) {
+		err := erk.FromPanic(struct{ C chan int }{})
+		ensure(err.Error()).Equals("panic: {<nil>}")
+		ensure(erk.GetParams(err)).Equals(erk.Params{"value": "{<nil>}"})
+		ensure(erk.GetMetadata(err)[erk.PanicStackMetadata]).Equals(erk.GetPanicStack(err))
+
+		_, jsonErr := json.Marshal(err)
+		ensure(jsonErr).IsNotError()
+	})
+
+	ensure.Run("with error value", func(ensure ensurepkg.Ensure) {
+		original := errors.New("original")
+		err := erk.FromPanic(original)
+		ensure(err.Error()).Equals("panic: original")
+		ensure(erk.IsKind(err, erk.ErkPanic{})).IsTrue()
+		ensure(errors.Is(err, original)).IsTrue()
+		ensure(errors.Unwrap(err) == original).IsTrue()
+	})
+
+	ensure.Run("with erk error value", func(ensure ensurepkg.Ensure) {
+		errExample := erk.New(ErkExample{}, "my message {{.a}}")
+		original := erk.WithParams(errExample, erk.Params{"a": "hello"})
+		err := erk.FromPanic(original)
+		ensure(err.Error()).Equals("panic: my message hello")
+		ensure(erk.IsKind(err, erk.ErkPanic{})).IsTrue()
+		ensure(errors.Is(err, errExample)).IsTrue()
+		ensure(erk.IsKind(errors.Unwrap(err), ErkExample{})).IsTrue()
+	})
+
+	ensure.Run("with panic error value", func(ensure ensurepkg.Ensure) {
+		original := erk.FromPanic("something bad")
+		err := erk.FromPanic(original)
+		ensure(err.Error()).Equals("panic: something bad")
+		ensure(erk.GetPanicStack(err)).Equals(erk.GetPanicStack(original))
+		ensure(errors.Unwrap(err)).IsNil()
+	})
+}
+
+func TestRecover(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("without panic", func(ensure ensurepkg.Ensure) {
+		original := errors.New("original")
+		err := func() (err error) {
+			defer erk.Recover(&err)
+			return original
+		}()
+
+		ensure(err == original).IsTrue()
+	})
+
+	ensure.Run("with panic", func(ensure ensurepkg.Ensure) {
+		err := panickingFunc()
+		ensure(err.Error()).Equals("panic: panicking func")
+		ensure(erk.IsKind(err, erk.ErkPanic{})).IsTrue()
+		ensure(strings.Contains(erk.GetPanicStack(err), "panickingFunc")).IsTrue()
+	})
+
+	ensure.Run("with error panic", func(ensure ensurepkg.Ensure) {
+		original := errors.New("original")
+		err := func() (err error) {
+			defer erk.Recover(&err)
+			panic(original)
+		}()
+
+		ensure(errors.Is(err, original)).IsTrue()
+	})
+
+	ensure.Run("with erk error panic in strict mode", func(ensure ensurepkg.Ensure) {
+		errExample := erk.New(ErkExample{}, "my message {{.a}}")
+
+		var err error
+		withStrictMode(true, func() {
+			err = func() (err error) {
+				defer erk.Recover(&err)
+				panic(erk.WithParam(errExample, "a", "hello"))
+			}()
+		})
+
+		ensure(err.Error()).Equals("panic: my message hello")
+		ensure(errors.Is(err, errExample)).IsTrue()
+	})
+
+	ensure.Run("with strict mode violation", func(ensure ensurepkg.Ensure) {
+		errExample := erk.New(ErkExample{}, "my message {{.a}}")
+
+		var err error
+		withStrictMode(true, func() {
+			err = func() (err error) {
+				defer erk.Recover(&err)
+				return errors.New(errExample.Error())
+			}()
+		})
+
+		ensure(erk.IsKind(err, erk.ErkPanic{})).IsTrue()
+		ensure(strings.Contains(err.Error(), "Unable to execute error template")).IsTrue()
+		ensure(erk.GetPanicStack(err) != "").IsTrue()
+
+		var violationErr *erkstrict.ViolationError
+		ensure(errors.As(err, &violationErr)).IsTrue()
+		ensure(violationErr.Violation.PackagePath).Equals("github.com/JosiahWitt/erk_test")
+		ensure(errors.Is(err, errExample)).IsTrue()
+	})
+}
+
+func panickingFunc() (err error) {
+	defer erk.Recover(&err)
+	panic("panicking func")
+}
+
+func TestGo(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with success", func(ensure ensurepkg.Ensure) {
+		errs := erk.Go(func() error { return nil })
+		ensure(<-errs).IsNil()
+
+		_, open := <-errs
+		ensure(open).IsFalse()
+	})
+
+	ensure.Run("with error", func(ensure ensurepkg.Ensure) {
+		original := errors.New("original")
+		errs := erk.Go(func() error { return original })
+		ensure(<-errs == original).IsTrue()
+	})
+
+	ensure.Run("with panic", func(ensure ensurepkg.Ensure) {
+		errs := erk.Go(func() error { panic(fmt.Sprintf("bad %d", 1)) })
+		err := <-errs
+		ensure(err.Error()).Equals("panic: bad 1")
+		ensure(erk.IsKind(err, erk.ErkPanic{})).IsTrue()
+	})
+}
+
+func TestGetPanicStack(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure(erk.GetPanicStack(errors.New("not a panic"))).Equals("")
+	ensure(erk.GetPanicStack(erk.New(ErkExample{}, "my message"))).Equals("")
+}
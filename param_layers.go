@@ -0,0 +1,105 @@
+package erk
+
+// maxParamLayers limits how many layers are walked when looking up a param.
+// When the limit is reached, the layers are flattened into a single layer.
+const maxParamLayers = 8
+
+// paramLayers store params as a base layer followed by the changes made by each WithParams call, oldest first.
+//
+// Layers are never modified after they are added, so they are shared between an Error and the copies returned
+// by WithParams, instead of copying every param each time.
+// Nil values in the base layer are params, but nil values in later layers delete the param.
+type paramLayers []Params
+
+// newParamLayers creates the base layer from a copy of the params.
+func newParamLayers(params Params) paramLayers {
+	if params == nil {
+		return nil
+	}
+
+	return paramLayers{params.Clone()}
+}
+
+// with returns new layers with the params added as a layer.
+// A nil param value deletes the param key.
+// The params may be stored, so they must not be modified afterwards.
+func (l paramLayers) with(params Params) paramLayers {
+	if len(l) == 0 {
+		for key, value := range params {
+			if value == nil {
+				delete(params, key) // Deleted params are not needed in the base layer
+			}
+		}
+
+		return paramLayers{params}
+	}
+
+	if len(l) >= maxParamLayers {
+		base := l.flattenNew()
+		applyParamChanges(base, params)
+		return paramLayers{base}
+	}
+
+	l2 := make(paramLayers, len(l)+1)
+	copy(l2, l)
+	l2[len(l)] = params
+	return l2
+}
+
+// get the value of the param, checking the newest layer first.
+func (l paramLayers) get(key string) (interface{}, bool) {
+	for i := len(l) - 1; i >= 0; i-- {
+		if value, ok := l[i][key]; ok {
+			return value, value != nil || i == 0
+		}
+	}
+
+	return nil, false
+}
+
+// flatten the layers into a single map.
+// The map is shared if there is only one layer, so it must not be modified.
+func (l paramLayers) flatten() Params {
+	switch len(l) {
+	case 0:
+		return nil
+	case 1:
+		return l[0]
+	default:
+		return l.flattenNew()
+	}
+}
+
+// flattenNew flattens the layers into a new map.
+func (l paramLayers) flattenNew() Params {
+	size := 0
+	for _, layer := range l {
+		size += len(layer)
+	}
+
+	flat := make(Params, size)
+	for i, layer := range l {
+		if i == 0 {
+			for key, value := range layer {
+				flat[key] = value
+			}
+
+			continue
+		}
+
+		applyParamChanges(flat, layer)
+	}
+
+	return flat
+}
+
+// applyParamChanges to the params, where a nil value deletes the param key.
+func applyParamChanges(params, changes Params) {
+	for key, value := range changes {
+		if value == nil {
+			delete(params, key)
+		} else {
+			params[key] = value
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package erk_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+)
+
+func TestParamLayers(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("when adding params to copies", func(ensure ensurepkg.Ensure) {
+		err1 := erk.NewWith(ErkExample{}, "my message", erk.Params{"a": 1})
+		err2 := erk.WithParams(err1, erk.Params{"b": 2})
+		err3 := erk.WithParams(err1, erk.Params{"b": 3, "c": 4})
+		err4 := erk.WithParams(err2, erk.Params{"a": 5})
+
+		ensure(erk.GetParams(err1)).Equals(erk.Params{"a": 1})
+		ensure(erk.GetParams(err2)).Equals(erk.Params{"a": 1, "b": 2})
+		ensure(erk.GetParams(err3)).Equals(erk.Params{"a": 1, "b": 3, "c": 4})
+		ensure(erk.GetParams(err4)).Equals(erk.Params{"a": 5, "b": 2})
+	})
+
+	ensure.Run("when deleting params", func(ensure ensurepkg.Ensure) {
+		err1 := erk.NewWith(ErkExample{}, "my message", erk.Params{"a": 1, "b": 2})
+		err2 := erk.WithParams(err1, erk.Params{"a": nil, "c": nil})
+		err3 := erk.WithParams(err2, erk.Params{"a": 3})
+
+		ensure(erk.GetParams(err1)).Equals(erk.Params{"a": 1, "b": 2})
+		ensure(erk.GetParams(err2)).Equals(erk.Params{"b": 2})
+		ensure(erk.GetParams(err3)).Equals(erk.Params{"a": 3, "b": 2})
+	})
+
+	ensure.Run("when deleting params without initial params", func(ensure ensurepkg.Ensure) {
+		err := erk.WithParams(erk.New(ErkExample{}, "my message"), erk.Params{"a": nil, "b": 1})
+		ensure(erk.GetParams(err)).Equals(erk.Params{"b": 1})
+	})
+
+	ensure.Run("when creating an error with nil params", func(ensure ensurepkg.Ensure) {
+		err := erk.NewWith(ErkExample{}, "my message", erk.Params{"a": nil})
+		ensure(erk.GetParams(err)).Equals(erk.Params{"a": nil})
+	})
+
+	ensure.Run("when modifying the returned params", func(ensure ensurepkg.Ensure) {
+		err := erk.WithParams(erk.NewWith(ErkExample{}, "my message", erk.Params{"a": 1}), erk.Params{"b": 2})
+
+		params := erk.GetParams(err)
+		params["a"] = 3
+		ensure(erk.GetParams(err)).Equals(erk.Params{"a": 1, "b": 2})
+	})
+
+	ensure.Run("when adding many params", func(ensure ensurepkg.Ensure) {
+		err := erk.New(ErkExample{}, "my message")
+		expected := erk.Params{}
+
+		for i := 0; i < 50; i++ {
+			key := fmt.Sprintf("p%d", i%7)
+			if i%5 == 0 {
+				err = erk.WithParam(err, key, nil)
+				delete(expected, key)
+			} else {
+				err = erk.WithParam(err, key, i)
+				expected[key] = i
+			}
+
+			ensure(erk.GetParams(err)).Equals(expected)
+		}
+	})
+
+	ensure.Run("when comparing errors with the same params", func(ensure ensurepkg.Ensure) {
+		err1 := erk.WithParams(erk.NewWith(ErkExample{}, "my {{.a}} {{.b}} message", erk.Params{"a": 1}), erk.Params{"b": 2})
+		err2 := erk.WithParams(erk.NewWith(ErkExample{}, "my {{.a}} {{.b}} message", erk.Params{"a": 1}), erk.Params{"b": 2})
+		ensure(err1.Error()).Equals("my 1 2 message")
+		ensure(erk.GetParams(err1)).Equals(erk.Params{"a": 1, "b": 2})
+
+		ensure(reflect.DeepEqual(err1, err2)).IsTrue()
+		ensure(err1).Equals(err2)
+	})
+
+	ensure.Run("when unwrapping", func(ensure ensurepkg.Ensure) {
+		original := errors.New("original")
+
+		err1 := erk.WrapAs(erk.New(ErkExample{}, "my message: {{.err}}"), original)
+		err2 := erk.WithParam(err1, "a", 1)
+		err3 := erk.WithParam(err2, erk.OriginalErrorParam, nil)
+
+		ensure(errors.Unwrap(err1)).Equals(original)
+		ensure(errors.Unwrap(err2)).Equals(original)
+		ensure(errors.Unwrap(err3)).IsNil()
+		ensure(errors.Unwrap(erk.New(ErkExample{}, "my message"))).IsNil()
+	})
+
+	ensure.Run("when reading concurrently", func(ensure ensurepkg.Ensure) {
+		err := erk.New(ErkExample{}, "my {{.a}} message")
+		for i := 0; i < 20; i++ {
+			err = erk.WithParam(err, "a", i)
+		}
+
+		const goroutines = 20
+		results := make([]erk.Params, goroutines)
+
+		var wg sync.WaitGroup
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+				results[i] = erk.GetParams(err)
+			}(i)
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			ensure(result).Equals(erk.Params{"a": 19})
+		}
+	})
+}
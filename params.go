@@ -30,6 +30,10 @@ func WithParams(err error, params Params) error {
 		return err
 	}
 
+	if p, ok := err.(Paramable); ok { // Avoids allocating the errors.As target in the common case
+		return p.WithParams(params)
+	}
+
 	var p Paramable
 	if errors.As(err, &p) {
 		return p.WithParams(params)
@@ -43,6 +47,10 @@ func WithParams(err error, params Params) error {
 // If err does not satisfy Paramable, the original error is returned.
 // A nil param value deletes the param key.
 func WithParam(err error, key string, value interface{}) error {
+	if e, ok := err.(*Error); ok {
+		return e.withParamLayer(Params{key: value}) // Not copied, since the params are not shared
+	}
+
 	return WithParams(err, Params{key: value})
 }
 
@@ -78,22 +86,35 @@ func (p Params) MarshalJSON() ([]byte, error) {
 }
 
 func (p Params) prep(indentLevel string) Params {
-	p2 := p.Clone()
+	return p.prepLocalized("", indentLevel)
+}
+
+// prepLocalized is like prep, but localizes the original error if the locale is not empty.
+// The params are only copied when the original error is converted to a string, so the result must not be modified.
+func (p Params) prepLocalized(locale, indentLevel string) Params {
+	if p == nil {
+		return Params{}
+	}
 
-	if rawErr, ok := p2[OriginalErrorParam]; ok {
-		if indentable, ok := rawErr.(ErrorIndentable); ok {
-			p2[OriginalErrorParam] = indentable.IndentError(indentLevel)
-		} else if err, ok := rawErr.(error); ok {
-			strError := err.Error()
+	rawErr := p[OriginalErrorParam]
 
-			if strings.Contains(strError, "\n") {
-				strError = strings.ReplaceAll(err.Error(), "\n", "\n"+indentLevel)
-				strError = "\n" + indentLevel + strError // Add a leading newline
-			}
+	var strError string
+	if localizable, ok := rawErr.(Localizable); ok && locale != "" {
+		strError = localizable.LocalizedIndentError(locale, indentLevel)
+	} else if indentable, ok := rawErr.(ErrorIndentable); ok {
+		strError = indentable.IndentError(indentLevel)
+	} else if err, ok := rawErr.(error); ok {
+		strError = err.Error()
 
-			p2[OriginalErrorParam] = strError
+		if strings.Contains(strError, "\n") {
+			strError = strings.ReplaceAll(strError, "\n", "\n"+indentLevel)
+			strError = "\n" + indentLevel + strError // Add a leading newline
 		}
+	} else {
+		return p // Nothing to convert
 	}
 
+	p2 := p.Clone()
+	p2[OriginalErrorParam] = strError
 	return p2
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/JosiahWitt/ensure"
@@ -55,6 +56,14 @@ func TestWithParams(t *testing.T) {
 			err = erk.WithParams(err, erk.Params{"a": "hello", "b": "world"})
 			ensure(erk.GetParams(err)).Equals(erk.Params{"0": "hey", "1": "there", "a": "hello", "b": "world"})
 		})
+
+		ensure.Run("equals the error created with the params", func(ensure ensurepkg.Ensure) {
+			err := erk.WithParams(erk.New(ErkExample{}, "my {{.a}} message"), erk.Params{"a": "hello"})
+			expected := erk.NewWith(ErkExample{}, "my {{.a}} message", erk.Params{"a": "hello"})
+
+			ensure(reflect.DeepEqual(err, expected)).IsTrue()
+			ensure(err).Equals(expected)
+		})
 	})
 
 	ensure.Run("with non erk.Paramable", func(ensure ensurepkg.Ensure) {
@@ -155,3 +164,59 @@ func TestParamsMarshalJSON(t *testing.T) {
 		ensure(string(bytes)).Equals(`{"0":"hey","err":"my error"}`)
 	})
 }
+
+func BenchmarkWithParams(b *testing.B) {
+	withStrictMode(false, func() {
+		base := erk.NewWith(ErkExample{}, "failed at depth {{.depth}}: {{.err}}", erk.Params{"service": "api", "region": "us"})
+
+		buildChain := func(depth int) error {
+			err := erk.NewWith(ErkExample{}, "item {{.key}} was not found", erk.Params{"key": "abc"})
+			for i := 0; i < depth; i++ {
+				err = erk.WrapWith(base, err, erk.Params{"depth": i, "attempt": i % 3})
+				err = erk.WithParam(err, "requestID", "req-123")
+			}
+
+			return err
+		}
+
+		for _, depth := range []int{1, 10, 50} {
+			depth := depth
+
+			b.Run(fmt.Sprintf("build chain of depth %d", depth), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = buildChain(depth)
+				}
+			})
+
+			b.Run(fmt.Sprintf("unwrap chain of depth %d", depth), func(b *testing.B) {
+				err := buildChain(depth)
+				target := errors.New("not in chain")
+
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = errors.Is(err, target)
+				}
+			})
+
+			b.Run(fmt.Sprintf("render chain of depth %d", depth), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = buildChain(depth).Error()
+				}
+			})
+		}
+
+		b.Run("add params repeatedly", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err := base
+				for j := 0; j < 20; j++ {
+					err = erk.WithParam(err, "attempt", j)
+				}
+
+				_ = erk.GetParams(err)
+			}
+		})
+	})
+}
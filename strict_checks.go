@@ -0,0 +1,143 @@
+package erk
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/JosiahWitt/erk/erkstrict"
+)
+
+// strictViolation found by an optional strict mode check.
+type strictViolation struct {
+	check   erkstrict.Check
+	message string
+}
+
+// isStrictMode reports if strict mode is enabled for the package of the error's kind.
+func (e *Error) isStrictMode() bool {
+	return erkstrict.IsStrictModeFor(kindPackagePath(e.kind))
+}
+
+// reportStrictViolation to erkstrict, which panics, warns, or calls the handler, depending on the configuration for the package of the error's kind.
+func (e *Error) reportStrictViolation(check erkstrict.Check, details string) {
+	erkstrict.Report(erkstrict.Violation{
+		Check:       check,
+		PackagePath: kindPackagePath(e.kind),
+		Message:     buildStrictPanicMessage(details),
+		Err:         e,
+	})
+}
+
+// checkStrictParams runs the enabled optional strict mode checks on the params being set, and reports any violations.
+// See erkstrict.Check.
+func (e *Error) checkStrictParams(params Params) {
+	config := erkstrict.ConfigFor(kindPackagePath(e.kind))
+	if config.Level == erkstrict.LevelOff || len(config.Checks) == 0 {
+		return
+	}
+
+	violations := []strictViolation{}
+	set := make(Params, len(params))
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := params[key]
+		if value == nil {
+			continue // Deleted
+		}
+
+		set[key] = value
+		violations = append(violations, checkStrictParam(config, key, value)...)
+	}
+
+	if config.IsCheckEnabled(erkstrict.CheckUnusedParams) && !e.literal && !isBuiltInKind(e.kind) {
+		violations = append(violations, e.checkUnusedParams(set)...)
+	}
+
+	for _, violation := range violations {
+		e.reportStrictViolation(violation.check, fmt.Sprintf(
+			"Invalid error params:\n\tKind: %s\n\tTemplate: %s\n\tParams: %+v\n\tCheck: %s\n\tViolation: %s",
+			GetKindString(e),
+			e.message,
+			e.params.flatten(),
+			violation.check,
+			violation.message,
+		))
+	}
+}
+
+func checkStrictParam(config erkstrict.Config, key string, value interface{}) []strictViolation {
+	violations := []strictViolation{}
+
+	if key == OriginalErrorParam {
+		if _, ok := value.(error); !ok && config.IsCheckEnabled(erkstrict.CheckReservedErrParam) {
+			violations = append(violations, strictViolation{
+				check: erkstrict.CheckReservedErrParam,
+				message: fmt.Sprintf(
+					"the %q param is reserved for wrapped errors, but the value of type %T is not an error",
+					OriginalErrorParam, value,
+				),
+			})
+		}
+	} else if config.IsCheckEnabled(erkstrict.CheckJSONParams) {
+		// The wrapped error is exported as its message, so it is always valid JSON
+		if _, err := json.Marshal(value); err != nil {
+			violations = append(violations, strictViolation{
+				check:   erkstrict.CheckJSONParams,
+				message: fmt.Sprintf("the %q param cannot be marshalled to JSON: %v", key, err),
+			})
+		}
+	}
+
+	if config.IsCheckEnabled(erkstrict.CheckParamNames) && !erkstrict.IsValidParamName(key) {
+		violations = append(violations, strictViolation{
+			check:   erkstrict.CheckParamNames,
+			message: fmt.Sprintf("the %q param does not match the param naming convention", key),
+		})
+	}
+
+	return violations
+}
+
+// checkUnusedParams only checks the params being set, so params set by earlier calls are not reported again.
+func (e *Error) checkUnusedParams(params Params) []strictViolation {
+	info, err := parseMessageInfo(e.kind, e.message)
+	if err != nil {
+		return nil // Invalid templates are reported when they are parsed
+	}
+
+	violations := []strictViolation{}
+	for _, param := range validateParams(info.Params, params).Extra {
+		if param != OriginalErrorParam {
+			violations = append(violations, strictViolation{
+				check:   erkstrict.CheckUnusedParams,
+				message: fmt.Sprintf("the %q param is not referenced by the message template", param),
+			})
+		}
+	}
+
+	return violations
+}
+
+// isBuiltInKind reports if the kind is declared by erk.
+// Built in errors, such as those returned by Classify, include params describing the wrapped error that their messages do not reference.
+func isBuiltInKind(kind Kind) bool {
+	return kindPackagePath(kind) == reflect.TypeOf(Error{}).PkgPath()
+}
+
+// kindPackagePath returns the package path of the kind, which selects its strict mode configuration.
+// Nil kinds have an empty path, so they use the default configuration.
+func kindPackagePath(kind Kind) string {
+	if kind == nil {
+		return ""
+	}
+
+	return baseKindType(reflect.TypeOf(kind)).PkgPath()
+}
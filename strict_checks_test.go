@@ -0,0 +1,302 @@
+package erk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+	"github.com/JosiahWitt/erk/erkjson"
+	"github.com/JosiahWitt/erk/erkstrict"
+)
+
+func TestStrictChecks(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name      string
+		Checks    []erkstrict.Check
+		Message   string
+		Params    erk.Params
+		Violation string // Empty if no violation is expected
+
+		// Set if only WithParams should be tested, since NewWith does not delete nil params
+		OnlyWithParams bool
+	}{
+		{
+			Name:      "unused params when enabled",
+			Checks:    []erkstrict.Check{erkstrict.CheckUnusedParams},
+			Message:   "my {{.a}} message",
+			Params:    erk.Params{"a": 1, "b": 2},
+			Violation: `the "b" param is not referenced by the message template`,
+		},
+		{
+			Name:    "unused params when disabled",
+			Checks:  []erkstrict.Check{erkstrict.CheckReservedErrParam, erkstrict.CheckJSONParams, erkstrict.CheckParamNames},
+			Message: "my {{.a}} message",
+			Params:  erk.Params{"a": 1, "b": 2},
+		},
+		{
+			Name:    "unused wrapped error",
+			Checks:  []erkstrict.Check{erkstrict.CheckUnusedParams},
+			Message: "my {{.a}} message",
+			Params:  erk.Params{"a": 1, "err": errors.New("wrapped")},
+		},
+		{
+			Name:      "reserved err param with non-error value when enabled",
+			Checks:    []erkstrict.Check{erkstrict.CheckReservedErrParam},
+			Message:   "my {{.err}} message",
+			Params:    erk.Params{"err": "not an error"},
+			Violation: `the "err" param is reserved for wrapped errors, but the value of type string is not an error`,
+		},
+		{
+			Name:    "reserved err param with non-error value when disabled",
+			Checks:  []erkstrict.Check{erkstrict.CheckUnusedParams, erkstrict.CheckJSONParams, erkstrict.CheckParamNames},
+			Message: "my {{.err}} message",
+			Params:  erk.Params{"err": "not an error"},
+		},
+		{
+			Name:    "reserved err param with error value",
+			Checks:  erkstrict.AllChecks(),
+			Message: "my {{.err}} message",
+			Params:  erk.Params{"err": errors.New("wrapped")},
+		},
+		{
+			Name:      "non-JSON param when enabled",
+			Checks:    []erkstrict.Check{erkstrict.CheckJSONParams},
+			Message:   "my {{.a}} message",
+			Params:    erk.Params{"a": make(chan int)},
+			Violation: `the "a" param cannot be marshalled to JSON: json: unsupported type: chan int`,
+		},
+		{
+			Name:    "non-JSON param when disabled",
+			Checks:  []erkstrict.Check{erkstrict.CheckUnusedParams, erkstrict.CheckReservedErrParam, erkstrict.CheckParamNames},
+			Message: "my {{.a}} message",
+			Params:  erk.Params{"a": make(chan int)},
+		},
+		{
+			Name:      "invalid param name when enabled",
+			Checks:    []erkstrict.Check{erkstrict.CheckParamNames},
+			Message:   "my {{.user_id}} message",
+			Params:    erk.Params{"user_id": 1},
+			Violation: `the "user_id" param does not match the param naming convention`,
+		},
+		{
+			Name:    "invalid param name when disabled",
+			Checks:  []erkstrict.Check{erkstrict.CheckUnusedParams, erkstrict.CheckReservedErrParam, erkstrict.CheckJSONParams},
+			Message: "my {{.user_id}} message",
+			Params:  erk.Params{"user_id": 1},
+		},
+		{
+			Name:    "valid params with all checks",
+			Checks:  erkstrict.AllChecks(),
+			Message: "my {{.a}} message {{.userID}}",
+			Params:  erk.Params{"a": 1, "userID": "abc"},
+		},
+		{
+			Name:           "deleted param with all checks",
+			Checks:         erkstrict.AllChecks(),
+			Message:        "my {{.a}} message",
+			Params:         erk.Params{"a": 1, "b_c": nil},
+			OnlyWithParams: true,
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		erkstrict.EnableChecks(entry.Checks...)
+		defer erkstrict.DisableChecks(erkstrict.AllChecks()...)
+
+		assertViolation := func(ensure ensurepkg.Ensure, fn func()) {
+			defer func() {
+				res := recover()
+				if entry.Violation == "" {
+					ensure(res).IsNil()
+					return
+				}
+
+				str, ok := strictViolationMessage(res)
+				ensure(ok).IsTrue()
+				ensure(strings.Contains(str, "Invalid error params:")).IsTrue()
+				ensure(strings.Contains(str, "\tViolation: "+entry.Violation+"\n")).IsTrue()
+			}()
+
+			withStrictMode(true, fn)
+		}
+
+		ensure.Run("when using WithParams", func(ensure ensurepkg.Ensure) {
+			assertViolation(ensure, func() {
+				err := erk.New(ErkExample{}, entry.Message)
+				_ = erk.WithParams(err, entry.Params)
+			})
+		})
+
+		ensure.Run("when using NewWith", func(ensure ensurepkg.Ensure) {
+			if entry.OnlyWithParams {
+				return
+			}
+
+			assertViolation(ensure, func() {
+				_ = erk.NewWith(ErkExample{}, entry.Message, entry.Params)
+			})
+		})
+	})
+
+	ensure.Run("when not in strict mode", func(ensure ensurepkg.Ensure) {
+		erkstrict.EnableChecks(erkstrict.AllChecks()...)
+		defer erkstrict.DisableChecks(erkstrict.AllChecks()...)
+
+		err := erk.New(ErkExample{}, "my message")
+		err = erk.WithParams(err, erk.Params{"Bad_Name": make(chan int), "err": "not an error"})
+		ensure(err.Error()).Equals("my message")
+	})
+
+	ensure.Run("with built in errors", func(ensure ensurepkg.Ensure) {
+		erkstrict.EnableChecks(erkstrict.AllChecks()...)
+		defer erkstrict.DisableChecks(erkstrict.AllChecks()...)
+
+		_, openErr := os.Open("/does/not/exist")
+		jsonErr := json.Unmarshal([]byte(`{"a":}`), &struct{}{})
+		timeoutErr := &net.OpError{Op: "dial", Net: "tcp", Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 80}, Err: testTimeoutError{}}
+
+		withStrictMode(true, func() {
+			ensure(erk.ToErk(openErr).Error()).Equals(openErr.Error())
+			ensure(erk.ToErk(jsonErr).Error()).Equals(jsonErr.Error())
+			ensure(erk.ToErk(timeoutErr).Error()).Equals(timeoutErr.Error())
+			ensure(erk.FromPanic("my panic").Error()).Equals("panic: my panic")
+
+			imported, err := erkjson.ImportError([]byte(`{"kind":"my:kind","message":"my {{.a}} message","params":{"a":"hello","b":"world"}}`))
+			ensure(err).IsNotError()
+			ensure(imported.Error()).Equals("my {{.a}} message")
+		})
+	})
+}
+
+func TestStrictConfig(t *testing.T) {
+	ensure := ensure.New(t)
+
+	resetStrictMode := func() {
+		erkstrict.UnsetStrictMode()
+		erkstrict.SetStrictMode(false)
+	}
+
+	ensure.Run("when enabled for the package of the kind", func(ensure ensurepkg.Ensure) {
+		defer resetStrictMode()
+
+		erkstrict.Configure(erkstrict.Config{Level: erkstrict.LevelOff})
+		erkstrict.ConfigurePackage("github.com/JosiahWitt/erk_test", erkstrict.Config{Level: erkstrict.LevelPanic})
+
+		defer func() {
+			str, ok := strictViolationMessage(recover())
+			ensure(ok).IsTrue()
+			ensure(strings.Contains(str, "Unable to execute error template")).IsTrue()
+		}()
+
+		_ = erk.New(ErkExample{}, "my {{.a}} message").Error()
+		ensure.Failf("Expected panic, so this line should not be reached")
+	})
+
+	ensure.Run("when disabled for the package of the kind", func(ensure ensurepkg.Ensure) {
+		defer resetStrictMode()
+
+		erkstrict.Configure(erkstrict.Config{Level: erkstrict.LevelPanic, Checks: erkstrict.AllChecks()})
+		erkstrict.ConfigurePackage("github.com/JosiahWitt/erk_test", erkstrict.Config{Level: erkstrict.LevelOff})
+
+		err := erk.New(ErkExample{}, "my {{.a}} message")
+		err = erk.WithParams(err, erk.Params{"Unused": make(chan int)})
+		ensure(err.Error()).Equals("my <no value> message")
+	})
+
+	ensure.Run("when disabled for the package of the kind with invalid template function arguments", func(ensure ensurepkg.Ensure) {
+		defer resetStrictMode()
+
+		erkstrict.Configure(erkstrict.Config{Level: erkstrict.LevelPanic})
+		erkstrict.ConfigurePackage("github.com/JosiahWitt/erk_test", erkstrict.Config{Level: erkstrict.LevelOff})
+
+		err := erk.WithParam(erk.New(ErkExample{}, "value {{truncate -1 .a}}"), "a", "hello")
+		ensure(err.Error()).Equals("value hello")
+	})
+
+	ensure.Run("when warning about invalid template function arguments", func(ensure ensurepkg.Ensure) {
+		defer resetStrictMode()
+
+		count := 0
+		erkstrict.ConfigurePackage("github.com/JosiahWitt/erk_test", erkstrict.Config{
+			Level:   erkstrict.LevelWarn,
+			Handler: func(erkstrict.Level, erkstrict.Violation) { count++ },
+		})
+
+		err := erk.WithParam(erk.New(ErkExample{}, "value {{truncate -1 .a}}"), "a", "hello")
+		ensure(err.Error()).Equals("value hello")
+		ensure(count).Equals(1)
+	})
+
+	ensure.Run("when warning with a handler", func(ensure ensurepkg.Ensure) {
+		defer resetStrictMode()
+
+		violations := []erkstrict.Violation{}
+		erkstrict.ConfigurePackage("github.com/JosiahWitt/erk_test", erkstrict.Config{
+			Level:  erkstrict.LevelWarn,
+			Checks: []erkstrict.Check{erkstrict.CheckUnusedParams, erkstrict.CheckParamNames},
+			Handler: func(level erkstrict.Level, v erkstrict.Violation) {
+				ensure(level).Equals(erkstrict.LevelWarn)
+				violations = append(violations, v)
+			},
+		})
+
+		err := erk.New(ErkExample{}, "my {{.a}} message")
+		err = erk.WithParams(err, erk.Params{"b_c": 1})
+		ensure(err.Error()).Equals("my <no value> message")
+
+		ensure(len(violations)).Equals(3)
+		ensure(violations[0].Check).Equals(erkstrict.CheckParamNames)
+		ensure(violations[1].Check).Equals(erkstrict.CheckUnusedParams)
+		ensure(violations[2].Check).Equals(erkstrict.Check(""))
+
+		for _, v := range violations {
+			ensure(v.PackagePath).Equals("github.com/JosiahWitt/erk_test")
+		}
+
+		ensure(strings.Contains(violations[2].Message, "Unable to execute error template")).IsTrue()
+	})
+
+	ensure.Run("when warning about unused params set by multiple calls", func(ensure ensurepkg.Ensure) {
+		defer resetStrictMode()
+
+		violations := []erkstrict.Violation{}
+		erkstrict.ConfigurePackage("github.com/JosiahWitt/erk_test", erkstrict.Config{
+			Level:   erkstrict.LevelWarn,
+			Checks:  []erkstrict.Check{erkstrict.CheckUnusedParams},
+			Handler: func(_ erkstrict.Level, v erkstrict.Violation) { violations = append(violations, v) },
+		})
+
+		err := erk.New(ErkExample{}, "my {{.a}} {{.b}} message")
+		err = erk.WithParams(err, erk.Params{"a": 1, "unused": 2})
+		err = erk.WithParams(err, erk.Params{"b": 3})
+		err = erk.WithParam(err, "a", 4)
+		ensure(err.Error()).Equals("my 4 3 message")
+
+		ensure(len(violations)).Equals(1)
+		ensure(strings.Contains(violations[0].Message, `the "unused" param is not referenced by the message template`)).IsTrue()
+	})
+
+	ensure.Run("when warning about an invalid template", func(ensure ensurepkg.Ensure) {
+		defer resetStrictMode()
+
+		count := 0
+		erkstrict.Configure(erkstrict.Config{
+			Level:   erkstrict.LevelWarn,
+			Handler: func(erkstrict.Level, erkstrict.Violation) { count++ },
+		})
+
+		err := erk.New(ErkExample{}, "my {{.a message")
+		ensure(err.Error()).Equals("my {{.a message")
+		ensure(count).Equals(2) // Once when created, and once when rendered
+	})
+}
@@ -1,24 +1,90 @@
 package erk
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
 	"text/template"
+	"time"
+	"unicode/utf8"
 )
 
-func templateFuncs(k Kind) template.FuncMap {
-	if funcs, ok := k.(interface{ TemplateFuncsFor(Kind) template.FuncMap }); ok {
-		return funcs.TemplateFuncsFor(k)
+// templateFuncs returns the template functions of the kind.
+// Since the default functions only fail on invalid arguments in strict mode, they are replaced by the strict versions in strict mode.
+func templateFuncs(k Kind, isStrictMode bool) template.FuncMap {
+	funcs := defaultTemplateFuncs
+	if kindFuncs, ok := k.(interface{ TemplateFuncsFor(Kind) template.FuncMap }); ok {
+		funcs = kindFuncs.TemplateFuncsFor(k)
 	}
 
-	return defaultTemplateFuncs
+	if !isStrictMode {
+		return funcs
+	}
+
+	strictFuncs := make(template.FuncMap, len(funcs))
+	for name, fn := range funcs {
+		if isSameTemplateFunc(fn, defaultTemplateFuncs[name]) {
+			fn = strictTemplateFuncs[name]
+		}
+
+		strictFuncs[name] = fn
+	}
+
+	return strictFuncs
+}
+
+// isSameTemplateFunc reports if the functions have the same code.
+// Functions bound to a different templateFuncMode have the same code, so a kind's copy of a default function is detected.
+func isSameTemplateFunc(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return false
+	}
+
+	aValue, bValue := reflect.ValueOf(a), reflect.ValueOf(b)
+	return aValue.Kind() == reflect.Func && bValue.Kind() == reflect.Func && aValue.Pointer() == bValue.Pointer()
 }
 
+// RedactedValue is the value rendered by the redact template function.
+const RedactedValue = "[REDACTED]"
+
 // Functions that are accessible from the error templates.
 //
+// All functions accept nil values.
+// In strict mode, invalid arguments cause the template to fail, so the mistake is caught.
+// Otherwise, a reasonable fallback is rendered.
+//
 //nolint:gochecknoglobals // Only read internally
-var defaultTemplateFuncs = template.FuncMap{
-	"type":    templateFuncType,
-	"inspect": templateFuncInspect,
+var (
+	defaultTemplateFuncs = newTemplateFuncs(templateFuncMode{isStrictMode: false})
+	strictTemplateFuncs  = newTemplateFuncs(templateFuncMode{isStrictMode: true})
+)
+
+// templateFuncMode determines how template functions handle invalid arguments.
+type templateFuncMode struct {
+	isStrictMode bool
+}
+
+func newTemplateFuncs(m templateFuncMode) template.FuncMap {
+	return template.FuncMap{
+		"type":     templateFuncType,
+		"inspect":  templateFuncInspect,
+		"quote":    templateFuncQuote,
+		"join":     templateFuncJoin,
+		"truncate": m.truncate,
+		"default":  templateFuncDefault,
+		"upper":    templateFuncUpper,
+		"lower":    templateFuncLower,
+		"json":     m.json,
+		"plural":   m.plural,
+		"len":      m.len,
+		"indent":   m.indent,
+		"duration": m.duration,
+		"bytes":    m.bytes,
+		"redact":   templateFuncRedact,
+	}
 }
 
 func templateFuncType(v interface{}) string {
@@ -28,3 +94,294 @@ func templateFuncType(v interface{}) string {
 func templateFuncInspect(v interface{}) string {
 	return fmt.Sprintf("%+v", v)
 }
+
+// templateFuncQuote returns the value as a double quoted Go string.
+func templateFuncQuote(v interface{}) string {
+	return strconv.Quote(templateString(v))
+}
+
+// templateFuncJoin joins the elements of a slice or array with the separator.
+// Other values are rendered as a single element.
+func templateFuncJoin(sep string, v interface{}) string {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return templateString(v)
+	}
+
+	elems := make([]string, value.Len())
+	for i := range elems {
+		elems[i] = templateString(value.Index(i).Interface())
+	}
+
+	return strings.Join(elems, sep)
+}
+
+// truncate shortens the value to the maximum number of characters, ending with "..." if it was shortened.
+func (m templateFuncMode) truncate(maxLength int, v interface{}) (string, error) {
+	const ellipsis = "..."
+
+	if maxLength < 0 {
+		return m.fallback(templateString(v), "truncate: the length %d must not be negative", maxLength)
+	}
+
+	s := templateString(v)
+	if utf8.RuneCountInString(s) <= maxLength {
+		return s, nil
+	}
+
+	if maxLength <= len(ellipsis) {
+		return string([]rune(s)[:maxLength]), nil
+	}
+
+	return string([]rune(s)[:maxLength-len(ellipsis)]) + ellipsis, nil
+}
+
+// templateFuncDefault returns the value, or the fallback if the value is nil, false, zero, or empty.
+// Missing params still fail in strict mode, since the template is executed with missingkey=error.
+func templateFuncDefault(fallback, v interface{}) interface{} {
+	if isEmptyTemplateValue(v) {
+		return fallback
+	}
+
+	return v
+}
+
+func templateFuncUpper(v interface{}) string {
+	return strings.ToUpper(templateString(v))
+}
+
+func templateFuncLower(v interface{}) string {
+	return strings.ToLower(templateString(v))
+}
+
+// json marshals the value to JSON.
+// Errors are marshalled as their message.
+func (m templateFuncMode) json(v interface{}) (string, error) {
+	if err, ok := v.(error); ok && err != nil {
+		v = err.Error()
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return m.fallback(templateFuncInspect(v), "json: %v", err)
+	}
+
+	return string(b), nil
+}
+
+// plural returns the first form if the count is 1, and the last form otherwise.
+//
+// Example: {{plural .count "item" "items"}}
+func (m templateFuncMode) plural(count interface{}, forms ...string) (string, error) {
+	if len(forms) == 0 {
+		return m.fallback("", "plural: at least one form is required")
+	}
+
+	if isNilTemplateValue(count) {
+		return forms[len(forms)-1], nil
+	}
+
+	n, ok := templateInt(count)
+	if !ok {
+		return m.fallback(forms[len(forms)-1], "plural: the count %s is not a number", templateFuncInspect(count))
+	}
+
+	if n == 1 || n == -1 {
+		return forms[0], nil
+	}
+
+	return forms[len(forms)-1], nil
+}
+
+// len returns the length of a string, slice, array, map, or channel.
+// Nil values have a length of zero.
+func (m templateFuncMode) len(v interface{}) (int, error) {
+	value := reflect.ValueOf(v)
+
+	switch value.Kind() { //nolint:exhaustive // Other kinds are handled by the default case
+	case reflect.Invalid:
+		return 0, nil
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return value.Len(), nil
+	case reflect.Ptr:
+		if value.IsNil() {
+			return 0, nil
+		}
+
+		return m.len(value.Elem().Interface())
+	default:
+		n, err := m.fallback("", "len: the value of type %T does not have a length", v)
+		return len(n), err
+	}
+}
+
+// indent indents each line of the value with the number of spaces.
+func (m templateFuncMode) indent(spaces int, v interface{}) (string, error) {
+	if spaces < 0 {
+		return m.fallback(templateString(v), "indent: the number of spaces %d must not be negative", spaces)
+	}
+
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(templateString(v), "\n", "\n"+pad), nil
+}
+
+// duration renders a time.Duration, or a number of seconds, in a human readable format.
+// Durations of at least a minute are rounded to the second, and shorter durations are rounded to the millisecond.
+//
+// Example: 1h30m, 1m5s, 1.5s, 150ms.
+func (m templateFuncMode) duration(v interface{}) (string, error) {
+	if isNilTemplateValue(v) {
+		return "", nil
+	}
+
+	var d time.Duration
+
+	switch value := v.(type) {
+	case time.Duration:
+		d = value
+	case *time.Duration:
+		d = *value
+	default:
+		seconds, ok := templateFloat(v)
+		if !ok {
+			return m.fallback(templateString(v), "duration: the value of type %T is not a duration", v)
+		}
+
+		d = time.Duration(seconds * float64(time.Second))
+	}
+
+	if d < 0 {
+		s, err := m.duration(-d)
+		return "-" + s, err
+	}
+
+	if d < time.Minute {
+		return d.Round(time.Millisecond).String(), nil
+	}
+
+	s := d.Round(time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+
+	return s, nil
+}
+
+// bytes renders a number of bytes in a human readable format, using binary units.
+//
+// Example: 512 B, 1.5 KiB, 20 MiB.
+func (m templateFuncMode) bytes(v interface{}) (string, error) {
+	if isNilTemplateValue(v) {
+		return "", nil
+	}
+
+	n, ok := templateFloat(v)
+	if !ok {
+		return m.fallback(templateString(v), "bytes: the value of type %T is not a number", v)
+	}
+
+	const unit = 1024
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+	i := 0
+	for math.Abs(n) >= unit && i < len(units)-1 {
+		n /= unit
+		i++
+	}
+
+	return strconv.FormatFloat(math.Round(n*10)/10, 'f', -1, 64) + " " + units[i], nil
+}
+
+// templateFuncRedact hides the value, so sensitive params can be referenced without exposing them in the message.
+// Nil and empty values render as an empty string.
+func templateFuncRedact(v interface{}) string {
+	if isEmptyTemplateValue(v) {
+		return ""
+	}
+
+	return RedactedValue
+}
+
+// fallback returns an error describing the invalid arguments in strict mode, or the fallback otherwise.
+func (m templateFuncMode) fallback(fallback string, format string, args ...interface{}) (string, error) {
+	if m.isStrictMode {
+		return "", fmt.Errorf(format, args...) //nolint:err113 // Only used as a template execution error
+	}
+
+	return fallback, nil
+}
+
+// templateString converts the value to a string, rendering nil values as an empty string.
+func templateString(v interface{}) string {
+	if isNilTemplateValue(v) {
+		return ""
+	}
+
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(v)
+}
+
+// isNilTemplateValue returns true if the value is nil, or a nil pointer, interface, map, slice, channel, or function.
+func isNilTemplateValue(v interface{}) bool {
+	value := reflect.ValueOf(v)
+
+	switch value.Kind() { //nolint:exhaustive // Other kinds cannot be nil
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+func isEmptyTemplateValue(v interface{}) bool {
+	value := reflect.ValueOf(v)
+
+	switch value.Kind() { //nolint:exhaustive // Other kinds are never empty
+	case reflect.Invalid:
+		return true
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return value.Len() == 0
+	case reflect.Ptr, reflect.Interface, reflect.Func:
+		return value.IsNil()
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return value.IsZero()
+	default:
+		return false
+	}
+}
+
+func templateInt(v interface{}) (int64, bool) {
+	n, ok := templateFloat(v)
+	return int64(n), ok
+}
+
+func templateFloat(v interface{}) (float64, bool) {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	switch value.Kind() { //nolint:exhaustive // Other kinds are not numbers
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	default:
+		return 0, false
+	}
+}
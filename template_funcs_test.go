@@ -1,9 +1,12 @@
 package erk_test
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/JosiahWitt/ensure"
 	"github.com/JosiahWitt/ensure/ensurepkg"
@@ -53,6 +56,128 @@ func TestTemplateFuncs(t *testing.T) {
 			ensure(err.Error()).Equals("my message: {Msg:hey Map:map[key:value]}")
 		})
 	})
+
+	var nilString *string
+
+	ensure.Run("with default template functions", func(ensure ensurepkg.Ensure) {
+		table := []struct {
+			Name     string
+			Message  string
+			Params   erk.Params
+			Expected string
+		}{
+			{Name: "quote", Message: "{{quote .a}}", Params: erk.Params{"a": `say "hi"`}, Expected: `"say \"hi\""`},
+			{Name: "quote nil", Message: "{{quote .a}}", Params: erk.Params{"a": nilString}, Expected: `""`},
+			{Name: "join strings", Message: `{{join ", " .a}}`, Params: erk.Params{"a": []string{"x", "y", "z"}}, Expected: "x, y, z"},
+			{Name: "join ints", Message: `{{join "-" .a}}`, Params: erk.Params{"a": []int{1, 2}}, Expected: "1-2"},
+			{Name: "join non slice", Message: `{{join ", " .a}}`, Params: erk.Params{"a": 42}, Expected: "42"},
+			{Name: "join nil", Message: `{{join ", " .a}}`, Params: erk.Params{"a": nilString}, Expected: ""},
+			{Name: "truncate short", Message: "{{truncate 10 .a}}", Params: erk.Params{"a": "short"}, Expected: "short"},
+			{Name: "truncate long", Message: "{{truncate 8 .a}}", Params: erk.Params{"a": "a long value"}, Expected: "a lon..."},
+			{Name: "truncate runes", Message: "{{truncate 4 .a}}", Params: erk.Params{"a": "héllo"}, Expected: "h..."},
+			{Name: "truncate tiny", Message: "{{truncate 2 .a}}", Params: erk.Params{"a": "hello"}, Expected: "he"},
+			{Name: "truncate nil", Message: "{{truncate 2 .a}}", Params: erk.Params{"a": nilString}, Expected: ""},
+			{Name: "default with value", Message: `{{default "none" .a}}`, Params: erk.Params{"a": "value"}, Expected: "value"},
+			{Name: "default with empty", Message: `{{default "none" .a}}`, Params: erk.Params{"a": ""}, Expected: "none"},
+			{Name: "default with nil", Message: `{{default "none" .a}}`, Params: erk.Params{"a": nilString}, Expected: "none"},
+			{Name: "default with zero", Message: `{{default 5 .a}}`, Params: erk.Params{"a": 0}, Expected: "5"},
+			{Name: "upper", Message: "{{upper .a}}", Params: erk.Params{"a": "Hello"}, Expected: "HELLO"},
+			{Name: "lower", Message: "{{lower .a}}", Params: erk.Params{"a": "Hello"}, Expected: "hello"},
+			{Name: "upper nil", Message: "{{upper .a}}", Params: erk.Params{"a": nilString}, Expected: ""},
+			{Name: "json map", Message: "{{json .a}}", Params: erk.Params{"a": map[string]int{"x": 1}}, Expected: `{"x":1}`},
+			{Name: "json nil", Message: "{{json .a}}", Params: erk.Params{"a": nilString}, Expected: "null"},
+			{Name: "json error", Message: "{{json .a}}", Params: erk.Params{"a": errors.New("oops")}, Expected: `"oops"`},
+			{Name: "json unsupported", Message: "{{json .a}}", Params: erk.Params{"a": make(chan int)}, Expected: "0x"},
+			{Name: "plural one", Message: `{{.a}} {{plural .a "item" "items"}}`, Params: erk.Params{"a": 1}, Expected: "1 item"},
+			{Name: "plural many", Message: `{{.a}} {{plural .a "item" "items"}}`, Params: erk.Params{"a": 3}, Expected: "3 items"},
+			{Name: "plural zero", Message: `{{.a}} {{plural .a "item" "items"}}`, Params: erk.Params{"a": uint(0)}, Expected: "0 items"},
+			{Name: "plural nil", Message: `{{plural .a "item" "items"}}`, Params: erk.Params{"a": nilString}, Expected: "items"},
+			{Name: "len string", Message: "{{len .a}}", Params: erk.Params{"a": "abc"}, Expected: "3"},
+			{Name: "len slice", Message: "{{len .a}}", Params: erk.Params{"a": []int{1, 2}}, Expected: "2"},
+			{Name: "len nil", Message: "{{len .a}}", Params: erk.Params{"a": nilString}, Expected: "0"},
+			{Name: "len nil pointer", Message: "{{len .a}}", Params: erk.Params{"a": (*[]int)(nil)}, Expected: "0"},
+			{Name: "len int", Message: "{{len .a}}", Params: erk.Params{"a": 5}, Expected: "0"},
+			{Name: "indent", Message: "details:\n{{indent 2 .a}}", Params: erk.Params{"a": "x\ny"}, Expected: "details:\n  x\n  y"},
+			{Name: "indent nil", Message: "{{indent 2 .a}}", Params: erk.Params{"a": nilString}, Expected: "  "},
+			{Name: "duration short", Message: "{{duration .a}}", Params: erk.Params{"a": 1500 * time.Millisecond}, Expected: "1.5s"},
+			{Name: "duration milliseconds", Message: "{{duration .a}}", Params: erk.Params{"a": 150*time.Millisecond + time.Microsecond}, Expected: "150ms"},
+			{Name: "duration minutes", Message: "{{duration .a}}", Params: erk.Params{"a": 65*time.Second + time.Millisecond}, Expected: "1m5s"},
+			{Name: "duration tens of seconds", Message: "{{duration .a}}", Params: erk.Params{"a": 70 * time.Second}, Expected: "1m10s"},
+			{Name: "duration even minutes", Message: "{{duration .a}}", Params: erk.Params{"a": 3 * time.Minute}, Expected: "3m"},
+			{Name: "duration hours", Message: "{{duration .a}}", Params: erk.Params{"a": 90 * time.Minute}, Expected: "1h30m"},
+			{Name: "duration even hours", Message: "{{duration .a}}", Params: erk.Params{"a": 2 * time.Hour}, Expected: "2h"},
+			{Name: "duration negative", Message: "{{duration .a}}", Params: erk.Params{"a": -2 * time.Second}, Expected: "-2s"},
+			{Name: "duration seconds", Message: "{{duration .a}}", Params: erk.Params{"a": 2.5}, Expected: "2.5s"},
+			{Name: "duration nil", Message: "{{duration .a}}", Params: erk.Params{"a": nilString}, Expected: ""},
+			{Name: "duration invalid", Message: "{{duration .a}}", Params: erk.Params{"a": "soon"}, Expected: "soon"},
+			{Name: "bytes", Message: "{{bytes .a}}", Params: erk.Params{"a": 512}, Expected: "512 B"},
+			{Name: "bytes kibibytes", Message: "{{bytes .a}}", Params: erk.Params{"a": 1536}, Expected: "1.5 KiB"},
+			{Name: "bytes mebibytes", Message: "{{bytes .a}}", Params: erk.Params{"a": int64(20 * 1024 * 1024)}, Expected: "20 MiB"},
+			{Name: "bytes nil", Message: "{{bytes .a}}", Params: erk.Params{"a": nilString}, Expected: ""},
+			{Name: "bytes invalid", Message: "{{bytes .a}}", Params: erk.Params{"a": "big"}, Expected: "big"},
+			{Name: "redact", Message: "token {{redact .a}}", Params: erk.Params{"a": "secret"}, Expected: "token [REDACTED]"},
+			{Name: "redact empty", Message: "token {{redact .a}}", Params: erk.Params{"a": ""}, Expected: "token "},
+			{Name: "redact nil", Message: "token {{redact .a}}", Params: erk.Params{"a": nilString}, Expected: "token "},
+		}
+
+		ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+			entry := table[i]
+
+			err := erk.New(ErkExample{}, entry.Message)
+			err = erk.WithParams(err, entry.Params)
+
+			if strings.HasSuffix(entry.Expected, "0x") {
+				ensure(strings.HasPrefix(err.Error(), entry.Expected)).IsTrue()
+				return
+			}
+
+			ensure(err.Error()).Equals(entry.Expected)
+		})
+	})
+
+	ensure.Run("with default template functions in strict mode", func(ensure ensurepkg.Ensure) {
+		table := []struct {
+			Name    string
+			Message string
+			Params  erk.Params
+		}{
+			{Name: "truncate negative", Message: "{{truncate -1 .a}}", Params: erk.Params{"a": "x"}},
+			{Name: "json unsupported", Message: "{{json .a}}", Params: erk.Params{"a": make(chan int)}},
+			{Name: "plural without forms", Message: "{{plural .a}}", Params: erk.Params{"a": 1}},
+			{Name: "plural not a number", Message: `{{plural .a "x" "y"}}`, Params: erk.Params{"a": "one"}},
+			{Name: "len int", Message: "{{len .a}}", Params: erk.Params{"a": 5}},
+			{Name: "indent negative", Message: "{{indent -1 .a}}", Params: erk.Params{"a": "x"}},
+			{Name: "duration invalid", Message: "{{duration .a}}", Params: erk.Params{"a": "soon"}},
+			{Name: "bytes invalid", Message: "{{bytes .a}}", Params: erk.Params{"a": "big"}},
+		}
+
+		ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+			entry := table[i]
+
+			defer func() {
+				res := recover()
+				str, ok := strictViolationMessage(res)
+				ensure(ok).IsTrue()
+				ensure(strings.Contains(str, "Unable to execute error template")).IsTrue()
+			}()
+
+			err := erk.New(ErkExample{}, entry.Message)
+			err = erk.WithParams(err, entry.Params)
+			withStrictMode(true, func() { _ = err.Error() }) // Used to trigger panic
+			ensure.Failf("Expected panic, so this line should not be reached")
+		})
+	})
+
+	ensure.Run("with nil safe functions in strict mode", func(ensure ensurepkg.Ensure) {
+		msg := `{{quote .a}}|{{join "," .a}}|{{truncate 3 .a}}|{{default "x" .a}}|{{upper .a}}|{{json .a}}|` +
+			`{{plural .a "one" "other"}}|{{len .a}}|{{indent 1 .a}}|{{duration .a}}|{{bytes .a}}|{{redact .a}}`
+		err := erk.New(ErkExample{}, msg)
+		err = erk.WithParam(err, "a", nilString)
+
+		withStrictMode(true, func() {
+			ensure(err.Error()).Equals(`""|||x||null|other|0| |||`)
+		})
+	})
 }
 
 type ErkSimple struct{}
@@ -0,0 +1,255 @@
+package erk
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"text/template/parse"
+)
+
+// ErkTemplate is the kind of errors returned when inspecting message templates.
+type ErkTemplate struct{ DefaultKind }
+
+// KindStringFor the provided kind.
+func (ErkTemplate) KindStringFor(Kind) string {
+	return "erk:template"
+}
+
+// ErrTemplateInvalid is returned when a message template cannot be parsed.
+var ErrTemplateInvalid = New(ErkTemplate{}, "invalid message template {{quote .message}}: {{.err}}")
+
+// TemplateInfo describes what a message template references.
+type TemplateInfo struct {
+	// Params are the sorted names of the params referenced by the template.
+	// Only params referenced from the root of the template are included, so fields inside range and with blocks are ignored,
+	// unless they are referenced using $.
+	Params []string
+
+	// ParamPaths are the sorted paths of the referenced params, including field accesses.
+	// For example, {{.user.Name}} references the path "user.Name", and the param "user".
+	ParamPaths []string
+
+	// Funcs are the sorted names of the functions called by the template, including built in functions.
+	Funcs []string
+}
+
+// TemplateValidation reports the differences between the params referenced by an error's message template and the params it has.
+type TemplateValidation struct {
+	// Missing params are referenced by the template, but not set on the error.
+	Missing []string
+
+	// Extra params are set on the error, but not referenced by the template.
+	Extra []string
+}
+
+// IsValid returns true if no params are missing.
+// Extra params do not cause the template to fail, so they are allowed.
+func (v *TemplateValidation) IsValid() bool {
+	return len(v.Missing) == 0
+}
+
+// ParseTemplate returns the params and functions referenced by the message template.
+// Functions are not required to exist, so templates using functions from any kind can be parsed.
+func ParseTemplate(message string) (*TemplateInfo, error) {
+	info, err := parseTemplateInfo(message)
+	if err != nil {
+		return nil, WrapWith(ErrTemplateInvalid, err, Params{"message": message})
+	}
+
+	return info, nil
+}
+
+// ParseMessage is like ParseTemplate, but the message is parsed using the message format.
+// This is useful for tooling that knows the message format of a kind, but cannot create an error with it.
+func ParseMessage(message string, format MessageFormat) (*TemplateInfo, error) {
+	info, err := parseFormatInfo(format, message)
+	if err != nil {
+		return nil, WrapWith(ErrTemplateInvalid, err, Params{"message": message})
+	}
+
+	return info, nil
+}
+
+func parseTemplateInfo(message string) (*TemplateInfo, error) {
+	trees, err := parseTemplateTrees(message)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &templateWalker{
+		params: map[string]struct{}{},
+		paths:  map[string]struct{}{},
+		funcs:  map[string]struct{}{},
+	}
+
+	for _, tree := range trees {
+		w.walk(tree.Root, true)
+	}
+
+	return &TemplateInfo{
+		Params:     sortedKeys(w.params),
+		ParamPaths: sortedKeys(w.paths),
+		Funcs:      sortedKeys(w.funcs),
+	}, nil
+}
+
+// GetTemplateInfo returns the params and functions referenced by the error's message template, using ExportRawMessage.
+// The message is parsed using the message format of the error's kind (see MessageFormat).
+// If err does not satisfy Exportable, or has a literal message (see NewLiteral), an empty TemplateInfo is returned.
+func GetTemplateInfo(err error) (*TemplateInfo, error) {
+	var exportable Exportable
+	if !errors.As(err, &exportable) {
+		return &TemplateInfo{Params: []string{}, ParamPaths: []string{}, Funcs: []string{}}, nil
+	}
+
+	if e, ok := exportable.(*Error); ok && e.literal {
+		return &TemplateInfo{Params: []string{}, ParamPaths: []string{}, Funcs: []string{}}, nil
+	}
+
+	message := exportable.ExportRawMessage()
+	info, parseErr := parseMessageInfo(GetKind(err), message)
+	if parseErr != nil {
+		return nil, WrapWith(ErrTemplateInvalid, parseErr, Params{"message": message})
+	}
+
+	return info, nil
+}
+
+// Validate compares the params referenced by the error's message template to the params set on the error, without rendering it.
+// Unlike rendering in strict mode, it does not panic, so it is safe to use in tooling and tests.
+//
+// An error is returned if the message template cannot be parsed.
+func Validate(err error) (*TemplateValidation, error) {
+	info, infoErr := GetTemplateInfo(err)
+	if infoErr != nil {
+		return nil, infoErr
+	}
+
+	return validateParams(info.Params, GetParams(err)), nil
+}
+
+func validateParams(referenced []string, params Params) *TemplateValidation {
+	validation := &TemplateValidation{Missing: []string{}, Extra: []string{}}
+
+	isReferenced := make(map[string]struct{}, len(referenced))
+	for _, param := range referenced {
+		isReferenced[param] = struct{}{}
+
+		if _, ok := params[param]; !ok {
+			validation.Missing = append(validation.Missing, param)
+		}
+	}
+
+	for param := range params {
+		if _, ok := isReferenced[param]; !ok {
+			validation.Extra = append(validation.Extra, param)
+		}
+	}
+
+	sort.Strings(validation.Extra)
+	return validation
+}
+
+func parseTemplateTrees(message string) (map[string]*parse.Tree, error) {
+	tree := parse.New("message")
+	tree.Mode = parse.SkipFuncCheck
+
+	trees := map[string]*parse.Tree{}
+	if _, err := tree.Parse(message, "{{", "}}", trees); err != nil {
+		return nil, err //nolint:wrapcheck // Wrapped by the caller
+	}
+
+	return trees, nil
+}
+
+type templateWalker struct {
+	params map[string]struct{}
+	paths  map[string]struct{}
+	funcs  map[string]struct{}
+}
+
+// walk the node, where dotIsRoot reports if dot refers to the params.
+//
+//nolint:cyclop // Switching over each node type is clearest
+func (w *templateWalker) walk(node parse.Node, dotIsRoot bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			w.walk(child, dotIsRoot)
+		}
+
+	case *parse.ActionNode:
+		w.walk(n.Pipe, dotIsRoot)
+
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+
+		for _, cmd := range n.Cmds {
+			w.walk(cmd, dotIsRoot)
+		}
+
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			w.walk(arg, dotIsRoot)
+		}
+
+	case *parse.ChainNode:
+		w.walk(n.Node, dotIsRoot)
+
+	case *parse.IdentifierNode:
+		w.funcs[n.Ident] = struct{}{}
+
+	case *parse.FieldNode:
+		if dotIsRoot {
+			w.addParam(n.Ident)
+		}
+
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			w.addParam(n.Ident[1:])
+		}
+
+	case *parse.IfNode:
+		w.walkBranch(&n.BranchNode, dotIsRoot, dotIsRoot)
+
+	case *parse.WithNode:
+		w.walkBranch(&n.BranchNode, dotIsRoot, false)
+
+	case *parse.RangeNode:
+		w.walkBranch(&n.BranchNode, dotIsRoot, false)
+
+	case *parse.TemplateNode:
+		w.walk(n.Pipe, dotIsRoot)
+	}
+}
+
+func (w *templateWalker) walkBranch(n *parse.BranchNode, dotIsRoot, listDotIsRoot bool) {
+	w.walk(n.Pipe, dotIsRoot)
+	w.walk(n.List, listDotIsRoot)
+	w.walk(n.ElseList, dotIsRoot)
+}
+
+func (w *templateWalker) addParam(ident []string) {
+	if len(ident) == 0 {
+		return
+	}
+
+	w.params[ident[0]] = struct{}{}
+	w.paths[strings.Join(ident, ".")] = struct{}{}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
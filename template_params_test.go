@@ -0,0 +1,178 @@
+package erk_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JosiahWitt/ensure"
+	"github.com/JosiahWitt/ensure/ensurepkg"
+	"github.com/JosiahWitt/erk"
+)
+
+func TestParseTemplate(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name     string
+		Message  string
+		Expected *erk.TemplateInfo
+		Error    error
+	}{
+		{
+			Name:     "with no params",
+			Message:  "my message",
+			Expected: &erk.TemplateInfo{Params: []string{}, ParamPaths: []string{}, Funcs: []string{}},
+		},
+		{
+			Name:    "with params and fields",
+			Message: "{{.b}} and {{.a.Name}} and {{.a.ID}} and {{.b}}",
+			Expected: &erk.TemplateInfo{
+				Params:     []string{"a", "b"},
+				ParamPaths: []string{"a.ID", "a.Name", "b"},
+				Funcs:      []string{},
+			},
+		},
+		{
+			Name:    "with functions",
+			Message: `{{quote .a}} {{.b | printf "%d"}} {{custom .c}}`,
+			Expected: &erk.TemplateInfo{
+				Params:     []string{"a", "b", "c"},
+				ParamPaths: []string{"a", "b", "c"},
+				Funcs:      []string{"custom", "printf", "quote"},
+			},
+		},
+		{
+			Name:    "with range and with blocks",
+			Message: `{{range .items}}{{.Name}} {{$.prefix}}{{end}}{{with .user}}{{.ID}}{{else}}{{.fallback}}{{end}}{{if .ok}}{{.yes}}{{end}}`,
+			Expected: &erk.TemplateInfo{
+				Params:     []string{"fallback", "items", "ok", "prefix", "user", "yes"},
+				ParamPaths: []string{"fallback", "items", "ok", "prefix", "user", "yes"},
+				Funcs:      []string{},
+			},
+		},
+		{
+			Name:    "with invalid template",
+			Message: "{{.a",
+			Error:   erk.ErrTemplateInvalid,
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		info, err := erk.ParseTemplate(entry.Message)
+		ensure(err).IsError(entry.Error)
+		ensure(info).Equals(entry.Expected)
+	})
+}
+
+func TestParseMessage(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with template format", func(ensure ensurepkg.Ensure) {
+		info, err := erk.ParseMessage("my {{.a}} message {b}", erk.MessageFormatTemplate)
+		ensure(err).IsNotError()
+		ensure(info).Equals(&erk.TemplateInfo{Params: []string{"a"}, ParamPaths: []string{"a"}, Funcs: []string{}})
+	})
+
+	ensure.Run("with placeholder format", func(ensure ensurepkg.Ensure) {
+		info, err := erk.ParseMessage("my {{.a}} message {b}", erk.MessageFormatPlaceholder)
+		ensure(err).IsNotError()
+		ensure(info).Equals(&erk.TemplateInfo{Params: []string{"b"}, ParamPaths: []string{"b"}, Funcs: []string{}})
+	})
+
+	ensure.Run("with invalid placeholder message", func(ensure ensurepkg.Ensure) {
+		info, err := erk.ParseMessage("my {a message", erk.MessageFormatPlaceholder)
+		ensure(err).IsError(erk.ErrTemplateInvalid)
+		ensure(info).IsNil()
+	})
+}
+
+func TestGetTemplateInfo(t *testing.T) {
+	ensure := ensure.New(t)
+
+	ensure.Run("with erk error", func(ensure ensurepkg.Ensure) {
+		err := erk.New(ErkExample{}, "my {{.a}} message {{upper .b}}")
+
+		info, infoErr := erk.GetTemplateInfo(err)
+		ensure(infoErr).IsNotError()
+		ensure(info).Equals(&erk.TemplateInfo{
+			Params:     []string{"a", "b"},
+			ParamPaths: []string{"a", "b"},
+			Funcs:      []string{"upper"},
+		})
+	})
+
+	ensure.Run("with literal message", func(ensure ensurepkg.Ensure) {
+		info, infoErr := erk.GetTemplateInfo(erk.NewLiteral(ErkExample{}, "my {{.a}} message {{", nil))
+		ensure(infoErr).IsNotError()
+		ensure(info).Equals(&erk.TemplateInfo{Params: []string{}, ParamPaths: []string{}, Funcs: []string{}})
+	})
+
+	ensure.Run("with non-erk error", func(ensure ensurepkg.Ensure) {
+		info, infoErr := erk.GetTemplateInfo(errors.New("{{.a}}"))
+		ensure(infoErr).IsNotError()
+		ensure(info).Equals(&erk.TemplateInfo{Params: []string{}, ParamPaths: []string{}, Funcs: []string{}})
+	})
+}
+
+func TestValidate(t *testing.T) {
+	ensure := ensure.New(t)
+
+	table := []struct {
+		Name          string
+		Message       string
+		Params        erk.Params
+		Expected      *erk.TemplateValidation
+		ExpectedValid bool
+		Error         error
+	}{
+		{
+			Name:          "with all params",
+			Message:       "my {{.a}} message {{.b}}",
+			Params:        erk.Params{"a": 1, "b": 2},
+			Expected:      &erk.TemplateValidation{Missing: []string{}, Extra: []string{}},
+			ExpectedValid: true,
+		},
+		{
+			Name:          "with missing params",
+			Message:       "my {{.a}} message {{.b}} {{.c}}",
+			Params:        erk.Params{"b": 2},
+			Expected:      &erk.TemplateValidation{Missing: []string{"a", "c"}, Extra: []string{}},
+			ExpectedValid: false,
+		},
+		{
+			Name:          "with extra params",
+			Message:       "my {{.a}} message",
+			Params:        erk.Params{"a": 1, "z": 3, "y": 2},
+			Expected:      &erk.TemplateValidation{Missing: []string{}, Extra: []string{"y", "z"}},
+			ExpectedValid: true,
+		},
+		{
+			Name:    "with invalid template",
+			Message: "my {{.a message",
+			Params:  erk.Params{"a": 1},
+			Error:   erk.ErrTemplateInvalid,
+		},
+	}
+
+	ensure.RunTableByIndex(table, func(ensure ensurepkg.Ensure, i int) {
+		entry := table[i]
+
+		err := erk.NewWith(ErkExample{}, entry.Message, entry.Params)
+		withStrictMode(true, func() {
+			// Strict mode panics when parsing invalid templates, so only build the error in strict mode if it is valid
+			if entry.Error == nil {
+				err = erk.NewWith(ErkExample{}, entry.Message, entry.Params)
+			}
+
+			validation, validateErr := erk.Validate(err) // Does not panic for missing params
+			ensure(validateErr).IsError(entry.Error)
+			ensure(validation).Equals(entry.Expected)
+
+			if validation != nil {
+				ensure(validation.IsValid()).Equals(entry.ExpectedValid)
+			}
+		})
+	})
+}